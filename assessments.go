@@ -3,7 +3,9 @@ package xbow
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"iter"
+	"slices"
 	"time"
 
 	"github.com/rsclarke/xbow/internal/api"
@@ -15,12 +17,16 @@ type AssessmentsService struct {
 }
 
 // Get retrieves an assessment by ID.
-func (s *AssessmentsService) Get(ctx context.Context, id string) (*Assessment, error) {
+func (s *AssessmentsService) Get(ctx context.Context, id string, reqOpts ...RequestOption) (*Assessment, error) {
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
 		return nil, err
 	}
 
+	rc := newRequestConfig(reqOpts)
+	ctx, cancel := rc.context(ctx)
+	defer cancel()
+
 	opts := &api.GetAPIV1AssessmentsAssessmentIDRequestOptions{
 		PathParams: &api.GetAPIV1AssessmentsAssessmentIDPath{
 			AssessmentID: id,
@@ -30,16 +36,81 @@ func (s *AssessmentsService) Get(ctx context.Context, id string) (*Assessment, e
 		},
 	}
 
-	resp, err := s.client.raw.GetAPIV1AssessmentsAssessmentID(ctx, opts, auth)
+	ctx, rid := recordRequestID(ctx)
+	resp, err := s.client.raw.GetAPIV1AssessmentsAssessmentID(ctx, opts, rc.editors(auth)...)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assessmentFromGetResponse(resp), nil
 }
 
+// terminalAssessmentStates are the states WaitForState waits for when no
+// target is given. See AssessmentState.IsTerminal.
+var terminalAssessmentStates = []AssessmentState{
+	AssessmentStateSucceeded,
+	AssessmentStateFailed,
+	AssessmentStateCancelled,
+	AssessmentStateReportReady,
+}
+
+// WaitForState polls an assessment until its State matches one of target,
+// and returns the assessment at that point. With no target it waits for a
+// terminal state: succeeded, failed, cancelled or report-ready.
+//
+// Polls start at the client's poll interval (see WithPollInterval) and back
+// off up to one minute apart. WaitForState returns early with the context's
+// error if ctx is cancelled or its deadline passes, or with the error from
+// Get if a poll fails.
+func (s *AssessmentsService) WaitForState(ctx context.Context, id string, target ...AssessmentState) (*Assessment, error) {
+	if len(target) == 0 {
+		target = terminalAssessmentStates
+	}
+
+	get := func(ctx context.Context) (*Assessment, error) { return s.Get(ctx, id) }
+	for assessment, err := range poll(ctx, s.client, get, s.client.pollInterval, true) {
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(target, assessment.State) {
+			return assessment, nil
+		}
+	}
+	// poll only stops after yielding an error.
+	return nil, ctx.Err()
+}
+
+// Watch polls an assessment every interval and yields each result, stopping
+// after the first terminal state (succeeded, failed, cancelled or
+// report-ready). An interval of zero uses the client's poll interval.
+//
+// Unlike WaitForState the interval does not back off, so callers can show
+// progress at a steady rate. Iteration ends with the context's error if ctx
+// is cancelled, or with the error from Get if a poll fails.
+func (s *AssessmentsService) Watch(ctx context.Context, id string, interval time.Duration) iter.Seq2[*Assessment, error] {
+	if interval <= 0 {
+		interval = s.client.pollInterval
+	}
+	get := func(ctx context.Context) (*Assessment, error) { return s.Get(ctx, id) }
+	return func(yield func(*Assessment, error) bool) {
+		for assessment, err := range poll(ctx, s.client, get, interval, false) {
+			if !yield(assessment, err) || err != nil {
+				return
+			}
+			if assessment.State.IsTerminal() {
+				return
+			}
+		}
+	}
+}
+
+// MaxAttackCredits is the largest AttackCredits value the API accepts
+// (2^53-1, the largest integer JSON numbers represent exactly).
+const MaxAttackCredits int64 = 1<<53 - 1
+
 // CreateAssessmentRequest specifies the parameters for creating an assessment.
 type CreateAssessmentRequest struct {
+	// AttackCredits must be between 1 and MaxAttackCredits.
 	AttackCredits int64
 	Objective     *string
 }
@@ -49,6 +120,12 @@ func (s *AssessmentsService) Create(ctx context.Context, assetID string, req *Cr
 	if req == nil {
 		return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: "CreateAssessmentRequest cannot be nil"}
 	}
+	if req.AttackCredits <= 0 || req.AttackCredits > MaxAttackCredits {
+		return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("AttackCredits must be between 1 and %d", MaxAttackCredits)}
+	}
+	if int64(int(req.AttackCredits)) != req.AttackCredits {
+		return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: "AttackCredits exceeds the maximum int on this platform"}
+	}
 
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
@@ -68,9 +145,10 @@ func (s *AssessmentsService) Create(ctx context.Context, assetID string, req *Cr
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1AssetsAssetIDAssessments(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assessmentFromCreateResponse(resp), nil
@@ -102,9 +180,10 @@ func (s *AssessmentsService) ListByAsset(ctx context.Context, assetID string, op
 		}
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1AssetsAssetIDAssessments(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assessmentsPageFromResponse(resp), nil
@@ -141,22 +220,79 @@ func (s *AssessmentsService) Cancel(ctx context.Context, id string) (*Assessment
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1AssessmentsAssessmentIDCancel(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assessmentFromCancelResponse(resp), nil
 }
 
+// PauseOptions configures Pause.
+type PauseOptions struct {
+	// CheckState fetches the assessment first and returns
+	// ErrInvalidStateTransition without calling the pause endpoint if its
+	// current state cannot be paused. It costs an extra request.
+	CheckState bool
+}
+
+// PauseOption sets a field of PauseOptions.
+type PauseOption func(*PauseOptions)
+
+// WithPauseStateCheck sets PauseOptions.CheckState.
+func WithPauseStateCheck() PauseOption {
+	return func(o *PauseOptions) {
+		o.CheckState = true
+	}
+}
+
+// assessmentPausable records, for each AssessmentState, whether an
+// assessment in that state can be paused. Only assessments that are queued
+// or running can be paused; finished, cancelling and already-paused
+// assessments cannot.
+var assessmentPausable = map[AssessmentState]bool{
+	AssessmentStateWaitingForCapacity:   true,
+	AssessmentStateRunning:              true,
+	AssessmentStateWaitingForTimeWindow: true,
+	AssessmentStateSucceeded:            false,
+	AssessmentStateReportReady:          false,
+	AssessmentStateFailed:               false,
+	AssessmentStateCancelling:           false,
+	AssessmentStateCancelled:            false,
+	AssessmentStatePaused:               false,
+}
+
 // Pause pauses a running assessment.
-func (s *AssessmentsService) Pause(ctx context.Context, id string) (*Assessment, error) {
+//
+// Example:
+//
+//	a, err := client.Assessments.Pause(ctx, id, xbow.WithPauseStateCheck())
+func (s *AssessmentsService) Pause(ctx context.Context, id string, opts ...PauseOption) (*Assessment, error) {
+	var o PauseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.CheckState {
+		current, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !assessmentPausable[current.State] {
+			return nil, &Error{
+				Code:    "ERR_INVALID_STATE_TRANSITION",
+				Message: fmt.Sprintf("cannot pause assessment in state %q", current.State),
+				Wrapped: ErrInvalidStateTransition,
+			}
+		}
+	}
+
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
 		return nil, err
 	}
 
-	opts := &api.PostAPIV1AssessmentsAssessmentIDPauseRequestOptions{
+	reqOpts := &api.PostAPIV1AssessmentsAssessmentIDPauseRequestOptions{
 		PathParams: &api.PostAPIV1AssessmentsAssessmentIDPausePath{
 			AssessmentID: id,
 		},
@@ -165,9 +301,10 @@ func (s *AssessmentsService) Pause(ctx context.Context, id string) (*Assessment,
 		},
 	}
 
-	resp, err := s.client.raw.PostAPIV1AssessmentsAssessmentIDPause(ctx, opts, auth)
+	ctx, rid := recordRequestID(ctx)
+	resp, err := s.client.raw.PostAPIV1AssessmentsAssessmentIDPause(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assessmentFromPauseResponse(resp), nil
@@ -189,9 +326,10 @@ func (s *AssessmentsService) Resume(ctx context.Context, id string) (*Assessment
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1AssessmentsAssessmentIDResume(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assessmentFromResumeResponse(resp), nil
@@ -342,7 +480,12 @@ func convertRecentEvents[Item any](items []Item, getOneOf func(Item) rawUnion) [
 		if err := json.Unmarshal(oneOf.Raw(), &ev); err != nil {
 			continue
 		}
-		result = append(result, AssessmentEvent(ev))
+		result = append(result, AssessmentEvent{
+			Name:      ev.Name,
+			Type:      AssessmentEventType(ev.Name),
+			Timestamp: ev.Timestamp,
+			Reason:    ev.Reason,
+		})
 	}
 	return result
 }
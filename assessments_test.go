@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -294,6 +298,50 @@ func TestConvertRecentEvents(t *testing.T) {
 		}
 	})
 
+	t.Run("typed variants", func(t *testing.T) {
+		tests := []struct {
+			raw        string
+			wantType   AssessmentEventType
+			wantReason string
+		}{
+			{`{"name":"paused","timestamp":"2025-06-15T12:00:00Z"}`, AssessmentEventTypePaused, ""},
+			{`{"name":"auto-paused","timestamp":"2025-06-15T12:00:00Z","reason":"waf-blocked"}`, AssessmentEventTypeAutoPaused, "waf-blocked"},
+			{`{"name":"resumed","timestamp":"2025-06-15T12:00:00Z"}`, AssessmentEventTypeResumed, ""},
+		}
+		for _, tt := range tests {
+			got := convertRecentEvents([]fakeItem{{oneOf: &fakeOneOf{data: json.RawMessage(tt.raw)}}}, getOneOf)
+			if len(got) != 1 {
+				t.Fatalf("len = %d, want 1", len(got))
+			}
+			ev := got[0]
+			if ev.Type != tt.wantType || !ev.Type.IsValid() {
+				t.Errorf("Type = %q, want %q", ev.Type, tt.wantType)
+			}
+			if ev.Name != string(tt.wantType) {
+				t.Errorf("Name = %q, want %q", ev.Name, tt.wantType)
+			}
+			if !ev.Timestamp.Equal(now) {
+				t.Errorf("Timestamp = %v, want %v", ev.Timestamp, now)
+			}
+			if ev.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", ev.Reason, tt.wantReason)
+			}
+		}
+	})
+
+	t.Run("unknown event kept", func(t *testing.T) {
+		items := []fakeItem{
+			{oneOf: &fakeOneOf{data: json.RawMessage(`{"name":"rescheduled","timestamp":"2025-06-15T12:00:00Z"}`)}},
+		}
+		got := convertRecentEvents(items, getOneOf)
+		if len(got) != 1 {
+			t.Fatalf("len = %d, want 1", len(got))
+		}
+		if got[0].Name != "rescheduled" || got[0].Type.IsValid() {
+			t.Errorf("got Name = %q, Type = %q (valid %v)", got[0].Name, got[0].Type, got[0].Type.IsValid())
+		}
+	})
+
 	t.Run("nil oneOf skipped", func(t *testing.T) {
 		items := []fakeItem{
 			{oneOf: nil},
@@ -342,3 +390,227 @@ func TestConvertRecentEvents(t *testing.T) {
 		}
 	})
 }
+
+// testAssessmentJSON returns an assessment response body in the given state.
+func testAssessmentJSON(state AssessmentState) string {
+	return fmt.Sprintf(`{
+		"id": "assess-123",
+		"name": "Test Assessment",
+		"assetId": "asset-456",
+		"organizationId": "org-789",
+		"state": %q,
+		"progress": 0.5,
+		"attackCredits": 100,
+		"recentEvents": [],
+		"createdAt": "2026-01-01T00:00:00Z",
+		"updatedAt": "2026-01-01T00:00:00Z"
+	}`, state)
+}
+
+// assessmentStatesHandler serves each state in turn for successive requests,
+// repeating the last one once exhausted.
+func assessmentStatesHandler(calls *atomic.Int32, states ...AssessmentState) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(calls.Add(1)) - 1
+		if i >= len(states) {
+			i = len(states) - 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testAssessmentJSON(states[i])))
+	})
+}
+
+func TestWaitForState(t *testing.T) {
+	t.Run("returns once terminal state is reached", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t,
+			assessmentStatesHandler(&calls, AssessmentStateRunning, AssessmentStateRunning, AssessmentStateSucceeded),
+			WithPollInterval(time.Millisecond),
+		)
+
+		got, err := client.Assessments.WaitForState(context.Background(), "assess-123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.State != AssessmentStateSucceeded {
+			t.Errorf("State = %q, want %q", got.State, AssessmentStateSucceeded)
+		}
+		if n := calls.Load(); n != 3 {
+			t.Errorf("polls = %d, want 3", n)
+		}
+	})
+
+	t.Run("waits for explicit target", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t,
+			assessmentStatesHandler(&calls, AssessmentStateWaitingForCapacity, AssessmentStateRunning),
+			WithPollInterval(time.Millisecond),
+		)
+
+		got, err := client.Assessments.WaitForState(context.Background(), "assess-123", AssessmentStateRunning, AssessmentStatePaused)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.State != AssessmentStateRunning {
+			t.Errorf("State = %q, want %q", got.State, AssessmentStateRunning)
+		}
+	})
+
+	t.Run("honors context cancellation", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t,
+			assessmentStatesHandler(&calls, AssessmentStateRunning),
+			WithPollInterval(time.Millisecond),
+		)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Assessments.WaitForState(ctx, "assess-123")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want context.DeadlineExceeded", err)
+		}
+		if n := calls.Load(); n < 2 {
+			t.Errorf("polls = %d, want at least 2", n)
+		}
+	})
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("yields each poll until terminal", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t,
+			assessmentStatesHandler(&calls, AssessmentStateWaitingForCapacity, AssessmentStateRunning, AssessmentStateFailed, AssessmentStateRunning),
+		)
+
+		var states []AssessmentState
+		for a, err := range client.Assessments.Watch(context.Background(), "assess-123", time.Millisecond) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			states = append(states, a.State)
+		}
+
+		want := []AssessmentState{AssessmentStateWaitingForCapacity, AssessmentStateRunning, AssessmentStateFailed}
+		if !slices.Equal(states, want) {
+			t.Errorf("states = %v, want %v", states, want)
+		}
+		if n := calls.Load(); n != 3 {
+			t.Errorf("polls = %d, want 3", n)
+		}
+	})
+
+	t.Run("ends with context error", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, assessmentStatesHandler(&calls, AssessmentStateRunning))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var lastErr error
+		for a, err := range client.Assessments.Watch(ctx, "assess-123", time.Millisecond) {
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if a.State == AssessmentStateRunning {
+				cancel()
+			}
+		}
+		if !errors.Is(lastErr, context.Canceled) {
+			t.Errorf("error = %v, want context.Canceled", lastErr)
+		}
+	})
+}
+
+func TestPauseCheckState(t *testing.T) {
+	t.Run("pauses running assessment", func(t *testing.T) {
+		var paused bool
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodPost && r.URL.Path == "/api/v1/assessments/assess-123/pause" {
+				paused = true
+				_, _ = w.Write([]byte(testAssessmentJSON(AssessmentStatePaused)))
+				return
+			}
+			_, _ = w.Write([]byte(testAssessmentJSON(AssessmentStateRunning)))
+		}))
+
+		got, err := client.Assessments.Pause(context.Background(), "assess-123", WithPauseStateCheck())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !paused {
+			t.Error("expected pause endpoint to be called")
+		}
+		if got.State != AssessmentStatePaused {
+			t.Errorf("State = %q, want %q", got.State, AssessmentStatePaused)
+		}
+	})
+
+	t.Run("rejects succeeded assessment", func(t *testing.T) {
+		var paused bool
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				paused = true
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(testAssessmentJSON(AssessmentStateSucceeded)))
+		}))
+
+		_, err := client.Assessments.Pause(context.Background(), "assess-123", WithPauseStateCheck())
+		if !errors.Is(err, ErrInvalidStateTransition) {
+			t.Fatalf("err = %v, want ErrInvalidStateTransition", err)
+		}
+		if paused {
+			t.Error("pause endpoint should not have been called")
+		}
+	})
+
+	t.Run("table covers every state", func(t *testing.T) {
+		states := []AssessmentState{
+			AssessmentStateWaitingForCapacity, AssessmentStateRunning, AssessmentStateSucceeded,
+			AssessmentStateReportReady, AssessmentStateFailed, AssessmentStateCancelling,
+			AssessmentStateCancelled, AssessmentStatePaused, AssessmentStateWaitingForTimeWindow,
+		}
+		for _, state := range states {
+			if _, ok := assessmentPausable[state]; !ok {
+				t.Errorf("assessmentPausable missing %q", state)
+			}
+		}
+	})
+}
+
+func TestCreateAttackCreditsValidation(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testAssessmentJSON(AssessmentStateWaitingForCapacity)))
+	}))
+
+	tests := []struct {
+		name    string
+		credits int64
+		wantErr bool
+	}{
+		{"zero", 0, true},
+		{"negative", -1, true},
+		{"one", 1, false},
+		{"max", MaxAttackCredits, false},
+		{"max plus one", MaxAttackCredits + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Assessments.Create(context.Background(), "asset-456", &CreateAssessmentRequest{AttackCredits: tt.credits})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_REQUEST" {
+				t.Errorf("expected ERR_INVALID_REQUEST, got %v", err)
+			}
+		})
+	}
+}
@@ -3,8 +3,13 @@ package xbow
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"iter"
+	"maps"
+	"net/textproto"
+	"net/url"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -57,9 +62,10 @@ func (s *AssetsService) Get(ctx context.Context, id string) (*Asset, error) {
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1AssetsAssetID(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assetFromGetResponse(resp), nil
@@ -78,11 +84,26 @@ type UpdateAssetRequest struct {
 	HTTPBoundaryRules    []HTTPBoundaryRule   `json:"httpBoundaryRules"`
 }
 
-// Update updates an asset.
+// Update updates an asset. Header names in req.Headers are canonicalized
+// (see net/http.CanonicalHeaderKey) and values of names that differ only in
+// case are merged; a name that is not a valid HTTP token is rejected with
+// ERR_INVALID_REQUEST before any request is made.
 func (s *AssetsService) Update(ctx context.Context, id string, req *UpdateAssetRequest) (*Asset, error) {
 	if req == nil {
 		return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: "UpdateAssetRequest cannot be nil"}
 	}
+	headers, err := normalizeHeaders(req.Headers)
+	if err != nil {
+		return nil, err
+	}
+	if req.ApprovedTimeWindows != nil {
+		if err := req.ApprovedTimeWindows.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateBoundaryRules(req.DNSBoundaryRules, req.HTTPBoundaryRules); err != nil {
+		return nil, err
+	}
 
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
@@ -104,19 +125,213 @@ func (s *AssetsService) Update(ctx context.Context, id string, req *UpdateAssetR
 			ApprovedTimeWindows:  convertApprovedTimeWindowsToBody(req.ApprovedTimeWindows),
 			Credentials:          convertCredentialsToBody(req.Credentials),
 			DNSBoundaryRules:     convertDNSBoundaryRulesToBody(req.DNSBoundaryRules),
-			Headers:              convertHeadersToBody(req.Headers),
+			Headers:              convertHeadersToBody(headers),
 			HTTPBoundaryRules:    convertHTTPBoundaryRulesToBody(req.HTTPBoundaryRules),
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PutAPIV1AssetsAssetID(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assetFromPutResponse(resp), nil
 }
 
+// Validate checks r the way the API would, so a mistake in a hand-written
+// request is reported before any request is made: Name is required,
+// StartURL, when set, must be an absolute http or https URL,
+// MaxRequestsPerSecond must not be negative, and header names, time windows
+// and boundary rules must be well formed. The error is an *Error with code
+// ERR_INVALID_REQUEST whose message starts with the JSON path of the first
+// problem, such as "httpBoundaryRules[1].type".
+func (r *UpdateAssetRequest) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf(format, args...)}
+	}
+	if r.Name == "" {
+		return invalid("name is required")
+	}
+	if r.StartURL != "" {
+		u, err := url.Parse(r.StartURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalid("startUrl: must be an absolute http or https URL, got %q", r.StartURL)
+		}
+	}
+	if r.MaxRequestsPerSecond < 0 {
+		return invalid("maxRequestsPerSecond: must not be negative, got %d", r.MaxRequestsPerSecond)
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+		if !validHeaderName(name) {
+			return invalid("headers[%q]: not a valid HTTP header name", name)
+		}
+	}
+	if r.ApprovedTimeWindows != nil {
+		if err := r.ApprovedTimeWindows.Validate(); err != nil {
+			return err
+		}
+	}
+	return validateBoundaryRules(r.DNSBoundaryRules, r.HTTPBoundaryRules)
+}
+
+// AllSettled reports whether every check has finished, so none is
+// unchecked or checking. Settled checks may still be invalid. A nil
+// *AssetChecks has not settled.
+func (c *AssetChecks) AllSettled() bool {
+	if c == nil {
+		return false
+	}
+	for _, check := range []AssetCheck{c.AssetReachable, c.Credentials, c.DNSBoundaryRules} {
+		if check.State == AssetCheckStateUnchecked || check.State == AssetCheckStateChecking {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitForChecks polls an asset until its checks have settled (see
+// AssetChecks.AllSettled), and returns the asset at that point. The API
+// runs checks in the background after an asset is created or updated, so
+// wait for them before starting an assessment. Whether they passed is left
+// to the caller.
+//
+// Polls start at the client's poll interval (see WithPollInterval) and back
+// off up to one minute apart. WaitForChecks returns early with the
+// context's error if ctx is cancelled or its deadline passes, or with the
+// error from Get if a poll fails.
+func (s *AssetsService) WaitForChecks(ctx context.Context, id string) (*Asset, error) {
+	get := func(ctx context.Context) (*Asset, error) { return s.Get(ctx, id) }
+	for asset, err := range poll(ctx, s.client, get, s.client.pollInterval, true) {
+		if err != nil {
+			return nil, err
+		}
+		if asset.Checks.AllSettled() {
+			return asset, nil
+		}
+	}
+	// poll only stops after yielding an error.
+	return nil, ctx.Err()
+}
+
+// SetMaxRequestsPerSecond changes only the asset's MaxRequestsPerSecond.
+// It is shorthand for Patch with just that field set.
+func (s *AssetsService) SetMaxRequestsPerSecond(ctx context.Context, id string, rps int) (*Asset, error) {
+	return s.Patch(ctx, id, &PatchAssetRequest{MaxRequestsPerSecond: &rps})
+}
+
+// PatchAssetRequest specifies a partial asset update. Only non-nil fields
+// are changed; the rest keep their current values. Set a slice or map field
+// to a pointer to an empty value to clear it.
+type PatchAssetRequest struct {
+	Name                 *string              `json:"name,omitempty"`
+	StartURL             *string              `json:"startUrl,omitempty"`
+	MaxRequestsPerSecond *int                 `json:"maxRequestsPerSecond,omitempty"`
+	Sku                  *string              `json:"sku,omitempty"`
+	ApprovedTimeWindows  *ApprovedTimeWindows `json:"approvedTimeWindows,omitempty"`
+	Credentials          *[]Credential        `json:"credentials,omitempty"`
+	DNSBoundaryRules     *[]DNSBoundaryRule   `json:"dnsBoundaryRules,omitempty"`
+	Headers              *map[string][]string `json:"headers,omitempty"`
+	HTTPBoundaryRules    *[]HTTPBoundaryRule  `json:"httpBoundaryRules,omitempty"`
+}
+
+// Patch changes only the fields set in req.
+//
+// The API has no partial update for assets, so Patch fetches the current
+// asset, applies req to it and submits the result with Update. The fetch
+// and the write are back to back, which keeps the window for clobbering a
+// concurrent change far smaller than an edit made between separate Get and
+// Update calls, but does not close it.
+func (s *AssetsService) Patch(ctx context.Context, id string, req *PatchAssetRequest) (*Asset, error) {
+	if id == "" {
+		return nil, &Error{Code: "ERR_INVALID_PARAM", Message: "asset id is required"}
+	}
+	if req == nil {
+		return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: "PatchAssetRequest cannot be nil"}
+	}
+	if req.MaxRequestsPerSecond != nil && *req.MaxRequestsPerSecond <= 0 {
+		return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: "maxRequestsPerSecond must be greater than 0"}
+	}
+	if req.Headers != nil {
+		if _, err := normalizeHeaders(*req.Headers); err != nil {
+			return nil, err
+		}
+	}
+	if req.ApprovedTimeWindows != nil {
+		if err := req.ApprovedTimeWindows.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var dnsRules []DNSBoundaryRule
+	var httpRules []HTTPBoundaryRule
+	if req.DNSBoundaryRules != nil {
+		dnsRules = *req.DNSBoundaryRules
+	}
+	if req.HTTPBoundaryRules != nil {
+		httpRules = *req.HTTPBoundaryRules
+	}
+	if err := validateBoundaryRules(dnsRules, httpRules); err != nil {
+		return nil, err
+	}
+
+	current, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := updateRequestFromAsset(current)
+	req.apply(update)
+
+	return s.Update(ctx, id, update)
+}
+
+// apply overwrites the fields of u that are set in p.
+func (p *PatchAssetRequest) apply(u *UpdateAssetRequest) {
+	if p.Name != nil {
+		u.Name = *p.Name
+	}
+	if p.StartURL != nil {
+		u.StartURL = *p.StartURL
+	}
+	if p.MaxRequestsPerSecond != nil {
+		u.MaxRequestsPerSecond = *p.MaxRequestsPerSecond
+	}
+	if p.Sku != nil {
+		u.Sku = p.Sku
+	}
+	if p.ApprovedTimeWindows != nil {
+		u.ApprovedTimeWindows = p.ApprovedTimeWindows
+	}
+	if p.Credentials != nil {
+		u.Credentials = *p.Credentials
+	}
+	if p.DNSBoundaryRules != nil {
+		u.DNSBoundaryRules = *p.DNSBoundaryRules
+	}
+	if p.Headers != nil {
+		u.Headers = *p.Headers
+	}
+	if p.HTTPBoundaryRules != nil {
+		u.HTTPBoundaryRules = *p.HTTPBoundaryRules
+	}
+}
+
+// updateRequestFromAsset builds an UpdateAssetRequest that re-submits the
+// asset's current state unchanged.
+func updateRequestFromAsset(a *Asset) *UpdateAssetRequest {
+	return &UpdateAssetRequest{
+		Name:                 a.Name,
+		StartURL:             ptrValue(a.StartURL),
+		MaxRequestsPerSecond: ptrValue(a.MaxRequestsPerSecond),
+		Sku:                  &a.Sku,
+		ApprovedTimeWindows:  a.ApprovedTimeWindows,
+		Credentials:          a.Credentials,
+		DNSBoundaryRules:     a.DNSBoundaryRules,
+		Headers:              a.Headers,
+		HTTPBoundaryRules:    a.HTTPBoundaryRules,
+	}
+}
+
 // CreateAssetRequest specifies the parameters for creating an asset.
 type CreateAssetRequest struct {
 	Name string
@@ -147,9 +362,10 @@ func (s *AssetsService) Create(ctx context.Context, organizationID string, req *
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1OrganizationsOrganizationIDAssets(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assetFromCreateResponse(resp), nil
@@ -181,9 +397,10 @@ func (s *AssetsService) ListByOrganization(ctx context.Context, organizationID s
 		}
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1OrganizationsOrganizationIDAssets(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assetsPageFromResponse(resp), nil
@@ -231,6 +448,9 @@ func structToMap(v reflect.Value) any {
 		if v.Type() == reflect.TypeOf(time.Time{}) {
 			return v.Interface()
 		}
+		if isUnionStruct(v) {
+			return unionToAny(v)
+		}
 		m := make(map[string]any)
 		t := v.Type()
 		for i := range t.NumField() {
@@ -269,6 +489,45 @@ func structToMap(v reflect.Value) any {
 	}
 }
 
+// isUnionStruct reports whether v is a generated union wrapper: a struct
+// whose variants are all hidden behind `json:"-"` and which relies on a
+// custom MarshalJSON.
+func isUnionStruct(v reflect.Value) bool {
+	if _, ok := v.Interface().(json.Marshaler); !ok {
+		return false
+	}
+	t := v.Type()
+	for i := range t.NumField() {
+		if t.Field(i).IsExported() && t.Field(i).Tag.Get("json") != "-" {
+			return false
+		}
+	}
+	return true
+}
+
+// unionToAny decodes a generated union wrapper through its MarshalJSON.
+// Unset unions become nil.
+func unionToAny(v reflect.Value) any {
+	set := false
+	for i := range v.NumField() {
+		if f := v.Field(i); f.Kind() == reflect.Ptr && !f.IsNil() {
+			set = true
+		}
+	}
+	if !set {
+		return nil
+	}
+	b, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return nil
+	}
+	var out any
+	if json.Unmarshal(b, &out) != nil {
+		return nil
+	}
+	return out
+}
+
 func assetFromGetResponse(r *api.GetAPIV1AssetsAssetIDResponse) *Asset {
 	return assetFromJSON(r)
 }
@@ -555,6 +814,49 @@ func convertDNSBoundaryRulesToBody(rules []DNSBoundaryRule) api.PutAPIV1AssetsAs
 	return result
 }
 
+// normalizeHeaders canonicalizes header names, as net/http does, so that
+// "content-type" and "Content-Type" name the same header. Values of names
+// that differ only in case are merged, in sorted order of the original
+// names. A name that is not a valid HTTP token is rejected.
+func normalizeHeaders(headers map[string][]string) (map[string][]string, error) {
+	if len(headers) == 0 {
+		return headers, nil
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		if !validHeaderName(name) {
+			return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("invalid header name %q", name)}
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	result := make(map[string][]string, len(headers))
+	for _, name := range names {
+		key := textproto.CanonicalMIMEHeaderKey(name)
+		result[key] = append(result[key], headers[name]...)
+	}
+	return result, nil
+}
+
+// validHeaderName reports whether name is a non-empty RFC 9110 token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func convertHeadersToBody(headers map[string][]string) map[string]api.PutAPIV1AssetsAssetIDBody_Headers_AnyOf_AdditionalProperties {
 	if len(headers) == 0 {
 		return nil
@@ -613,3 +915,114 @@ func assetsPageFromResponse(r *api.GetAPIV1OrganizationsOrganizationIDAssetsResp
 		},
 	}
 }
+
+// Validate checks w the way the API would, so a bad window is reported
+// before any request is made: Tz must be an IANA time zone name, weekdays
+// run from 1 (Monday) to 7 (Sunday), and times are 24-hour "HH:MM". The
+// error is an *Error with code ERR_INVALID_REQUEST naming the offending
+// entry and field.
+func (w *ApprovedTimeWindows) Validate() error {
+	if w.Tz == "" {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: "approvedTimeWindows.tz is required"}
+	}
+	if _, err := time.LoadLocation(w.Tz); err != nil {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("approvedTimeWindows.tz: unknown time zone %q", w.Tz)}
+	}
+	for i, e := range w.Entries {
+		invalid := func(field, format string, args ...any) error {
+			return &Error{
+				Code:    "ERR_INVALID_REQUEST",
+				Message: fmt.Sprintf("approvedTimeWindows.entries[%d].%s: ", i, field) + fmt.Sprintf(format, args...),
+			}
+		}
+		if e.StartWeekday < 1 || e.StartWeekday > 7 {
+			return invalid("startWeekday", "must be 1 (Monday) to 7 (Sunday), got %d", e.StartWeekday)
+		}
+		if !validClockTime(e.StartTime) {
+			return invalid("startTime", "must be HH:MM, got %q", e.StartTime)
+		}
+		if e.EndWeekday < 1 || e.EndWeekday > 7 {
+			return invalid("endWeekday", "must be 1 (Monday) to 7 (Sunday), got %d", e.EndWeekday)
+		}
+		if !validClockTime(e.EndTime) {
+			return invalid("endTime", "must be HH:MM, got %q", e.EndTime)
+		}
+	}
+	return nil
+}
+
+// validClockTime reports whether s is a 24-hour "HH:MM" time of day.
+func validClockTime(s string) bool {
+	if len(s) != 5 || s[2] != ':' {
+		return false
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	hour := int(s[0]-'0')*10 + int(s[1]-'0')
+	minute := int(s[3]-'0')*10 + int(s[4]-'0')
+	return hour < 24 && minute < 60
+}
+
+// dnsBoundaryRuleTypes and httpBoundaryRuleTypes list the filter types the
+// API accepts for each kind of boundary rule.
+var (
+	dnsBoundaryRuleTypes  = []string{"glob"}
+	httpBoundaryRuleTypes = []string{"contains", "exact", "glob", "prefix", "regexp"}
+)
+
+// Validate checks r's action and type the way the API would, so a typo is
+// reported before any request is made. allow-auth is rejected: only HTTP
+// boundary rules support it. The error is an *Error with code
+// ERR_INVALID_REQUEST naming the offending field.
+func (r DNSBoundaryRule) Validate() error {
+	return r.validate("dnsBoundaryRule")
+}
+
+func (r DNSBoundaryRule) validate(field string) error {
+	if r.Action == DNSBoundaryRuleAction(HTTPBoundaryRuleActionAllowAuth) {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: field + ".action: allow-auth is only supported on HTTP boundary rules"}
+	}
+	if !r.Action.IsValid() {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("%s.action: must be one of %s, got %q", field, joinValues(DNSBoundaryRuleActionValues()), r.Action)}
+	}
+	if !slices.Contains(dnsBoundaryRuleTypes, r.Type) {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("%s.type: must be one of %s, got %q", field, strings.Join(dnsBoundaryRuleTypes, ", "), r.Type)}
+	}
+	return nil
+}
+
+// Validate checks r's action and type the way the API would, so a typo is
+// reported before any request is made. The error is an *Error with code
+// ERR_INVALID_REQUEST naming the offending field.
+func (r HTTPBoundaryRule) Validate() error {
+	return r.validate("httpBoundaryRule")
+}
+
+func (r HTTPBoundaryRule) validate(field string) error {
+	if !r.Action.IsValid() {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("%s.action: must be one of %s, got %q", field, joinValues(HTTPBoundaryRuleActionValues()), r.Action)}
+	}
+	if !slices.Contains(httpBoundaryRuleTypes, r.Type) {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("%s.type: must be one of %s, got %q", field, strings.Join(httpBoundaryRuleTypes, ", "), r.Type)}
+	}
+	return nil
+}
+
+// validateBoundaryRules validates every rule, naming failures by their
+// index in the request.
+func validateBoundaryRules(dnsRules []DNSBoundaryRule, httpRules []HTTPBoundaryRule) error {
+	for i, r := range dnsRules {
+		if err := r.validate(fmt.Sprintf("dnsBoundaryRules[%d]", i)); err != nil {
+			return err
+		}
+	}
+	for i, r := range httpRules {
+		if err := r.validate(fmt.Sprintf("httpBoundaryRules[%d]", i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -676,6 +680,74 @@ func TestConvertHeadersToBody(t *testing.T) {
 	})
 }
 
+func TestNormalizeHeaders(t *testing.T) {
+	t.Run("canonicalizes and merges case variants", func(t *testing.T) {
+		got, err := normalizeHeaders(map[string][]string{
+			"content-type": {"text/plain"},
+			"Content-Type": {"application/json"},
+			"x-single":     {"1"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string][]string{
+			"Content-Type": {"application/json", "text/plain"},
+			"X-Single":     {"1"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		// Merged values are sent as the multi-value (B) variant.
+		body := convertHeadersToBody(got)
+		if anyOf := body["Content-Type"].PutAPIV1AssetsAssetIDBody_Headers_AnyOf_AdditionalProperties_AnyOf; !anyOf.IsB() || len(anyOf.B) != 2 {
+			t.Errorf("Content-Type = %+v, want two values as B", anyOf)
+		}
+		if anyOf := body["X-Single"].PutAPIV1AssetsAssetIDBody_Headers_AnyOf_AdditionalProperties_AnyOf; !anyOf.IsA() {
+			t.Errorf("X-Single = %+v, want A", anyOf)
+		}
+	})
+
+	t.Run("rejects illegal names", func(t *testing.T) {
+		for _, name := range []string{"", "Bad Header", "X-Bad:", "X-Ünicode", "X-New\nline"} {
+			_, err := normalizeHeaders(map[string][]string{name: {"v"}})
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_REQUEST" {
+				t.Errorf("normalizeHeaders(%q) error = %v, want ERR_INVALID_REQUEST", name, err)
+			}
+		}
+	})
+}
+
+func TestUpdateAssetMergesHeaders(t *testing.T) {
+	var putBody map[string]any
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&putBody); err != nil {
+			t.Errorf("decoding PUT body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testAssetJSON))
+	}))
+
+	_, err := client.Assets.Update(context.Background(), "asset-123", &UpdateAssetRequest{
+		Name:                 "Asset",
+		StartURL:             "https://example.com",
+		MaxRequestsPerSecond: 10,
+		Headers:              map[string][]string{"x-token": {"a"}, "X-Token": {"b"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headers, _ := putBody["headers"].(map[string]any)
+	if len(headers) != 1 {
+		t.Fatalf("headers = %v, want one merged key", putBody["headers"])
+	}
+	if got, _ := headers["X-Token"].([]any); len(got) != 2 {
+		t.Errorf("X-Token = %v, want two values", headers["X-Token"])
+	}
+}
+
 func TestConvertHTTPBoundaryRulesToBody(t *testing.T) {
 	t.Run("converts rules", func(t *testing.T) {
 		rules := []HTTPBoundaryRule{
@@ -728,6 +800,181 @@ func TestUpdateAssetNilRequest(t *testing.T) {
 	}
 }
 
+func TestApprovedTimeWindowsValidate(t *testing.T) {
+	valid := TimeWindowEntry{StartWeekday: 1, StartTime: "09:00", EndWeekday: 5, EndTime: "17:30"}
+	with := func(f func(e *TimeWindowEntry)) []TimeWindowEntry {
+		e := valid
+		f(&e)
+		return []TimeWindowEntry{valid, e}
+	}
+
+	tests := []struct {
+		name    string
+		windows ApprovedTimeWindows
+		want    string // substring of the error message; empty for valid
+	}{
+		{"valid", ApprovedTimeWindows{Tz: "Europe/London", Entries: []TimeWindowEntry{valid, {StartWeekday: 7, StartTime: "00:00", EndWeekday: 7, EndTime: "23:59"}}}, ""},
+		{"no entries", ApprovedTimeWindows{Tz: "UTC"}, ""},
+		{"missing tz", ApprovedTimeWindows{Entries: []TimeWindowEntry{valid}}, "approvedTimeWindows.tz is required"},
+		{"unknown tz", ApprovedTimeWindows{Tz: "Mars/Olympus", Entries: []TimeWindowEntry{valid}}, `unknown time zone "Mars/Olympus"`},
+		{"start weekday zero", ApprovedTimeWindows{Tz: "UTC", Entries: with(func(e *TimeWindowEntry) { e.StartWeekday = 0 })}, "entries[1].startWeekday"},
+		{"end weekday eight", ApprovedTimeWindows{Tz: "UTC", Entries: with(func(e *TimeWindowEntry) { e.EndWeekday = 8 })}, "entries[1].endWeekday"},
+		{"start time hour", ApprovedTimeWindows{Tz: "UTC", Entries: with(func(e *TimeWindowEntry) { e.StartTime = "24:00" })}, "entries[1].startTime"},
+		{"start time short", ApprovedTimeWindows{Tz: "UTC", Entries: with(func(e *TimeWindowEntry) { e.StartTime = "9:00" })}, "entries[1].startTime"},
+		{"end time minute", ApprovedTimeWindows{Tz: "UTC", Entries: with(func(e *TimeWindowEntry) { e.EndTime = "17:60" })}, "entries[1].endTime"},
+		{"end time seconds", ApprovedTimeWindows{Tz: "UTC", Entries: with(func(e *TimeWindowEntry) { e.EndTime = "17:00:00" })}, "entries[1].endTime"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.windows.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_REQUEST" {
+				t.Fatalf("error = %v, want ERR_INVALID_REQUEST", err)
+			}
+			if !strings.Contains(apiErr.Message, tt.want) {
+				t.Errorf("message = %q, want it to contain %q", apiErr.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateAssetRejectsInvalidTimeWindows(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+
+	_, err := client.Assets.Update(context.Background(), "asset-123", &UpdateAssetRequest{
+		Name:                 "Asset",
+		StartURL:             "https://example.com",
+		MaxRequestsPerSecond: 10,
+		ApprovedTimeWindows: &ApprovedTimeWindows{
+			Tz:      "UTC",
+			Entries: []TimeWindowEntry{{StartWeekday: 0, StartTime: "09:00", EndWeekday: 5, EndTime: "17:00"}},
+		},
+	})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_REQUEST" {
+		t.Fatalf("error = %v, want ERR_INVALID_REQUEST", err)
+	}
+}
+
+func TestBoundaryRuleValidate(t *testing.T) {
+	dnsTests := []struct {
+		name string
+		rule DNSBoundaryRule
+		want string // substring of the error message; empty for valid
+	}{
+		{"allow-attack", DNSBoundaryRule{Action: DNSBoundaryRuleActionAllowAttack, Type: "glob", Filter: "*.example.com"}, ""},
+		{"allow-visit", DNSBoundaryRule{Action: DNSBoundaryRuleActionAllowVisit, Type: "glob", Filter: "*.example.com"}, ""},
+		{"deny", DNSBoundaryRule{Action: DNSBoundaryRuleActionDeny, Type: "glob", Filter: "*.example.com"}, ""},
+		{"allow-auth", DNSBoundaryRule{Action: "allow-auth", Type: "glob", Filter: "*.example.com"}, "allow-auth is only supported on HTTP boundary rules"},
+		{"typo action", DNSBoundaryRule{Action: "allowattack", Type: "glob", Filter: "*.example.com"}, `dnsBoundaryRule.action: must be one of allow-attack, allow-visit, deny, got "allowattack"`},
+		{"empty action", DNSBoundaryRule{Type: "glob", Filter: "*.example.com"}, "dnsBoundaryRule.action"},
+		{"unknown type", DNSBoundaryRule{Action: DNSBoundaryRuleActionDeny, Type: "hostname", Filter: "example.com"}, `dnsBoundaryRule.type: must be one of glob, got "hostname"`},
+	}
+	for _, tt := range dnsTests {
+		t.Run("dns "+tt.name, func(t *testing.T) {
+			checkValidateError(t, tt.rule.Validate(), tt.want)
+		})
+	}
+
+	httpTests := []struct {
+		name string
+		rule HTTPBoundaryRule
+		want string
+	}{
+		{"allow-auth", HTTPBoundaryRule{Action: HTTPBoundaryRuleActionAllowAuth, Type: "prefix", Filter: "https://example.com/login"}, ""},
+		{"deny regexp", HTTPBoundaryRule{Action: HTTPBoundaryRuleActionDeny, Type: "regexp", Filter: "/logout$"}, ""},
+		{"typo action", HTTPBoundaryRule{Action: "allow_visit", Type: "exact", Filter: "https://example.com"}, `httpBoundaryRule.action: must be one of allow-attack, allow-auth, allow-visit, deny, got "allow_visit"`},
+		{"empty action", HTTPBoundaryRule{Type: "exact", Filter: "https://example.com"}, "httpBoundaryRule.action"},
+		{"unknown type", HTTPBoundaryRule{Action: HTTPBoundaryRuleActionDeny, Type: "url", Filter: "https://example.com"}, `httpBoundaryRule.type: must be one of contains, exact, glob, prefix, regexp, got "url"`},
+	}
+	for _, tt := range httpTests {
+		t.Run("http "+tt.name, func(t *testing.T) {
+			checkValidateError(t, tt.rule.Validate(), tt.want)
+		})
+	}
+}
+
+// checkValidateError fails t unless err is nil when want is empty, or an
+// ERR_INVALID_REQUEST whose message contains want.
+func TestUpdateAssetRequestValidate(t *testing.T) {
+	valid := func() *UpdateAssetRequest {
+		return &UpdateAssetRequest{
+			Name:                 "App",
+			StartURL:             "https://app.example.com",
+			MaxRequestsPerSecond: 5,
+			Headers:              map[string][]string{"X-Test": {"1"}},
+			DNSBoundaryRules:     []DNSBoundaryRule{{Action: DNSBoundaryRuleActionAllowAttack, Type: "glob", Filter: "example.com"}},
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(r *UpdateAssetRequest)
+		want   string
+	}{
+		{"valid", func(*UpdateAssetRequest) {}, ""},
+		{"no start url", func(r *UpdateAssetRequest) { r.StartURL = "" }, ""},
+		{"missing name", func(r *UpdateAssetRequest) { r.Name = "" }, "name is required"},
+		{"relative start url", func(r *UpdateAssetRequest) { r.StartURL = "/login" }, "startUrl: must be an absolute http or https URL"},
+		{"negative rps", func(r *UpdateAssetRequest) { r.MaxRequestsPerSecond = -1 }, "maxRequestsPerSecond"},
+		{"bad header", func(r *UpdateAssetRequest) { r.Headers["Bad Name"] = nil }, `headers["Bad Name"]`},
+		{"bad window", func(r *UpdateAssetRequest) { r.ApprovedTimeWindows = &ApprovedTimeWindows{Tz: "Nowhere/Land"} }, "approvedTimeWindows.tz"},
+		{"bad rule", func(r *UpdateAssetRequest) { r.DNSBoundaryRules[0].Type = "hostname" }, "dnsBoundaryRules[0].type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(r)
+			checkValidateError(t, r.Validate(), tt.want)
+		})
+	}
+}
+
+func checkValidateError(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_REQUEST" {
+		t.Fatalf("error = %v, want ERR_INVALID_REQUEST", err)
+	}
+	if !strings.Contains(apiErr.Message, want) {
+		t.Errorf("message = %q, want it to contain %q", apiErr.Message, want)
+	}
+}
+
+func TestUpdateAssetRejectsInvalidBoundaryRules(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+
+	_, err := client.Assets.Update(context.Background(), "asset-123", &UpdateAssetRequest{
+		Name:                 "Asset",
+		StartURL:             "https://example.com",
+		MaxRequestsPerSecond: 10,
+		DNSBoundaryRules: []DNSBoundaryRule{
+			{Action: DNSBoundaryRuleActionAllowAttack, Type: "glob", Filter: "*.example.com"},
+			{Action: "allow-auth", Type: "glob", Filter: "login.example.com"},
+		},
+	})
+	checkValidateError(t, err, "dnsBoundaryRules[1].action: allow-auth")
+
+	rules := []HTTPBoundaryRule{{Action: "block", Type: "prefix", Filter: "https://example.com"}}
+	_, err = client.Assets.Patch(context.Background(), "asset-123", &PatchAssetRequest{HTTPBoundaryRules: &rules})
+	checkValidateError(t, err, "httpBoundaryRules[0].action")
+}
+
 func TestCreateAssetNilRequest(t *testing.T) {
 	client, _ := NewClient(WithOrganizationKey("test-key"))
 
@@ -828,3 +1075,258 @@ func TestHTTPBoundaryRuleActionConstants(t *testing.T) {
 		}
 	}
 }
+
+const testAssetJSON = `{
+	"id": "asset-123",
+	"name": "Test Asset",
+	"organizationId": "org-456",
+	"lifecycle": "active",
+	"sku": "standard-sku",
+	"startUrl": "https://example.com",
+	"maxRequestsPerSecond": 5,
+	"approvedTimeWindows": {"tz": "Europe/Berlin", "entries": [{"startWeekday": 1, "startTime": "09:00", "endWeekday": 5, "endTime": "17:00"}]},
+	"credentials": [],
+	"dnsBoundaryRules": [{"id": "dns-1", "action": "allow-attack", "type": "glob", "filter": "example.com"}],
+	"headers": {"X-Custom": ["a"]},
+	"httpBoundaryRules": [],
+	"checks": {
+		"assetReachable": {"state": "valid", "message": ""},
+		"credentials": {"state": "valid", "message": ""},
+		"dnsBoundaryRules": {"state": "valid", "message": ""},
+		"updatedAt": null
+	},
+	"archiveAt": null,
+	"createdAt": "2026-01-01T00:00:00Z",
+	"updatedAt": "2026-01-01T00:00:00Z"
+}`
+
+func TestSetMaxRequestsPerSecond(t *testing.T) {
+	t.Run("changes only the rate", func(t *testing.T) {
+		var putBody map[string]any
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodPut {
+				if err := json.NewDecoder(r.Body).Decode(&putBody); err != nil {
+					t.Errorf("decoding PUT body: %v", err)
+				}
+			}
+			_, _ = w.Write([]byte(testAssetJSON))
+		}))
+
+		if _, err := client.Assets.SetMaxRequestsPerSecond(context.Background(), "asset-123", 10); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if putBody == nil {
+			t.Fatal("expected a PUT request")
+		}
+		if got := putBody["maxRequestsPerSecond"]; got != float64(10) {
+			t.Errorf("maxRequestsPerSecond = %v, want 10", got)
+		}
+		if got := putBody["name"]; got != "Test Asset" {
+			t.Errorf("name = %v, want 'Test Asset'", got)
+		}
+		if got := putBody["startUrl"]; got != "https://example.com" {
+			t.Errorf("startUrl = %v, want 'https://example.com'", got)
+		}
+		if got := putBody["sku"]; got != "standard-sku" {
+			t.Errorf("sku = %v, want 'standard-sku'", got)
+		}
+		if rules, ok := putBody["dnsBoundaryRules"].([]any); !ok || len(rules) != 1 {
+			t.Errorf("dnsBoundaryRules = %v, want 1 rule preserved", putBody["dnsBoundaryRules"])
+		}
+		if atw, ok := putBody["approvedTimeWindows"].(map[string]any); !ok || atw["tz"] != "Europe/Berlin" {
+			t.Errorf("approvedTimeWindows = %v, want tz preserved", putBody["approvedTimeWindows"])
+		}
+		if headers, ok := putBody["headers"].(map[string]any); !ok || headers["X-Custom"] == nil {
+			t.Errorf("headers = %v, want X-Custom preserved", putBody["headers"])
+		}
+	})
+
+	t.Run("rejects non-positive values", func(t *testing.T) {
+		client, _ := NewClient(WithOrganizationKey("test-key"))
+
+		for _, rps := range []int{0, -1} {
+			_, err := client.Assets.SetMaxRequestsPerSecond(context.Background(), "asset-123", rps)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_REQUEST" {
+				t.Errorf("rps=%d: expected ERR_INVALID_REQUEST, got %v", rps, err)
+			}
+		}
+	})
+
+	t.Run("rejects empty id", func(t *testing.T) {
+		client, _ := NewClient(WithOrganizationKey("test-key"))
+
+		_, err := client.Assets.SetMaxRequestsPerSecond(context.Background(), "", 10)
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_PARAM" {
+			t.Errorf("expected ERR_INVALID_PARAM, got %v", err)
+		}
+	})
+}
+
+func TestPatchAssetRequestJSON(t *testing.T) {
+	name := "Renamed"
+	empty := []Credential{}
+	data, err := json.Marshal(&PatchAssetRequest{Name: &name, Credentials: &empty})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if got, want := string(data), `{"name":"Renamed","credentials":[]}`; got != want {
+		t.Errorf("JSON = %s, want %s", got, want)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	// patchServer serves testAssetJSON and records the body of the PUT.
+	patchServer := func(t *testing.T, putBody *map[string]any) *Client {
+		return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodPut {
+				if err := json.NewDecoder(r.Body).Decode(putBody); err != nil {
+					t.Errorf("decoding PUT body: %v", err)
+				}
+			}
+			_, _ = w.Write([]byte(testAssetJSON))
+		}))
+	}
+
+	t.Run("sends set fields and keeps the rest", func(t *testing.T) {
+		var putBody map[string]any
+		client := patchServer(t, &putBody)
+
+		name := "Renamed"
+		headers := map[string][]string{"X-New": {"1"}}
+		_, err := client.Assets.Patch(context.Background(), "asset-123", &PatchAssetRequest{
+			Name:    &name,
+			Headers: &headers,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := putBody["name"]; got != "Renamed" {
+			t.Errorf("name = %v, want Renamed", got)
+		}
+		if h, ok := putBody["headers"].(map[string]any); !ok || h["X-New"] == nil || h["X-Custom"] != nil {
+			t.Errorf("headers = %v, want only X-New", putBody["headers"])
+		}
+		// Nil fields keep the asset's current values.
+		if got := putBody["startUrl"]; got != "https://example.com" {
+			t.Errorf("startUrl = %v, want unchanged", got)
+		}
+		if got := putBody["sku"]; got != "standard-sku" {
+			t.Errorf("sku = %v, want unchanged", got)
+		}
+		if rules, ok := putBody["dnsBoundaryRules"].([]any); !ok || len(rules) != 1 {
+			t.Errorf("dnsBoundaryRules = %v, want unchanged", putBody["dnsBoundaryRules"])
+		}
+	})
+
+	t.Run("empty slice clears the field", func(t *testing.T) {
+		var putBody map[string]any
+		client := patchServer(t, &putBody)
+
+		none := []DNSBoundaryRule{}
+		if _, err := client.Assets.Patch(context.Background(), "asset-123", &PatchAssetRequest{DNSBoundaryRules: &none}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rules, _ := putBody["dnsBoundaryRules"].([]any); len(rules) != 0 {
+			t.Errorf("dnsBoundaryRules = %v, want cleared", putBody["dnsBoundaryRules"])
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		client, _ := NewClient(WithOrganizationKey("test-key"))
+		zero := 0
+
+		tests := []struct {
+			name     string
+			id       string
+			req      *PatchAssetRequest
+			wantCode string
+		}{
+			{"empty id", "", &PatchAssetRequest{}, "ERR_INVALID_PARAM"},
+			{"nil request", "asset-123", nil, "ERR_INVALID_REQUEST"},
+			{"zero rate", "asset-123", &PatchAssetRequest{MaxRequestsPerSecond: &zero}, "ERR_INVALID_REQUEST"},
+			{"illegal header", "asset-123", &PatchAssetRequest{Headers: &map[string][]string{"Bad Header": {"1"}}}, "ERR_INVALID_REQUEST"},
+		}
+		for _, tt := range tests {
+			_, err := client.Assets.Patch(context.Background(), tt.id, tt.req)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.wantCode {
+				t.Errorf("%s: expected %s, got %v", tt.name, tt.wantCode, err)
+			}
+		}
+	})
+}
+
+func TestAssetChecksAllSettled(t *testing.T) {
+	checks := func(reachable, credentials, dns AssetCheckState) *AssetChecks {
+		return &AssetChecks{
+			AssetReachable:   AssetCheck{State: reachable},
+			Credentials:      AssetCheck{State: credentials},
+			DNSBoundaryRules: AssetCheck{State: dns},
+		}
+	}
+	tests := []struct {
+		name   string
+		checks *AssetChecks
+		want   bool
+	}{
+		{"all valid", checks(AssetCheckStateValid, AssetCheckStateValid, AssetCheckStateValid), true},
+		{"valid and invalid", checks(AssetCheckStateValid, AssetCheckStateInvalid, AssetCheckStateValid), true},
+		{"one checking", checks(AssetCheckStateValid, AssetCheckStateValid, AssetCheckStateChecking), false},
+		{"one unchecked", checks(AssetCheckStateUnchecked, AssetCheckStateValid, AssetCheckStateValid), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		if got := tt.checks.AllSettled(); got != tt.want {
+			t.Errorf("%s: AllSettled() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWaitForChecks(t *testing.T) {
+	checking := strings.Replace(testAssetJSON, `"credentials": {"state": "valid"`, `"credentials": {"state": "checking"`, 1)
+	if checking == testAssetJSON {
+		t.Fatal("testAssetJSON has no credentials check to replace")
+	}
+
+	t.Run("checking to valid", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if calls.Add(1) < 3 {
+				_, _ = w.Write([]byte(checking))
+				return
+			}
+			_, _ = w.Write([]byte(testAssetJSON))
+		}), WithPollInterval(time.Millisecond))
+
+		asset, err := client.Assets.WaitForChecks(context.Background(), "asset-123")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if asset.Checks.Credentials.State != AssetCheckStateValid {
+			t.Errorf("credentials check = %q, want valid", asset.Checks.Credentials.State)
+		}
+		if got := calls.Load(); got != 3 {
+			t.Errorf("polls = %d, want 3", got)
+		}
+	})
+
+	t.Run("context ends", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(checking))
+		}), WithPollInterval(time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := client.Assets.WaitForChecks(ctx, "asset-123"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
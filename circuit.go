@@ -0,0 +1,168 @@
+package xbow
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerSettings configures the circuit breaker enabled by
+// WithCircuitBreaker.
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures, all within
+	// Window, that opens the circuit. Default is 5.
+	FailureThreshold int
+
+	// Window bounds how far apart the first and last of those failures may
+	// be; an older streak starts over. Default is 1 minute.
+	Window time.Duration
+
+	// Cooldown is how long the circuit stays open before a single probe
+	// request is let through to test recovery. Default is 30 seconds.
+	Cooldown time.Duration
+
+	// IsFailure, if set, decides whether an attempt counts as a failure. It
+	// receives either the response or the transport error (resp is nil when
+	// err is set). By default transport errors and 5xx responses are
+	// failures. Requests cancelled by their own context never count.
+	IsFailure func(resp *http.Response, err error) bool
+}
+
+func (s *CircuitBreakerSettings) defaults() {
+	if s.FailureThreshold <= 0 {
+		s.FailureThreshold = 5
+	}
+	if s.Window <= 0 {
+		s.Window = time.Minute
+	}
+	if s.Cooldown <= 0 {
+		s.Cooldown = 30 * time.Second
+	}
+}
+
+// WithCircuitBreaker stops the client from sending requests during a
+// sustained outage. After FailureThreshold consecutive failed attempts the
+// circuit opens, and calls fail immediately with an error matching
+// ErrCircuitOpen. Once Cooldown has passed, one probe request is sent: if it
+// succeeds the circuit closes, otherwise it stays open for another Cooldown.
+// By default there is no circuit breaker.
+//
+// The breaker sits below WithRetryPolicy, so each retry attempt counts
+// towards the threshold, and a call stops retrying as soon as the circuit
+// opens.
+//
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("key"),
+//	    xbow.WithCircuitBreaker(&xbow.CircuitBreakerSettings{
+//	        FailureThreshold: 10,
+//	        Cooldown:         time.Minute,
+//	    }),
+//	)
+func WithCircuitBreaker(s *CircuitBreakerSettings) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.breaker = s
+	}
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreakerTransport fails requests fast while its circuit is open.
+type circuitBreakerTransport struct {
+	base     http.RoundTripper
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu           sync.Mutex
+	state        circuitState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+}
+
+func newCircuitBreakerTransport(base http.RoundTripper, s CircuitBreakerSettings) *circuitBreakerTransport {
+	s.defaults()
+	return &circuitBreakerTransport{base: base, settings: s, now: time.Now}
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil && req.Context().Err() != nil {
+		// The caller gave up; that says nothing about the server. Release a
+		// half-open probe so another request can try.
+		t.record(false, true)
+		return resp, err
+	}
+	t.record(t.isFailure(resp, err), false)
+	return resp, err
+}
+
+// allow reports whether a request may be sent, moving an open circuit whose
+// cooldown has passed to half-open and admitting the caller as its probe.
+func (t *circuitBreakerTransport) allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch t.state {
+	case circuitOpen:
+		if t.now().Sub(t.openedAt) < t.settings.Cooldown {
+			return false
+		}
+		t.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// A probe is already in flight.
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the circuit with the outcome of a request. An abandoned
+// request leaves the failure count alone.
+func (t *circuitBreakerTransport) record(failed, abandoned bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	switch {
+	case abandoned:
+		if t.state == circuitHalfOpen {
+			t.state = circuitOpen
+			t.openedAt = now.Add(-t.settings.Cooldown)
+		}
+	case !failed:
+		t.state = circuitClosed
+		t.failures = 0
+	case t.state == circuitHalfOpen:
+		t.state = circuitOpen
+		t.openedAt = now
+	default:
+		if t.failures == 0 || now.Sub(t.firstFailure) > t.settings.Window {
+			t.failures = 0
+			t.firstFailure = now
+		}
+		t.failures++
+		if t.failures >= t.settings.FailureThreshold {
+			t.state = circuitOpen
+			t.openedAt = now
+			t.failures = 0
+		}
+	}
+}
+
+func (t *circuitBreakerTransport) isFailure(resp *http.Response, err error) bool {
+	if t.settings.IsFailure != nil {
+		return t.settings.IsFailure(resp, err)
+	}
+	return err != nil || resp.StatusCode >= 500
+}
@@ -0,0 +1,152 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusTransport answers every request with the status held in code.
+func statusTransport(calls *atomic.Int32, code *atomic.Int32) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return &http.Response{
+			StatusCode: int(code.Load()),
+			Body:       io.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestCircuitBreakerTransport(t *testing.T) {
+	var calls, code atomic.Int32
+	code.Store(http.StatusServiceUnavailable)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreakerTransport(statusTransport(&calls, &code), CircuitBreakerSettings{
+		FailureThreshold: 3,
+		Cooldown:         10 * time.Second,
+	})
+	cb.now = func() time.Time { return now }
+
+	send := func() error {
+		req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+		resp, err := cb.RoundTrip(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	for i := range 3 {
+		if err := send(); err != nil {
+			t.Fatalf("request %d: unexpected error %v", i+1, err)
+		}
+	}
+	if err := send(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after threshold: error = %v, want ErrCircuitOpen", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server calls = %d, want 3 (open circuit must not reach it)", got)
+	}
+
+	// A failed probe after the cooldown keeps the circuit open.
+	now = now.Add(10 * time.Second)
+	if err := send(); err != nil {
+		t.Fatalf("probe: unexpected error %v", err)
+	}
+	if err := send(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after failed probe: error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A successful probe closes it.
+	now = now.Add(10 * time.Second)
+	code.Store(http.StatusOK)
+	for i := range 3 {
+		if err := send(); err != nil {
+			t.Fatalf("request %d after recovery: unexpected error %v", i+1, err)
+		}
+	}
+	if got := calls.Load(); got != 7 {
+		t.Errorf("server calls = %d, want 7", got)
+	}
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	var calls, code atomic.Int32
+	code.Store(http.StatusInternalServerError)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreakerTransport(statusTransport(&calls, &code), CircuitBreakerSettings{
+		FailureThreshold: 2,
+		Window:           time.Minute,
+	})
+	cb.now = func() time.Time { return now }
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+	for range 3 {
+		resp, err := cb.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("failures spread past the window opened the circuit: %v", err)
+		}
+		_ = resp.Body.Close()
+		now = now.Add(2 * time.Minute)
+	}
+}
+
+func TestCircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	cb := newCircuitBreakerTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, req.Context().Err()
+	}), CircuitBreakerSettings{FailureThreshold: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil).WithContext(ctx)
+	for range 3 {
+		if _, err := cb.RoundTrip(req); !errors.Is(err, context.Canceled) {
+			t.Fatalf("error = %v, want context.Canceled", err)
+		}
+	}
+}
+
+func TestWithCircuitBreaker_StopsRetries(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"ERR_UNAVAILABLE","error":"Service Unavailable","message":"down"}`))
+	}),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		}),
+		WithCircuitBreaker(&CircuitBreakerSettings{
+			FailureThreshold: 2,
+			Cooldown:         time.Hour,
+		}),
+	)
+
+	_, err := client.Reports.GetSummary(context.Background(), "report-1")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error = %v, want ErrCircuitOpen", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server calls = %d, want 2", got)
+	}
+
+	_, err = client.Reports.GetSummary(context.Background(), "report-1")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second call error = %v, want ErrCircuitOpen", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server calls after open = %d, want 2", got)
+	}
+}
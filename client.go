@@ -31,9 +31,15 @@ package xbow
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/doordash-oss/oapi-codegen-dd/v3/pkg/runtime"
 	"github.com/rsclarke/xbow/internal/api"
@@ -45,6 +51,17 @@ const (
 	APIVersion     = "2026-02-01"
 )
 
+// SDKVersion is the version of this SDK reported in the User-Agent header.
+// Release builds set it with:
+//
+//	go build -ldflags "-X github.com/rsclarke/xbow.SDKVersion=v1.2.3"
+var SDKVersion = "dev"
+
+const (
+	defaultPollInterval = 5 * time.Second
+	maxPollInterval     = time.Minute
+)
+
 // Client manages communication with the XBOW API.
 type Client struct {
 	raw            *api.Client
@@ -52,6 +69,19 @@ type Client struct {
 	integrationKey string
 	baseURL        string
 	httpClient     *http.Client
+	pollInterval   time.Duration
+	userAgent      string
+	correlationID  func(context.Context) string
+	apiVersion     string
+
+	// baseTransport is the transport the SDK stack wraps, when the caller
+	// supplied one with WithHTTPClient or WithTransport.
+	baseTransport http.RoundTripper
+
+	// closed is cancelled by Close to stop background work tied to the
+	// client, such as verifier key refreshes.
+	closed    context.Context
+	closeFunc context.CancelFunc
 
 	// Services
 	Assessments   *AssessmentsService
@@ -69,11 +99,16 @@ type ClientOption func(*clientConfig)
 type clientConfig struct {
 	baseURL        string
 	httpClient     *http.Client
+	roundTripper   http.RoundTripper
 	apiClientOpts  []runtime.APIClientOption
 	orgKey         string
 	integrationKey string
-	rateLimiter    RateLimiter
-	retryPolicy    *RetryPolicy
+	transport      transportConfig
+	pollInterval   time.Duration
+	userAgent      []string
+	correlationID  func(context.Context) string
+	apiVersion     string
+	tlsConfig      *tls.Config
 }
 
 // WithBaseURL sets a custom base URL.
@@ -96,10 +131,66 @@ func (w *httpClientWrapper) Do(ctx context.Context, req *http.Request) (*http.Re
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *clientConfig) {
 		c.httpClient = httpClient
-		c.apiClientOpts = append(c.apiClientOpts, runtime.WithHTTPClient(&httpClientWrapper{client: httpClient}))
 	}
 }
 
+// WithTransport sets the transport that requests are finally sent with,
+// replacing that of the HTTP client (from WithHTTPClient, or
+// http.DefaultClient). The SDK's own transport stack (retries, rate
+// limiting, logging and so on) still wraps it. Combined with
+// NewHandlerTransport it serves requests from an http.Handler in memory,
+// without a network:
+//
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("test-key"),
+//	    xbow.WithTransport(xbow.NewHandlerTransport(mockAPI)),
+//	)
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *clientConfig) {
+		c.roundTripper = rt
+	}
+}
+
+// WithTLSConfig makes the client use a copy of cfg for TLS, for example to
+// trust a corporate proxy's CA through RootCAs. It applies to a clone of
+// the base transport, the HTTP client's transport or http.DefaultTransport,
+// so neither the caller's transport nor global state is changed, and the
+// SDK's transport stack wraps the clone as usual. NewClient fails if the
+// base transport, as set by WithTransport or WithHTTPClient, is not an
+// *http.Transport.
+//
+// Setting InsecureSkipVerify disables certificate checks altogether: anyone
+// able to intercept the connection can then read and alter requests,
+// including the API keys they carry. Use it only against a local mock
+// server, and prefer adding the server's certificate to RootCAs.
+//
+//	pool := x509.NewCertPool()
+//	pool.AppendCertsFromPEM(proxyCA)
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("key"),
+//	    xbow.WithTLSConfig(&tls.Config{RootCAs: pool}),
+//	)
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *clientConfig) {
+		c.tlsConfig = cfg
+	}
+}
+
+// transportWithTLS returns a clone of base, or of http.DefaultTransport if
+// base is nil, using a copy of cfg for TLS.
+func transportWithTLS(base http.RoundTripper, cfg *tls.Config) (*http.Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("xbow: WithTLSConfig requires an *http.Transport base transport, got %T", base)
+	}
+	t = t.Clone()
+	t.TLSClientConfig = cfg.Clone()
+	return t, nil
+}
+
 // WithAPIClientOption adds a runtime.APIClientOption to the underlying client.
 func WithAPIClientOption(opt runtime.APIClientOption) ClientOption {
 	return func(c *clientConfig) {
@@ -134,59 +225,144 @@ func WithIntegrationKey(key string) ClientOption {
 //	)
 func WithRateLimiter(limiter RateLimiter) ClientOption {
 	return func(c *clientConfig) {
-		c.rateLimiter = limiter
+		c.transport.rateLimiter = limiter
+	}
+}
+
+// WithPollInterval sets the initial delay between polls in helpers such as
+// AssessmentsService.WaitForState. The delay grows between polls, up to
+// maxPollInterval. Default is 5 seconds.
+func WithPollInterval(d time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.pollInterval = d
+	}
+}
+
+// WithUserAgent appends a product token, such as "myapp/1.2", to the
+// User-Agent header. The SDK's own "xbow-go/<version>" token is kept first,
+// so the header reads "xbow-go/<version> myapp/1.2". Repeated calls append
+// further tokens.
+func WithUserAgent(token string) ClientOption {
+	return func(c *clientConfig) {
+		if token != "" {
+			c.userAgent = append(c.userAgent, token)
+		}
+	}
+}
+
+// WithCorrelationIDFunc stamps each request with an X-Correlation-ID header
+// holding fn's result for the request context, so calls can be matched up
+// with the caller's own logs. No header is added when fn returns "". With
+// CorrelationIDFromContext, API calls made while handling a webhook carry
+// the webhook's correlation id:
+//
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("key"),
+//	    xbow.WithCorrelationIDFunc(xbow.CorrelationIDFromContext),
+//	)
+func WithCorrelationIDFunc(fn func(ctx context.Context) string) ClientOption {
+	return func(c *clientConfig) {
+		c.correlationID = fn
+	}
+}
+
+// restAPIVersions lists the values WithAPIVersion accepts. The spec's
+// X-XBOW-API-Version enum holds only APIVersion; next and unstable are the
+// API's rolling versions, accepted so early adopters can opt in. This is
+// deliberately separate from WebhookAPIVersionValues, which also lists the
+// webhook-only 2025-11-01 payload version.
+var restAPIVersions = []string{APIVersion, "next", "unstable"}
+
+// WithAPIVersion sends v as the X-XBOW-API-Version header in place of
+// APIVersion, for trying out upcoming behavior with "next" or "unstable"
+// before the SDK targets it. NewClient rejects any other value. The SDK's
+// types still follow APIVersion, so fields added in another version are not
+// decoded and fields removed from it read as zero values.
+func WithAPIVersion(v string) ClientOption {
+	return func(c *clientConfig) {
+		c.apiVersion = v
 	}
 }
 
 // NewClient creates a new XBOW API client.
 func NewClient(opts ...ClientOption) (*Client, error) {
 	cfg := &clientConfig{
-		baseURL:    DefaultBaseURL,
-		httpClient: http.DefaultClient,
+		baseURL:      DefaultBaseURL,
+		httpClient:   http.DefaultClient,
+		pollInterval: defaultPollInterval,
+		transport:    transportConfig{maxResponseBytes: defaultMaxResponseBytes},
 	}
 
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	// Wrap HTTP transport with retry and rate limit transports.
-	// Layering: HTTP Client → rateLimitTransport → retryTransport → base transport
-	if cfg.retryPolicy != nil || cfg.rateLimiter != nil {
-		transport := cfg.httpClient.Transport
-		if transport == nil {
-			transport = http.DefaultTransport
-		}
+	if cfg.pollInterval <= 0 {
+		cfg.pollInterval = defaultPollInterval
+	}
+	if cfg.apiVersion == "" {
+		cfg.apiVersion = APIVersion
+	}
+	if !slices.Contains(restAPIVersions, cfg.apiVersion) {
+		return nil, fmt.Errorf("xbow: unknown API version %q (valid: %s)", cfg.apiVersion, joinValues(restAPIVersions))
+	}
 
-		if cfg.retryPolicy != nil {
-			cfg.retryPolicy.defaults()
-			transport = &retryTransport{base: transport, policy: *cfg.retryPolicy}
+	baseTransport := cfg.httpClient.Transport
+	if cfg.roundTripper != nil {
+		baseTransport = cfg.roundTripper
+	}
+	if cfg.tlsConfig != nil {
+		t, err := transportWithTLS(baseTransport, cfg.tlsConfig)
+		if err != nil {
+			return nil, err
 		}
+		baseTransport = t
+	}
 
-		if cfg.rateLimiter != nil {
-			transport = &rateLimitTransport{base: transport, limiter: cfg.rateLimiter}
-		}
+	// Wrap HTTP transport with the SDK transport stack. See
+	// transportConfig.wrap for the order of the layers.
+	cfg.httpClient = &http.Client{
+		Transport:     cfg.transport.wrap(baseTransport),
+		CheckRedirect: cfg.httpClient.CheckRedirect,
+		Jar:           cfg.httpClient.Jar,
+		Timeout:       cfg.httpClient.Timeout,
+	}
+	userAgent := strings.Join(append([]string{"xbow-go/" + SDKVersion}, cfg.userAgent...), " ")
 
-		wrappedClient := &http.Client{
-			Transport:     transport,
-			CheckRedirect: cfg.httpClient.CheckRedirect,
-			Jar:           cfg.httpClient.Jar,
-			Timeout:       cfg.httpClient.Timeout,
-		}
-		cfg.httpClient = wrappedClient
-		cfg.apiClientOpts = append(cfg.apiClientOpts, runtime.WithHTTPClient(&httpClientWrapper{client: wrappedClient}))
+	// Install the wrapped client and default editors first so
+	// WithAPIClientOption can still override them.
+	defaultOpts := []runtime.APIClientOption{
+		runtime.WithHTTPClient(&httpClientWrapper{client: cfg.httpClient}),
+		runtime.WithRequestEditorFn(userAgentEditor(userAgent)),
+	}
+	if cfg.correlationID != nil {
+		defaultOpts = append(defaultOpts, runtime.WithRequestEditorFn(correlationIDEditor(cfg.correlationID)))
 	}
+	if cfg.apiVersion != APIVersion {
+		// The generated client always sets its own version header first.
+		defaultOpts = append(defaultOpts, runtime.WithRequestEditorFn(apiVersionEditor(cfg.apiVersion)))
+	}
+	cfg.apiClientOpts = append(defaultOpts, cfg.apiClientOpts...)
 
 	raw, err := api.NewDefaultClient(cfg.baseURL, cfg.apiClientOpts...)
 	if err != nil {
 		return nil, err
 	}
 
+	closed, closeFunc := context.WithCancel(context.Background())
 	c := &Client{
 		raw:            raw,
 		orgKey:         cfg.orgKey,
 		integrationKey: cfg.integrationKey,
 		baseURL:        cfg.baseURL,
 		httpClient:     cfg.httpClient,
+		pollInterval:   cfg.pollInterval,
+		userAgent:      userAgent,
+		correlationID:  cfg.correlationID,
+		apiVersion:     cfg.apiVersion,
+		baseTransport:  baseTransport,
+		closed:         closed,
+		closeFunc:      closeFunc,
 	}
 
 	c.Assessments = &AssessmentsService{client: c}
@@ -205,6 +381,123 @@ func (c *Client) Raw() *api.Client {
 	return c.raw
 }
 
+// BaseURL returns the API base URL the client sends requests to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Close releases resources held by the client: verifiers created with
+// NewWebhookVerifierFromClient stop refreshing their keys (keeping the keys
+// they have), and idle connections of the transport supplied with
+// WithHTTPClient are closed. The shared http.DefaultTransport is left alone.
+// Long-running services that create a client per tenant should close each
+// one when done with it.
+//
+// Close always returns nil and is safe to call more than once. The client
+// can still make requests afterwards, opening new connections as needed.
+func (c *Client) Close() error {
+	c.closeFunc()
+	if c.baseTransport == http.DefaultTransport {
+		return nil
+	}
+	if ci, ok := c.baseTransport.(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Ping checks that the API is reachable and that the organization key is
+// accepted, by fetching the webhook signing keys, a cheap authenticated
+// call. A rejected key returns an error matching ErrUnauthorized, or
+// ErrForbidden if the key lacks access.
+//
+// Example:
+//
+//	if err := client.Ping(ctx); errors.Is(err, xbow.ErrUnauthorized) {
+//	    log.Fatal("check XBOW_ORG_KEY")
+//	}
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Meta.GetWebhookSigningKeys(ctx)
+	return err
+}
+
+// Do sends a request to an API endpoint the SDK has no typed method for
+// yet, such as one added to the API after this release. It is a supported
+// escape hatch alongside Raw.
+//
+// path is relative to the base URL and may include a query string, for
+// example "/api/v1/assets/asset-123". A non-nil body is sent as JSON. The
+// request carries the organization key, or the integration key when no
+// organization key is set (use WithHeader to send a different
+// Authorization), the API version header and the User-Agent, and passes
+// through the client's transport stack, so retries, rate limiting and
+// logging apply as usual.
+//
+// A 2xx response is JSON-decoded into out unless out is nil or the body is
+// empty. Any other status is returned as an *Error, so errors.Is works
+// with the sentinel errors.
+//
+// Example:
+//
+//	var asset struct {
+//	    ID   string `json:"id"`
+//	    Name string `json:"name"`
+//	}
+//	err := client.Do(ctx, http.MethodGet, "/api/v1/assets/"+id, nil, &asset)
+func (c *Client) Do(ctx context.Context, method, path string, body io.Reader, out any, opts ...RequestOption) error {
+	auth, err := c.anyAuthEditor()
+	if err != nil {
+		return err
+	}
+
+	rc := newRequestConfig(opts)
+	ctx, cancel := rc.context(ctx)
+	defer cancel()
+
+	resp, err := c.doStream(ctx, method, path, body, rc.editors(auth)...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
+
+// userAgentEditor returns a request editor that sets the User-Agent header.
+func userAgentEditor(ua string) runtime.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("User-Agent", ua)
+		return nil
+	}
+}
+
+// apiVersionEditor returns a request editor that overrides the API version
+// header.
+func apiVersionEditor(v string) runtime.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("X-XBOW-API-Version", v)
+		return nil
+	}
+}
+
+// correlationIDEditor returns a request editor that sets the correlation id
+// header from fn, unless fn returns "".
+func correlationIDEditor(fn func(context.Context) string) runtime.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		if id := fn(ctx); id != "" {
+			req.Header.Set(HeaderCorrelationID, id)
+		}
+		return nil
+	}
+}
+
 // authEditorFor returns a request editor that adds authentication headers for the given key.
 func (c *Client) authEditorFor(key string) runtime.RequestEditorFn {
 	return func(ctx context.Context, req *http.Request) error {
@@ -243,37 +536,82 @@ func (c *Client) orgOrIntegrationAuthEditor() (runtime.RequestEditorFn, error) {
 	return nil, ErrMissingAnyKey
 }
 
+// anyAuthEditor returns a request editor preferring the organization key,
+// falling back to the integration key. Returns an error if neither key is
+// set.
+func (c *Client) anyAuthEditor() (runtime.RequestEditorFn, error) {
+	if c.orgKey != "" {
+		return c.authEditorFor(c.orgKey), nil
+	}
+	if c.integrationKey != "" {
+		return c.authEditorFor(c.integrationKey), nil
+	}
+	return nil, ErrMissingAnyKey
+}
+
 // do executes a raw HTTP request with authentication and the API version header.
 // It returns the response and body bytes. Non-2xx responses are returned as a
 // properly structured *Error with StatusCode set, so that errors.Is works with
 // sentinel errors like ErrNotFound.
 func (c *Client) do(ctx context.Context, method, path string, auth runtime.RequestEditorFn) ([]byte, error) {
+	resp, err := c.doStream(ctx, method, path, nil, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return body, nil
+}
+
+// doStream is like do but returns the response with its body unread, so
+// large payloads can be streamed. The caller must close the body. Non-2xx
+// responses are consumed and returned as a structured *Error. A non-nil
+// body is sent as JSON. The editors, normally auth first, run after the
+// SDK's own headers are set so they can override them.
+func (c *Client) doStream(ctx context.Context, method, path string, body io.Reader, editors ...runtime.RequestEditorFn) (*http.Response, error) {
 	url := c.baseURL + path
 
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	ctx, rid := recordRequestID(ctx)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	if err := auth(ctx, req); err != nil {
-		return nil, fmt.Errorf("applying auth: %w", err)
+	req.Header.Set("X-XBOW-API-Version", c.apiVersion)
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.correlationID != nil {
+		editors = append([]runtime.RequestEditorFn{correlationIDEditor(c.correlationID)}, editors...)
+	}
+	for _, edit := range editors {
+		if err := edit(ctx, req); err != nil {
+			return nil, fmt.Errorf("applying auth: %w", err)
+		}
 	}
-	req.Header.Set("X-XBOW-API-Version", APIVersion)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
-	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, wrapRawError(resp.StatusCode, body)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+		apiErr := wrapRawError(resp.StatusCode, body)
+		apiErr.RequestID = resp.Header.Get(HeaderRequestID)
+		apiErr.Attempts = rid.attempts
+		return nil, apiErr
 	}
 
-	return body, nil
+	return resp, nil
 }
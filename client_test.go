@@ -0,0 +1,343 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestClient starts an httptest.Server backed by handler and returns a
+// Client pointed at it with both keys configured. Additional options are
+// applied after the defaults.
+func newTestClient(t *testing.T, handler http.Handler, opts ...ClientOption) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	opts = append([]ClientOption{
+		WithBaseURL(srv.URL),
+		WithHTTPClient(srv.Client()),
+		WithOrganizationKey("test-org-key"),
+		WithIntegrationKey("test-integration-key"),
+	}, opts...)
+
+	c, err := NewClient(opts...)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return c
+}
+
+func TestNewClient_DefaultHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	// No WithHTTPClient: the generated client must still have a doer.
+	client, err := NewClient(WithBaseURL(srv.URL), WithOrganizationKey("test-org-key"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want string
+	}{
+		{name: "default", want: "xbow-go/" + SDKVersion},
+		{name: "custom token is appended", opts: []ClientOption{WithUserAgent("myapp/1.2")}, want: "xbow-go/" + SDKVersion + " myapp/1.2"},
+		{name: "multiple tokens", opts: []ClientOption{WithUserAgent("myapp/1.2"), WithUserAgent("plugin/0.1")}, want: "xbow-go/" + SDKVersion + " myapp/1.2 plugin/0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, r.Header.Get("User-Agent"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+			}), tt.opts...)
+
+			// GetSummary goes through the generated client; Download uses
+			// the raw request path.
+			if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err != nil {
+				t.Fatalf("GetSummary: %v", err)
+			}
+			if _, err := client.Reports.Download(context.Background(), "report-1", io.Discard); err != nil {
+				t.Fatalf("Download: %v", err)
+			}
+
+			for i, ua := range got {
+				if ua != tt.want {
+					t.Errorf("request %d User-Agent = %q, want %q", i, ua, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithCorrelationIDFunc(t *testing.T) {
+	var got []string
+	var present []bool
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Header[http.CanonicalHeaderKey(HeaderCorrelationID)]
+		got = append(got, r.Header.Get(HeaderCorrelationID))
+		present = append(present, ok)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+	}), WithCorrelationIDFunc(CorrelationIDFromContext))
+
+	// GetSummary goes through the generated client; Download uses the raw
+	// request path.
+	call := func(ctx context.Context) {
+		t.Helper()
+		if _, err := client.Reports.GetSummary(ctx, "report-1"); err != nil {
+			t.Fatalf("GetSummary: %v", err)
+		}
+		if _, err := client.Reports.Download(ctx, "report-1", io.Discard); err != nil {
+			t.Fatalf("Download: %v", err)
+		}
+	}
+
+	call(ContextWithCorrelationID(context.Background(), "trace-123"))
+	call(context.Background())
+
+	want := []string{"trace-123", "trace-123", "", ""}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d X-Correlation-ID = %q, want %q", i, got[i], want[i])
+		}
+		if present[i] != (want[i] != "") {
+			t.Errorf("request %d header present = %v", i, present[i])
+		}
+	}
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	var got []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-XBOW-API-Version"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+	})
+
+	// GetSummary goes through the generated client; Download uses the raw
+	// request path.
+	for _, tt := range []struct {
+		opts []ClientOption
+		want string
+	}{
+		{want: APIVersion},
+		{opts: []ClientOption{WithAPIVersion("next")}, want: "next"},
+	} {
+		got = nil
+		client := newTestClient(t, handler, tt.opts...)
+		if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err != nil {
+			t.Fatalf("GetSummary: %v", err)
+		}
+		if _, err := client.Reports.Download(context.Background(), "report-1", io.Discard); err != nil {
+			t.Fatalf("Download: %v", err)
+		}
+		if len(got) != 2 || got[0] != tt.want || got[1] != tt.want {
+			t.Errorf("X-XBOW-API-Version = %q, want %q on both requests", got, tt.want)
+		}
+	}
+
+	// 2025-11-01 is a webhook payload version, not a REST one.
+	for _, v := range []string{"2099-01-01", "2025-11-01"} {
+		_, err := NewClient(WithOrganizationKey("key"), WithAPIVersion(v))
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("unknown API version %q", v)) {
+			t.Errorf("NewClient(WithAPIVersion(%q)) error = %v, want unknown API version", v, err)
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "ok", status: http.StatusOK, body: `[{"publicKey":"a2V5"}]`},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `{"code":"ERR_UNAUTHORIZED","error":"Unauthorized","message":"invalid API key"}`, wantErr: ErrUnauthorized},
+		{name: "forbidden", status: http.StatusForbidden, body: `{"code":"ERR_FORBIDDEN","error":"Forbidden","message":"access denied"}`, wantErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer test-org-key" {
+					t.Errorf("Authorization = %q", got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+
+			err := client.Ping(context.Background())
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		client, err := NewClient()
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		if err := client.Ping(context.Background()); !errors.Is(err, ErrMissingOrgKey) {
+			t.Errorf("error = %v, want ErrMissingOrgKey", err)
+		}
+	})
+}
+
+// idleClosingTransport counts CloseIdleConnections calls.
+type idleClosingTransport struct {
+	http.RoundTripper
+	closes int
+}
+
+func (t *idleClosingTransport) CloseIdleConnections() {
+	t.closes++
+}
+
+func TestClientClose(t *testing.T) {
+	t.Run("closes idle connections", func(t *testing.T) {
+		transport := &idleClosingTransport{RoundTripper: http.DefaultTransport}
+		client, err := NewClient(WithOrganizationKey("key"), WithHTTPClient(&http.Client{Transport: transport}))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if transport.closes != 1 {
+			t.Errorf("CloseIdleConnections calls = %d, want 1", transport.closes)
+		}
+		if err := client.Close(); err != nil {
+			t.Fatalf("second Close() error = %v", err)
+		}
+	})
+
+	t.Run("default transport", func(t *testing.T) {
+		client, err := NewClient(WithOrganizationKey("key"))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		if err := client.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	})
+}
+
+func TestClientDo(t *testing.T) {
+	t.Run("GET decodes into struct", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.URL.Path != "/api/v1/assets/asset-123" {
+				t.Errorf("request = %s %s", r.Method, r.URL.Path)
+			}
+			if got := r.Header.Get("Authorization"); got != "Bearer test-org-key" {
+				t.Errorf("Authorization = %q", got)
+			}
+			if got := r.Header.Get("X-XBOW-API-Version"); got != APIVersion {
+				t.Errorf("X-XBOW-API-Version = %q", got)
+			}
+			if got := r.Header.Get("X-Trace"); got != "abc" {
+				t.Errorf("X-Trace = %q, want request option header", got)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(testAssetJSON))
+		}))
+
+		var asset struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		err := client.Do(context.Background(), http.MethodGet, "/api/v1/assets/asset-123", nil, &asset, WithHeader("X-Trace", "abc"))
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if asset.ID != "asset-123" || asset.Name == "" {
+			t.Errorf("decoded %+v", asset)
+		}
+	})
+
+	t.Run("POST 422 maps to Error", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"name":""}` {
+				t.Errorf("body = %s", body)
+			}
+			if got := r.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(HeaderRequestID, "req-9")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"code":"ERR_UNPROCESSABLE","error":"Unprocessable Entity","message":"name is empty"}`))
+		}))
+
+		var out map[string]any
+		err := client.Do(context.Background(), http.MethodPost, "/api/v1/things", strings.NewReader(`{"name":""}`), &out)
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("error = %v, want *Error", err)
+		}
+		if apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Code != "ERR_UNPROCESSABLE" || apiErr.Message != "name is empty" {
+			t.Errorf("got %+v", apiErr)
+		}
+		if apiErr.RequestID != "req-9" {
+			t.Errorf("RequestID = %q, want req-9", apiErr.RequestID)
+		}
+		if out != nil {
+			t.Errorf("out = %v, want untouched", out)
+		}
+	})
+
+	t.Run("nil out and empty body", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		if err := client.Do(context.Background(), http.MethodDelete, "/api/v1/things/1", nil, nil); err != nil {
+			t.Errorf("nil out: %v", err)
+		}
+		var out struct{}
+		if err := client.Do(context.Background(), http.MethodDelete, "/api/v1/things/1", nil, &out); err != nil {
+			t.Errorf("empty body: %v", err)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		client, err := NewClient()
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		if err := client.Do(context.Background(), http.MethodGet, "/api/v1/x", nil, nil); !errors.Is(err, ErrMissingAnyKey) {
+			t.Errorf("error = %v, want ErrMissingAnyKey", err)
+		}
+	})
+}
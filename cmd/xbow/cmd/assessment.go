@@ -2,8 +2,17 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"iter"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/rsclarke/xbow"
 	"github.com/spf13/cobra"
@@ -23,6 +32,7 @@ func init() {
 	assessmentCmd.AddCommand(assessmentCancelCmd)
 	assessmentCmd.AddCommand(assessmentPauseCmd)
 	assessmentCmd.AddCommand(assessmentResumeCmd)
+	assessmentCmd.AddCommand(assessmentWatchCmd)
 }
 
 var assessmentGetCmd = &cobra.Command{
@@ -48,6 +58,7 @@ var (
 	createAssetID       string
 	createAttackCredits int64
 	createObjective     string
+	createObjectiveFile string
 )
 
 var assessmentCreateCmd = &cobra.Command{
@@ -59,11 +70,14 @@ var assessmentCreateCmd = &cobra.Command{
 			return err
 		}
 
+		objective, err := loadObjective(createObjective, createObjectiveFile, os.Stdin)
+		if err != nil {
+			return err
+		}
+
 		req := &xbow.CreateAssessmentRequest{
 			AttackCredits: createAttackCredits,
-		}
-		if createObjective != "" {
-			req.Objective = &createObjective
+			Objective:     objective,
 		}
 
 		assessment, err := client.Assessments.Create(context.Background(), createAssetID, req)
@@ -79,10 +93,43 @@ func init() {
 	assessmentCreateCmd.Flags().StringVar(&createAssetID, "asset-id", "", "Asset ID to create assessment for (required)")
 	assessmentCreateCmd.Flags().Int64Var(&createAttackCredits, "attack-credits", 0, "Number of attack credits to use (required)")
 	assessmentCreateCmd.Flags().StringVar(&createObjective, "objective", "", "Assessment objective")
+	assessmentCreateCmd.Flags().StringVar(&createObjectiveFile, "objective-file", "", "Read the assessment objective from a file (- for stdin)")
 	_ = assessmentCreateCmd.MarkFlagRequired("asset-id")
 	_ = assessmentCreateCmd.MarkFlagRequired("attack-credits")
 }
 
+// loadObjective returns the objective from --objective or --objective-file,
+// or nil if neither is set. A path of "-" reads from stdin.
+func loadObjective(objective, path string, stdin io.Reader) (*string, error) {
+	if objective != "" && path != "" {
+		return nil, errors.New("--objective and --objective-file cannot both be set")
+	}
+	if path == "" {
+		if objective == "" {
+			return nil, nil
+		}
+		return &objective, nil
+	}
+
+	var data []byte
+	var err error
+
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(filepath.Clean(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading objective file: %w", err)
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return nil, errors.New("objective file is empty")
+	}
+	return &content, nil
+}
+
 var (
 	listAssetID string
 	listLimit   int
@@ -169,9 +216,68 @@ var assessmentResumeCmd = &cobra.Command{
 	},
 }
 
+// watch
+
+var assessmentWatchInterval time.Duration
+
+var assessmentWatchCmd = &cobra.Command{
+	Use:   "watch <assessment-id>",
+	Short: "Follow an assessment's progress until it finishes",
+	Long: `Poll an assessment and print its state and progress whenever they change,
+until it reaches a terminal state. Exits non-zero if the assessment fails or
+is cancelled. Press Ctrl-C to stop watching; the assessment keeps running.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return watchAssessment(ctx, client, args[0], assessmentWatchInterval)
+	},
+}
+
+func init() {
+	assessmentWatchCmd.Flags().DurationVar(&assessmentWatchInterval, "interval", 5*time.Second, "Time between polls")
+}
+
+// watchAssessment prints a line each time the assessment's state or progress
+// changes and returns an error if it ends failed or cancelled. With
+// structured output only the final assessment is printed.
+func watchAssessment(ctx context.Context, client *xbow.Client, id string, interval time.Duration) error {
+	var last *xbow.Assessment
+	for a, err := range client.Assessments.Watch(ctx, id, interval) {
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return errors.New("stopped watching")
+			}
+			return err
+		}
+		if !structuredOutput() && (last == nil || a.State != last.State || a.Progress != last.Progress) {
+			_, _ = fmt.Fprintf(stdout, "%s  %-24s %5.1f%%\n", time.Now().Format("15:04:05"), a.State, a.Progress*100)
+		}
+		last = a
+	}
+
+	if structuredOutput() {
+		if err := printStructured(last); err != nil {
+			return err
+		}
+	}
+
+	switch last.State {
+	case xbow.AssessmentStateFailed, xbow.AssessmentStateCancelled:
+		return fmt.Errorf("assessment %s %s", id, last.State)
+	}
+	return nil
+}
+
 func printAssessment(a *xbow.Assessment) error {
-	if outputFormat == "json" {
-		return printJSON(a)
+	if structuredOutput() {
+		return printStructured(a)
 	}
 
 	w := newTabWriter()
@@ -183,11 +289,28 @@ func printAssessment(a *xbow.Assessment) error {
 	printRow(w, "ATTACK CREDITS:", a.AttackCredits)
 	printRow(w, "CREATED:", a.CreatedAt.Format("2006-01-02 15:04:05"))
 	printRow(w, "UPDATED:", a.UpdatedAt.Format("2006-01-02 15:04:05"))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(a.RecentEvents) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintln(stdout, "\nRECENT EVENTS:")
+	w = newTabWriter()
+	printRow(w, "TIMESTAMP", "EVENT", "REASON")
+	for _, e := range a.RecentEvents {
+		reason := e.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		printRow(w, e.Timestamp.Format("2006-01-02 15:04:05"), e.Name, reason)
+	}
 	return w.Flush()
 }
 
 func printAssessmentList(iter iter.Seq2[xbow.AssessmentListItem, error]) error {
-	if outputFormat == "json" {
+	if structuredOutput() {
 		var items []xbow.AssessmentListItem
 		for a, err := range iter {
 			if err != nil {
@@ -195,16 +318,31 @@ func printAssessmentList(iter iter.Seq2[xbow.AssessmentListItem, error]) error {
 			}
 			items = append(items, a)
 		}
-		return printJSON(items)
+		return printStructured(items)
+	}
+
+	if csvOutput() {
+		return printCSV(iter, []string{"id", "name", "state", "progress", "created_at", "updated_at"}, func(a xbow.AssessmentListItem) []string {
+			return []string{a.ID, a.Name, string(a.State), strconv.FormatFloat(a.Progress, 'f', -1, 64), csvTime(a.CreatedAt), csvTime(a.UpdatedAt)}
+		})
 	}
 
 	w := newTabWriter()
-	printRow(w, "ID", "NAME", "STATE", "PROGRESS", "CREATED")
+	if wideOutput() {
+		printRow(w, "ID", "NAME", "STATE", "PROGRESS", "CREATED", "UPDATED")
+	} else {
+		printRow(w, "ID", "NAME", "STATE", "PROGRESS", "CREATED")
+	}
 	for a, err := range iter {
 		if err != nil {
 			return err
 		}
-		printRow(w, a.ID, a.Name, a.State, fmt.Sprintf("%.1f%%", a.Progress*100), a.CreatedAt.Format("2006-01-02"))
+		progress := fmt.Sprintf("%.1f%%", a.Progress*100)
+		if wideOutput() {
+			printRow(w, a.ID, a.Name, a.State, progress, a.CreatedAt.Format("2006-01-02 15:04:05"), a.UpdatedAt.Format("2006-01-02 15:04:05"))
+			continue
+		}
+		printRow(w, a.ID, a.Name, a.State, progress, a.CreatedAt.Format("2006-01-02"))
 	}
 	return w.Flush()
 }
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rsclarke/xbow"
+)
+
+func TestLoadObjective(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "objective.md")
+	if err := os.WriteFile(file, []byte("Find auth bypasses.\nFocus on /admin.\n"), 0o600); err != nil {
+		t.Fatalf("writing objective file: %v", err)
+	}
+	empty := filepath.Join(dir, "empty.md")
+	if err := os.WriteFile(empty, []byte("\n"), 0o600); err != nil {
+		t.Fatalf("writing empty file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		objective string
+		path      string
+		stdin     string
+		want      *string
+		wantErr   string
+	}{
+		{
+			name: "neither set",
+		},
+		{
+			name:      "inline objective",
+			objective: "Find XSS",
+			want:      strPtr("Find XSS"),
+		},
+		{
+			name: "from file",
+			path: file,
+			want: strPtr("Find auth bypasses.\nFocus on /admin."),
+		},
+		{
+			name:  "from stdin",
+			path:  "-",
+			stdin: "Objective from stdin\n",
+			want:  strPtr("Objective from stdin"),
+		},
+		{
+			name:      "both set",
+			objective: "Find XSS",
+			path:      file,
+			wantErr:   "cannot both be set",
+		},
+		{
+			name:    "missing file",
+			path:    filepath.Join(dir, "missing.md"),
+			wantErr: "reading objective file",
+		},
+		{
+			name:    "empty file",
+			path:    empty,
+			wantErr: "objective file is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadObjective(tt.objective, tt.path, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if got != nil && *got != *tt.want {
+				t.Errorf("got %q, want %q", *got, *tt.want)
+			}
+		})
+	}
+}
+
+// assessmentStates serves each state in turn, repeating the last one.
+func assessmentStates(states ...string) http.Handler {
+	var calls atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := min(int(calls.Add(1))-1, len(states)-1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"id":"assess-1","name":"Nightly","assetId":"asset-1","organizationId":"org-1","state":%q,"progress":%d,"attackCredits":10,"recentEvents":[],"createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}`, states[i], i%2)
+	})
+}
+
+func TestAssessmentWatch(t *testing.T) {
+	t.Run("running to succeeded", func(t *testing.T) {
+		out, err := runCLI(t, assessmentStates("running", "running", "succeeded"), "assessment", "watch", "assess-1", "--interval", "1ms")
+		if err != nil {
+			t.Fatalf("watch error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("got %d lines, want one per change:\n%s", len(lines), out)
+		}
+		if !strings.Contains(lines[0], "running") || !strings.Contains(lines[0], "0.0%") {
+			t.Errorf("first line = %q", lines[0])
+		}
+		if !strings.Contains(lines[2], "succeeded") {
+			t.Errorf("last line = %q, want succeeded", lines[2])
+		}
+	})
+
+	t.Run("failed exits with error", func(t *testing.T) {
+		_, err := runCLI(t, assessmentStates("running", "failed"), "assessment", "watch", "assess-1", "--interval", "1ms")
+		if err == nil || err.Error() != "assessment assess-1 failed" {
+			t.Errorf("error = %v, want assessment assess-1 failed", err)
+		}
+	})
+
+	t.Run("json prints final assessment", func(t *testing.T) {
+		out, err := runCLI(t, assessmentStates("running", "succeeded"), "assessment", "watch", "assess-1", "--interval", "1ms", "-o", "json")
+		if err != nil {
+			t.Fatalf("watch error = %v", err)
+		}
+		if !strings.Contains(out, `"state": "succeeded"`) || strings.Contains(out, `"state": "running"`) {
+			t.Errorf("output = %s, want only the final assessment", out)
+		}
+	})
+}
+
+func TestPrintAssessment(t *testing.T) {
+	a := &xbow.Assessment{
+		ID:            "assess-1",
+		Name:          "Nightly",
+		State:         xbow.AssessmentStatePaused,
+		AttackCredits: 10,
+		RecentEvents: []xbow.AssessmentEvent{{
+			Name:      "auto-paused",
+			Type:      xbow.AssessmentEventTypeAutoPaused,
+			Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Reason:    "outside-time-window",
+		}},
+	}
+
+	out := captureOutput(t, formatTable, func() error { return printAssessment(a) })
+	for _, want := range []string{"RECENT EVENTS:", "REASON"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if !regexp.MustCompile(`2026-01-02 03:04:05\s+auto-paused\s+outside-time-window`).MatchString(out) {
+		t.Errorf("output has no row for the auto-paused event:\n%s", out)
+	}
+
+	a.RecentEvents = nil
+	out = captureOutput(t, formatTable, func() error { return printAssessment(a) })
+	if strings.Contains(out, "RECENT EVENTS:") {
+		t.Errorf("output shows an empty events section:\n%s", out)
+	}
+}
@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
+	"net/textproto"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/rsclarke/xbow"
 	"github.com/spf13/cobra"
@@ -25,6 +31,7 @@ func init() {
 	assetCmd.AddCommand(assetCreateCmd)
 	assetCmd.AddCommand(assetListCmd)
 	assetCmd.AddCommand(assetUpdateCmd)
+	assetCmd.AddCommand(assetWaitChecksCmd)
 }
 
 // get
@@ -65,7 +72,12 @@ var assetCreateCmd = &cobra.Command{
 			return err
 		}
 
-		asset, err := client.Assets.Create(context.Background(), assetCreateOrgID, &xbow.CreateAssetRequest{
+		orgID, err := orgIDFlag(assetCreateOrgID)
+		if err != nil {
+			return err
+		}
+
+		asset, err := client.Assets.Create(context.Background(), orgID, &xbow.CreateAssetRequest{
 			Name: assetCreateName,
 			Sku:  assetCreateSku,
 		})
@@ -78,10 +90,9 @@ var assetCreateCmd = &cobra.Command{
 }
 
 func init() {
-	assetCreateCmd.Flags().StringVar(&assetCreateOrgID, "org-id", "", "Organization ID (required)")
+	assetCreateCmd.Flags().StringVar(&assetCreateOrgID, "org-id", "", "Organization ID (required unless the config profile sets orgId)")
 	assetCreateCmd.Flags().StringVar(&assetCreateName, "name", "", "Asset name (required)")
 	assetCreateCmd.Flags().StringVar(&assetCreateSku, "sku", "standard-sku", "Asset SKU")
-	_ = assetCreateCmd.MarkFlagRequired("org-id")
 	_ = assetCreateCmd.MarkFlagRequired("name")
 }
 
@@ -106,14 +117,18 @@ var assetListCmd = &cobra.Command{
 			opts = &xbow.ListOptions{Limit: assetListLimit}
 		}
 
-		return printAssetList(client.Assets.AllByOrganization(context.Background(), assetListOrgID, opts))
+		orgID, err := orgIDFlag(assetListOrgID)
+		if err != nil {
+			return err
+		}
+
+		return printAssetList(client.Assets.AllByOrganization(context.Background(), orgID, opts))
 	},
 }
 
 func init() {
-	assetListCmd.Flags().StringVar(&assetListOrgID, "org-id", "", "Organization ID (required)")
+	assetListCmd.Flags().StringVar(&assetListOrgID, "org-id", "", "Organization ID (required unless the config profile sets orgId)")
 	assetListCmd.Flags().IntVar(&assetListLimit, "limit", 0, "Maximum number of results per page")
-	_ = assetListCmd.MarkFlagRequired("org-id")
 }
 
 // update
@@ -133,7 +148,8 @@ var (
 var assetUpdateCmd = &cobra.Command{
 	Use:   "update <asset-id>",
 	Short: "Update an asset",
-	Long: `Update an asset by ID. Fetches the current asset, applies changes, and saves.
+	Long: `Update an asset by ID. Only the fields given by flags change; the rest keep
+their current values.
 
 Simple fields:
   --name, --start-url, --max-rps, --sku
@@ -141,8 +157,8 @@ Simple fields:
 Repeatable structured fields:
   --header "Key: Value"
   --credential "name=n,type=basic,username=u,password=p"
-  --dns-rule "action=allow-attack,type=hostname,filter=example.com"
-  --http-rule "action=deny,type=url,filter=https://evil.com"
+  --dns-rule "action=allow-attack,type=glob,filter=example.com"
+  --http-rule "action=deny,type=prefix,filter=https://evil.com"
 
   Optional sub-fields for --credential: email-address, authenticator-uri
   Optional sub-fields for --dns-rule/--http-rule: id, include-subdomains
@@ -158,63 +174,24 @@ Full replacement from JSON file:
 
 		ctx := context.Background()
 
-		var req *xbow.UpdateAssetRequest
-
 		if assetUpdateFromFile != "" {
-			req, err = loadUpdateRequestFromFile(assetUpdateFromFile)
+			req, err := loadUpdateRequestFromFile(assetUpdateFromFile)
 			if err != nil {
 				return err
 			}
-		} else {
-			current, err := client.Assets.Get(ctx, args[0])
+			asset, err := client.Assets.Update(ctx, args[0], req)
 			if err != nil {
-				return fmt.Errorf("fetching current asset: %w", err)
+				return err
 			}
-			req = updateRequestFromAsset(current)
+			return printAsset(asset)
+		}
 
-			if cmd.Flags().Changed("name") {
-				req.Name = assetUpdateName
-			}
-			if cmd.Flags().Changed("start-url") {
-				req.StartURL = assetUpdateStartURL
-			}
-			if cmd.Flags().Changed("max-rps") {
-				req.MaxRequestsPerSecond = assetUpdateMaxRPS
-			}
-			if cmd.Flags().Changed("sku") {
-				req.Sku = &assetUpdateSku
-			}
-			if cmd.Flags().Changed("header") {
-				headers, err := parseHeaders(assetUpdateHeaders)
-				if err != nil {
-					return err
-				}
-				req.Headers = headers
-			}
-			if cmd.Flags().Changed("credential") {
-				creds, err := parseCredentials(assetUpdateCredentials)
-				if err != nil {
-					return err
-				}
-				req.Credentials = creds
-			}
-			if cmd.Flags().Changed("dns-rule") {
-				rules, err := parseDNSRules(assetUpdateDNSRules)
-				if err != nil {
-					return err
-				}
-				req.DNSBoundaryRules = rules
-			}
-			if cmd.Flags().Changed("http-rule") {
-				rules, err := parseHTTPRules(assetUpdateHTTPRules)
-				if err != nil {
-					return err
-				}
-				req.HTTPBoundaryRules = rules
-			}
+		req, err := patchRequestFromFlags(cmd)
+		if err != nil {
+			return err
 		}
 
-		asset, err := client.Assets.Update(ctx, args[0], req)
+		asset, err := client.Assets.Patch(ctx, args[0], req)
 		if err != nil {
 			return err
 		}
@@ -223,6 +200,53 @@ Full replacement from JSON file:
 	},
 }
 
+// patchRequestFromFlags builds a PatchAssetRequest from the update flags
+// that were set.
+func patchRequestFromFlags(cmd *cobra.Command) (*xbow.PatchAssetRequest, error) {
+	req := &xbow.PatchAssetRequest{}
+	if cmd.Flags().Changed("name") {
+		req.Name = &assetUpdateName
+	}
+	if cmd.Flags().Changed("start-url") {
+		req.StartURL = &assetUpdateStartURL
+	}
+	if cmd.Flags().Changed("max-rps") {
+		req.MaxRequestsPerSecond = &assetUpdateMaxRPS
+	}
+	if cmd.Flags().Changed("sku") {
+		req.Sku = &assetUpdateSku
+	}
+	if cmd.Flags().Changed("header") {
+		headers, err := parseHeaders(assetUpdateHeaders)
+		if err != nil {
+			return nil, err
+		}
+		req.Headers = &headers
+	}
+	if cmd.Flags().Changed("credential") {
+		creds, err := parseCredentials(assetUpdateCredentials)
+		if err != nil {
+			return nil, err
+		}
+		req.Credentials = &creds
+	}
+	if cmd.Flags().Changed("dns-rule") {
+		rules, err := parseDNSRules(assetUpdateDNSRules)
+		if err != nil {
+			return nil, err
+		}
+		req.DNSBoundaryRules = &rules
+	}
+	if cmd.Flags().Changed("http-rule") {
+		rules, err := parseHTTPRules(assetUpdateHTTPRules)
+		if err != nil {
+			return nil, err
+		}
+		req.HTTPBoundaryRules = &rules
+	}
+	return req, nil
+}
+
 func init() {
 	assetUpdateCmd.Flags().StringVar(&assetUpdateName, "name", "", "Asset name")
 	assetUpdateCmd.Flags().StringVar(&assetUpdateStartURL, "start-url", "", "Start URL")
@@ -230,33 +254,15 @@ func init() {
 	assetUpdateCmd.Flags().StringVar(&assetUpdateSku, "sku", "", "Asset SKU")
 	assetUpdateCmd.Flags().StringArrayVar(&assetUpdateHeaders, "header", nil, `Header in "Key: Value" format (repeatable)`)
 	assetUpdateCmd.Flags().StringArrayVar(&assetUpdateCredentials, "credential", nil, `Credential as "name=n,type=basic,username=u,password=p" (repeatable)`)
-	assetUpdateCmd.Flags().StringArrayVar(&assetUpdateDNSRules, "dns-rule", nil, `DNS boundary rule as "action=allow-attack,type=hostname,filter=example.com" (repeatable)`)
-	assetUpdateCmd.Flags().StringArrayVar(&assetUpdateHTTPRules, "http-rule", nil, `HTTP boundary rule as "action=deny,type=url,filter=https://example.com" (repeatable)`)
+	assetUpdateCmd.Flags().StringArrayVar(&assetUpdateDNSRules, "dns-rule", nil, `DNS boundary rule as "action=allow-attack,type=glob,filter=example.com" (repeatable)`)
+	assetUpdateCmd.Flags().StringArrayVar(&assetUpdateHTTPRules, "http-rule", nil, `HTTP boundary rule as "action=deny,type=prefix,filter=https://example.com" (repeatable)`)
 	assetUpdateCmd.Flags().StringVar(&assetUpdateFromFile, "from-file", "", "Load full update request from JSON file (- for stdin)")
 }
 
-// updateRequestFromAsset builds an UpdateAssetRequest from the current asset state.
-func updateRequestFromAsset(a *xbow.Asset) *xbow.UpdateAssetRequest {
-	req := &xbow.UpdateAssetRequest{
-		Name:              a.Name,
-		Sku:               &a.Sku,
-		Credentials:       a.Credentials,
-		DNSBoundaryRules:  a.DNSBoundaryRules,
-		Headers:           a.Headers,
-		HTTPBoundaryRules: a.HTTPBoundaryRules,
-	}
-	if a.StartURL != nil {
-		req.StartURL = *a.StartURL
-	}
-	if a.MaxRequestsPerSecond != nil {
-		req.MaxRequestsPerSecond = *a.MaxRequestsPerSecond
-	}
-	if a.ApprovedTimeWindows != nil {
-		req.ApprovedTimeWindows = a.ApprovedTimeWindows
-	}
-	return req
-}
-
+// loadUpdateRequestFromFile reads an UpdateAssetRequest from a JSON file,
+// or stdin for "-". Unknown fields are rejected rather than ignored, and the
+// request is validated, so mistakes are reported with their JSON path before
+// anything is sent.
 func loadUpdateRequestFromFile(path string) (*xbow.UpdateAssetRequest, error) {
 	var data []byte
 	var err error
@@ -271,14 +277,20 @@ func loadUpdateRequestFromFile(path string) (*xbow.UpdateAssetRequest, error) {
 	}
 
 	var req xbow.UpdateAssetRequest
-	if err := json.Unmarshal(data, &req); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
 		return nil, fmt.Errorf("parsing JSON: %w", err)
 	}
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid update request: %w", err)
+	}
 	return &req, nil
 }
 
-// parseHeaders parses "Key: Value" strings into a header map.
-// Multiple values for the same key are accumulated.
+// parseHeaders parses "Key: Value" strings into a header map. Keys are
+// canonicalized, so values for "x-custom" and "X-Custom" accumulate under
+// "X-Custom".
 func parseHeaders(raw []string) (map[string][]string, error) {
 	if len(raw) == 0 {
 		return nil, nil
@@ -294,6 +306,7 @@ func parseHeaders(raw []string) (map[string][]string, error) {
 		if key == "" {
 			return nil, fmt.Errorf("empty header key in %q", h)
 		}
+		key = textproto.CanonicalMIMEHeaderKey(key)
 		headers[key] = append(headers[key], value)
 	}
 	return headers, nil
@@ -383,6 +396,9 @@ func parseDNSRules(raw []string) ([]xbow.DNSBoundaryRule, error) {
 			b := v == "true"
 			rule.IncludeSubdomains = &b
 		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rule %q: %w", s, err)
+		}
 		rules = append(rules, rule)
 	}
 	return rules, nil
@@ -416,16 +432,92 @@ func parseHTTPRules(raw []string) ([]xbow.HTTPBoundaryRule, error) {
 			b := v == "true"
 			rule.IncludeSubdomains = &b
 		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rule %q: %w", s, err)
+		}
 		rules = append(rules, rule)
 	}
 	return rules, nil
 }
 
+// wait-checks
+
+var assetWaitChecksTimeout time.Duration
+
+var assetWaitChecksCmd = &cobra.Command{
+	Use:   "wait-checks <asset-id>",
+	Short: "Wait for an asset's checks to finish",
+	Long: `Poll an asset until none of its checks is unchecked or checking, then
+print it. Exits non-zero if any check ended invalid. Run it after creating
+or updating an asset, before starting an assessment.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if assetWaitChecksTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, assetWaitChecksTimeout)
+			defer cancel()
+		}
+
+		asset, err := client.Assets.WaitForChecks(ctx, args[0])
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return fmt.Errorf("asset %s checks did not finish within %s", args[0], assetWaitChecksTimeout)
+		case errors.Is(err, context.Canceled):
+			return errors.New("stopped waiting")
+		case err != nil:
+			return err
+		}
+
+		if err := printAsset(asset); err != nil {
+			return err
+		}
+		return invalidChecksError(asset)
+	},
+}
+
+func init() {
+	assetWaitChecksCmd.Flags().DurationVar(&assetWaitChecksTimeout, "timeout", 0, "Give up after this long (default: wait indefinitely)")
+}
+
+// invalidChecksError returns an error naming each of the asset's checks
+// that ended invalid, or nil if none did.
+func invalidChecksError(a *xbow.Asset) error {
+	var invalid []string
+	for _, c := range []struct {
+		name  string
+		check xbow.AssetCheck
+	}{
+		{"reachable", a.Checks.AssetReachable},
+		{"credentials", a.Checks.Credentials},
+		{"dns rules", a.Checks.DNSBoundaryRules},
+	} {
+		if c.check.State != xbow.AssetCheckStateInvalid {
+			continue
+		}
+		if c.check.Message != "" {
+			invalid = append(invalid, c.name+" ("+c.check.Message+")")
+		} else {
+			invalid = append(invalid, c.name)
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return fmt.Errorf("asset %s has invalid checks: %s", a.ID, strings.Join(invalid, ", "))
+}
+
 // output helpers
 
 func printAsset(a *xbow.Asset) error {
-	if outputFormat == "json" {
-		return printJSON(a)
+	if structuredOutput() {
+		return printStructured(a)
 	}
 
 	w := newTabWriter()
@@ -466,7 +558,7 @@ func printAsset(a *xbow.Asset) error {
 }
 
 func printAssetList(iter iter.Seq2[xbow.AssetListItem, error]) error {
-	if outputFormat == "json" {
+	if structuredOutput() {
 		var items []xbow.AssetListItem
 		for a, err := range iter {
 			if err != nil {
@@ -474,15 +566,29 @@ func printAssetList(iter iter.Seq2[xbow.AssetListItem, error]) error {
 			}
 			items = append(items, a)
 		}
-		return printJSON(items)
+		return printStructured(items)
+	}
+
+	if csvOutput() {
+		return printCSV(iter, []string{"id", "name", "lifecycle", "created_at", "updated_at"}, func(a xbow.AssetListItem) []string {
+			return []string{a.ID, a.Name, string(a.Lifecycle), csvTime(a.CreatedAt), csvTime(a.UpdatedAt)}
+		})
 	}
 
 	w := newTabWriter()
-	printRow(w, "ID", "NAME", "LIFECYCLE", "CREATED")
+	if wideOutput() {
+		printRow(w, "ID", "NAME", "LIFECYCLE", "CREATED", "UPDATED")
+	} else {
+		printRow(w, "ID", "NAME", "LIFECYCLE", "CREATED")
+	}
 	for a, err := range iter {
 		if err != nil {
 			return err
 		}
+		if wideOutput() {
+			printRow(w, a.ID, a.Name, a.Lifecycle, a.CreatedAt.Format("2006-01-02 15:04:05"), a.UpdatedAt.Format("2006-01-02 15:04:05"))
+			continue
+		}
 		printRow(w, a.ID, a.Name, a.Lifecycle, a.CreatedAt.Format("2006-01-02"))
 	}
 	return w.Flush()
@@ -1,8 +1,16 @@
 package cmd
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/rsclarke/xbow"
 )
@@ -49,6 +57,11 @@ func TestParseHeaders(t *testing.T) {
 			input: []string{"Authorization: Bearer token:123"},
 			want:  map[string][]string{"Authorization": {"Bearer token:123"}},
 		},
+		{
+			name:  "case variants merge",
+			input: []string{"x-custom: val1", "X-CUSTOM: val2"},
+			want:  map[string][]string{"X-Custom": {"val1", "val2"}},
+		},
 		{
 			name:    "missing colon",
 			input:   []string{"InvalidHeader"},
@@ -207,46 +220,46 @@ func TestParseDNSRules(t *testing.T) {
 	}{
 		{
 			name:  "basic rule",
-			input: []string{"action=allow-attack,type=hostname,filter=example.com"},
+			input: []string{"action=allow-attack,type=glob,filter=example.com"},
 			want: []xbow.DNSBoundaryRule{
-				{Action: xbow.DNSBoundaryRuleActionAllowAttack, Type: "hostname", Filter: "example.com"},
+				{Action: xbow.DNSBoundaryRuleActionAllowAttack, Type: "glob", Filter: "example.com"},
 			},
 		},
 		{
 			name:  "with include-subdomains true",
-			input: []string{"action=allow-visit,type=hostname,filter=example.com,include-subdomains=true"},
+			input: []string{"action=allow-visit,type=glob,filter=example.com,include-subdomains=true"},
 			want: []xbow.DNSBoundaryRule{
-				{Action: xbow.DNSBoundaryRuleActionAllowVisit, Type: "hostname", Filter: "example.com", IncludeSubdomains: boolPtr(true)},
+				{Action: xbow.DNSBoundaryRuleActionAllowVisit, Type: "glob", Filter: "example.com", IncludeSubdomains: boolPtr(true)},
 			},
 		},
 		{
 			name:  "with include-subdomains false",
-			input: []string{"action=deny,type=hostname,filter=evil.com,include-subdomains=false"},
+			input: []string{"action=deny,type=glob,filter=evil.com,include-subdomains=false"},
 			want: []xbow.DNSBoundaryRule{
-				{Action: xbow.DNSBoundaryRuleActionDeny, Type: "hostname", Filter: "evil.com", IncludeSubdomains: boolPtr(false)},
+				{Action: xbow.DNSBoundaryRuleActionDeny, Type: "glob", Filter: "evil.com", IncludeSubdomains: boolPtr(false)},
 			},
 		},
 		{
 			name:  "with id",
-			input: []string{"id=rule-1,action=allow-attack,type=hostname,filter=example.com"},
+			input: []string{"id=rule-1,action=allow-attack,type=glob,filter=example.com"},
 			want: []xbow.DNSBoundaryRule{
-				{ID: "rule-1", Action: xbow.DNSBoundaryRuleActionAllowAttack, Type: "hostname", Filter: "example.com"},
+				{ID: "rule-1", Action: xbow.DNSBoundaryRuleActionAllowAttack, Type: "glob", Filter: "example.com"},
 			},
 		},
 		{
 			name: "multiple rules",
 			input: []string{
-				"action=allow-attack,type=hostname,filter=a.com",
-				"action=deny,type=hostname,filter=b.com",
+				"action=allow-attack,type=glob,filter=a.com",
+				"action=deny,type=glob,filter=b.com",
 			},
 			want: []xbow.DNSBoundaryRule{
-				{Action: xbow.DNSBoundaryRuleActionAllowAttack, Type: "hostname", Filter: "a.com"},
-				{Action: xbow.DNSBoundaryRuleActionDeny, Type: "hostname", Filter: "b.com"},
+				{Action: xbow.DNSBoundaryRuleActionAllowAttack, Type: "glob", Filter: "a.com"},
+				{Action: xbow.DNSBoundaryRuleActionDeny, Type: "glob", Filter: "b.com"},
 			},
 		},
 		{
 			name:    "missing action",
-			input:   []string{"type=hostname,filter=example.com"},
+			input:   []string{"type=glob,filter=example.com"},
 			wantErr: true,
 		},
 		{
@@ -256,7 +269,22 @@ func TestParseDNSRules(t *testing.T) {
 		},
 		{
 			name:    "missing filter",
-			input:   []string{"action=deny,type=hostname"},
+			input:   []string{"action=deny,type=glob"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			input:   []string{"action=allowattack,type=glob,filter=example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "allow-auth action",
+			input:   []string{"action=allow-auth,type=glob,filter=example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			input:   []string{"action=deny,type=hostname,filter=example.com"},
 			wantErr: true,
 		},
 	}
@@ -283,35 +311,35 @@ func TestParseHTTPRules(t *testing.T) {
 	}{
 		{
 			name:  "basic rule",
-			input: []string{"action=deny,type=url,filter=https://evil.com"},
+			input: []string{"action=deny,type=prefix,filter=https://evil.com"},
 			want: []xbow.HTTPBoundaryRule{
-				{Action: xbow.HTTPBoundaryRuleActionDeny, Type: "url", Filter: "https://evil.com"},
+				{Action: xbow.HTTPBoundaryRuleActionDeny, Type: "prefix", Filter: "https://evil.com"},
 			},
 		},
 		{
 			name:  "allow-auth action",
-			input: []string{"action=allow-auth,type=url,filter=https://login.example.com"},
+			input: []string{"action=allow-auth,type=prefix,filter=https://login.example.com"},
 			want: []xbow.HTTPBoundaryRule{
-				{Action: xbow.HTTPBoundaryRuleActionAllowAuth, Type: "url", Filter: "https://login.example.com"},
+				{Action: xbow.HTTPBoundaryRuleActionAllowAuth, Type: "prefix", Filter: "https://login.example.com"},
 			},
 		},
 		{
 			name:  "with include-subdomains",
-			input: []string{"action=allow-attack,type=url,filter=https://example.com,include-subdomains=true"},
+			input: []string{"action=allow-attack,type=prefix,filter=https://example.com,include-subdomains=true"},
 			want: []xbow.HTTPBoundaryRule{
-				{Action: xbow.HTTPBoundaryRuleActionAllowAttack, Type: "url", Filter: "https://example.com", IncludeSubdomains: boolPtr(true)},
+				{Action: xbow.HTTPBoundaryRuleActionAllowAttack, Type: "prefix", Filter: "https://example.com", IncludeSubdomains: boolPtr(true)},
 			},
 		},
 		{
 			name:  "with id",
-			input: []string{"id=rule-1,action=allow-visit,type=url,filter=https://example.com"},
+			input: []string{"id=rule-1,action=allow-visit,type=prefix,filter=https://example.com"},
 			want: []xbow.HTTPBoundaryRule{
-				{ID: "rule-1", Action: xbow.HTTPBoundaryRuleActionAllowVisit, Type: "url", Filter: "https://example.com"},
+				{ID: "rule-1", Action: xbow.HTTPBoundaryRuleActionAllowVisit, Type: "prefix", Filter: "https://example.com"},
 			},
 		},
 		{
 			name:    "missing action",
-			input:   []string{"type=url,filter=https://example.com"},
+			input:   []string{"type=prefix,filter=https://example.com"},
 			wantErr: true,
 		},
 		{
@@ -321,7 +349,17 @@ func TestParseHTTPRules(t *testing.T) {
 		},
 		{
 			name:    "missing filter",
-			input:   []string{"action=deny,type=url"},
+			input:   []string{"action=deny,type=prefix"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			input:   []string{"action=block,type=prefix,filter=https://example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			input:   []string{"action=deny,type=url,filter=https://example.com"},
 			wantErr: true,
 		},
 	}
@@ -341,3 +379,116 @@ func TestParseHTTPRules(t *testing.T) {
 
 func strPtr(s string) *string { return &s }
 func boolPtr(b bool) *bool    { return &b }
+
+// testAssetWithChecksJSON returns an asset whose credentials check is in
+// the given state, with message as its message.
+func testAssetWithChecksJSON(credentials, message string) string {
+	return fmt.Sprintf(`{"id":"asset-1","name":"App","organizationId":"org-1","startUrl":"https://app.example.com","maxRequestsPerSecond":10,"sku":"standard","lifecycle":"active","checks":{"assetReachable":{"state":"valid","message":""},"credentials":{"state":%q,"message":%q},"dnsBoundaryRules":{"state":"valid","message":""},"updatedAt":null},"approvedTimeWindows":null,"credentials":[],"dnsBoundaryRules":[],"headers":{},"httpBoundaryRules":[],"archiveAt":null,"createdAt":"2026-01-01T00:00:00Z","updatedAt":"2026-01-01T00:00:00Z"}`, credentials, message)
+}
+
+func TestAssetWaitChecks(t *testing.T) {
+	run := func(t *testing.T, final string) (string, int32, error) {
+		t.Helper()
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/assets/asset-1" {
+				t.Errorf("path = %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if calls.Add(1) < 3 {
+				_, _ = w.Write([]byte(testAssetWithChecksJSON("checking", "")))
+				return
+			}
+			_, _ = w.Write([]byte(final))
+		}))
+		t.Cleanup(srv.Close)
+
+		oldOpts := extraClientOptions
+		extraClientOptions = []xbow.ClientOption{xbow.WithBaseURL(srv.URL), xbow.WithPollInterval(time.Millisecond)}
+		t.Cleanup(func() { extraClientOptions = oldOpts })
+
+		out, err := executeCLI(t, "--org-key", "test-key", "asset", "wait-checks", "asset-1")
+		return out, calls.Load(), err
+	}
+
+	t.Run("checking to valid", func(t *testing.T) {
+		out, calls, err := run(t, testAssetWithChecksJSON("valid", ""))
+		if err != nil {
+			t.Fatalf("wait-checks error = %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("polls = %d, want 3", calls)
+		}
+		if !strings.Contains(out, "CHECK CREDENTIALS:  valid") {
+			t.Errorf("output missing settled check:\n%s", out)
+		}
+	})
+
+	t.Run("checking to invalid", func(t *testing.T) {
+		_, _, err := run(t, testAssetWithChecksJSON("invalid", "login failed"))
+		if err == nil || !strings.Contains(err.Error(), "credentials (login failed)") {
+			t.Errorf("error = %v, want one naming the invalid credentials check", err)
+		}
+	})
+}
+
+func TestLoadUpdateRequestFromFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			json: `{"name":"App","startUrl":"https://app.example.com","maxRequestsPerSecond":5,
+				"httpBoundaryRules":[{"action":"deny","type":"prefix","filter":"https://app.example.com/logout"}]}`,
+		},
+		{
+			name:    "unknown field",
+			json:    `{"name":"App","startURL":"https://app.example.com","maxRps":5}`,
+			wantErr: `unknown field "maxRps"`,
+		},
+		{
+			name:    "unknown nested field",
+			json:    `{"name":"App","credentials":[{"name":"admin","type":"username-password","username":"u","passwd":"p"}]}`,
+			wantErr: `unknown field "passwd"`,
+		},
+		{
+			name:    "malformed rule",
+			json:    `{"name":"App","dnsBoundaryRules":[{"action":"allow-attack","type":"glob","filter":"a"},{"action":"deny","type":"hostname","filter":"b"}]}`,
+			wantErr: "dnsBoundaryRules[1].type",
+		},
+		{
+			name:    "bad time window",
+			json:    `{"name":"App","approvedTimeWindows":{"tz":"UTC","entries":[{"startWeekday":1,"startTime":"9am","endWeekday":5,"endTime":"17:00"}]}}`,
+			wantErr: "approvedTimeWindows.entries[0].startTime",
+		},
+		{
+			name:    "missing name",
+			json:    `{"startUrl":"https://app.example.com"}`,
+			wantErr: "name is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "asset.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := loadUpdateRequestFromFile(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("loadUpdateRequestFromFile() error = %v", err)
+				}
+				if req.Name != "App" || len(req.HTTPBoundaryRules) != 1 {
+					t.Errorf("loaded request = %+v", req)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
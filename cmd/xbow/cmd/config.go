@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+var (
+	configFile  string
+	profileName string
+)
+
+// cliConfig is the layout of the CLI configuration file.
+type cliConfig struct {
+	// DefaultProfile is used when neither --profile nor XBOW_PROFILE is set.
+	DefaultProfile string                `json:"defaultProfile,omitempty"`
+	Profiles       map[string]cliProfile `json:"profiles"`
+}
+
+// cliProfile holds one named set of defaults. Every field is optional, and
+// flags and environment variables take precedence over it.
+type cliProfile struct {
+	BaseURL        string `json:"baseUrl,omitempty"`
+	OrgKey         string `json:"orgKey,omitempty"`
+	IntegrationKey string `json:"integrationKey,omitempty"`
+	OrgID          string `json:"orgId,omitempty"`
+	IntegrationID  string `json:"integrationId,omitempty"`
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (or set XBOW_CONFIG env var; default $XDG_CONFIG_HOME/xbow/config.json)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Config profile to use (or set XBOW_PROFILE env var)")
+}
+
+// defaultConfigPath returns where the config file is read from when neither
+// --config nor XBOW_CONFIG is set.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "xbow", "config.json")
+}
+
+// loadProfile returns the active profile: the one named by --profile or
+// XBOW_PROFILE, or else the file's defaultProfile. It returns an empty
+// profile when no profile is selected, or when the default config file
+// does not exist and no profile was asked for.
+//
+// Like ssh with a private key, it refuses a config file that holds API
+// keys and that other users can read or write.
+func loadProfile() (cliProfile, error) {
+	path := flagOrEnv(configFile, "XBOW_CONFIG")
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	name := flagOrEnv(profileName, "XBOW_PROFILE")
+
+	data, mode, err := readConfigFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit && name == "" {
+		return cliProfile{}, nil
+	}
+	if err != nil {
+		return cliProfile{}, fmt.Errorf("reading config: %w", err)
+	}
+
+	var cfg cliConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cliProfile{}, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	if perm := mode.Perm(); perm&0o077 != 0 && runtime.GOOS != "windows" && cfg.hasKeys() {
+		return cliProfile{}, fmt.Errorf("config %s holds API keys but is accessible by other users (mode %04o): run chmod 600 %s", path, perm, path)
+	}
+
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	if name == "" {
+		return cliProfile{}, nil
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(cfg.Profiles))
+		for n := range cfg.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return cliProfile{}, fmt.Errorf("profile %q not found in %s (profiles: %s)", name, path, strings.Join(names, ", "))
+	}
+	return p, nil
+}
+
+// readConfigFile returns the contents and mode of the config file at path.
+// The mode comes from the open file, so it describes the contents read.
+func readConfigFile(path string) ([]byte, fs.FileMode, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, info.Mode(), nil
+}
+
+// hasKeys reports whether any profile in c holds an API key.
+func (c cliConfig) hasKeys() bool {
+	for _, p := range c.Profiles {
+		if p.OrgKey != "" || p.IntegrationKey != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setting returns the flag value if set, otherwise the named environment
+// variable, otherwise the profile's value.
+func setting(flag, env, fromProfile string) string {
+	if v := flagOrEnv(flag, env); v != "" {
+		return v
+	}
+	return fromProfile
+}
+
+// orgIDFlag returns the --org-id flag value, falling back to the active
+// profile's orgId.
+func orgIDFlag(flag string) (string, error) {
+	return idFlag(flag, "--org-id", "orgId", func(p cliProfile) string { return p.OrgID })
+}
+
+// integrationIDFlag returns the --integration-id flag value, falling back
+// to the active profile's integrationId.
+func integrationIDFlag(flag string) (string, error) {
+	return idFlag(flag, "--integration-id", "integrationId", func(p cliProfile) string { return p.IntegrationID })
+}
+
+func idFlag(flag, name, field string, fromProfile func(cliProfile) string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	p, err := loadProfile()
+	if err != nil {
+		return "", err
+	}
+	if id := fromProfile(p); id != "" {
+		return id, nil
+	}
+	return "", fmt.Errorf("%s is required (or set %s in a config profile)", name, field)
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keep a developer's own config file out of the tests.
+	dir, err := os.MkdirTemp("", "xbow-cli-test")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_CONFIG_HOME", dir)
+	_ = os.Setenv("HOME", dir)
+	_ = os.Unsetenv("XBOW_CONFIG")
+	_ = os.Unsetenv("XBOW_PROFILE")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// writeConfig writes a config file to a temporary directory and returns its
+// path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigProfilePrecedence(t *testing.T) {
+	unreachable := "http://127.0.0.1:1"
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		wantAuth string
+	}{
+		{
+			name:     "selected profile",
+			args:     []string{"--profile", "staging"},
+			wantAuth: "Bearer staging-key",
+		},
+		{
+			name:     "default profile",
+			wantAuth: "Bearer prod-key",
+		},
+		{
+			name:     "profile from env",
+			env:      map[string]string{"XBOW_PROFILE": "staging"},
+			wantAuth: "Bearer staging-key",
+		},
+		{
+			name:     "env overrides profile",
+			env:      map[string]string{"XBOW_ORG_KEY": "env-key"},
+			args:     []string{"--profile", "staging"},
+			wantAuth: "Bearer env-key",
+		},
+		{
+			name:     "flag overrides env and profile",
+			env:      map[string]string{"XBOW_ORG_KEY": "env-key"},
+			args:     []string{"--profile", "staging", "--org-key", "flag-key"},
+			wantAuth: "Bearer flag-key",
+		},
+		{
+			name:     "env base URL overrides profile",
+			env:      map[string]string{"XBOW_BASE_URL": "SERVER"},
+			args:     []string{"--profile", "broken"},
+			wantAuth: "Bearer broken-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var auths []string
+			srv := authServer(t, &auths)
+
+			config := writeConfig(t, `{
+				"defaultProfile": "prod",
+				"profiles": {
+					"prod": {"baseUrl": "`+srv.URL+`", "orgKey": "prod-key"},
+					"staging": {"baseUrl": "`+srv.URL+`", "orgKey": "staging-key"},
+					"broken": {"baseUrl": "`+unreachable+`", "orgKey": "broken-key"}
+				}
+			}`)
+			t.Setenv("XBOW_CONFIG", config)
+			for _, name := range []string{"XBOW_ORG_KEY", "XBOW_BASE_URL", "XBOW_PROFILE"} {
+				t.Setenv(name, strings.ReplaceAll(tt.env[name], "SERVER", srv.URL))
+			}
+
+			if _, err := executeCLI(t, append(tt.args, "webhook", "get", "wh-1")...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(auths) != 1 || auths[0] != tt.wantAuth {
+				t.Errorf("Authorization = %v, want %q", auths, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestConfigProfileDefaultIDs(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[],"nextCursor":null}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	config := writeConfig(t, `{"profiles": {"prod": {
+		"baseUrl": "`+srv.URL+`",
+		"orgKey": "k",
+		"integrationKey": "ik",
+		"orgId": "org-from-profile",
+		"integrationId": "int-from-profile"
+	}}}`)
+
+	for _, args := range [][]string{
+		{"asset", "list"},
+		{"asset", "list", "--org-id", "org-from-flag"},
+		{"webhook", "list"},
+		{"organization", "list"},
+	} {
+		if _, err := executeCLI(t, append([]string{"--config", config, "--profile", "prod"}, args...)...); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+	}
+
+	want := []string{
+		"/api/v1/organizations/org-from-profile/assets",
+		"/api/v1/organizations/org-from-flag/assets",
+		"/api/v1/organizations/org-from-profile/webhooks",
+		"/api/v1/integrations/int-from-profile/organizations",
+	}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestConfigProfileErrors(t *testing.T) {
+	config := writeConfig(t, `{"profiles": {"prod": {"orgKey": "k"}, "dev": {"orgKey": "k"}}}`)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "unknown profile",
+			args:    []string{"--config", config, "--profile", "staging", "webhook", "get", "wh-1"},
+			wantErr: `profile "staging" not found in ` + config + ` (profiles: dev, prod)`,
+		},
+		{
+			name:    "missing config file",
+			args:    []string{"--config", config + ".missing", "webhook", "get", "wh-1"},
+			wantErr: "reading config",
+		},
+		{
+			name:    "profile without default file",
+			args:    []string{"--profile", "prod", "webhook", "get", "wh-1"},
+			wantErr: "reading config",
+		},
+		{
+			name:    "missing org id",
+			args:    []string{"--config", config, "--profile", "prod", "asset", "list"},
+			wantErr: "--org-id is required (or set orgId in a config profile)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCLI(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		bad := writeConfig(t, `{"profiles": {"prod": {"org_key": "k"}}}`)
+		_, err := executeCLI(t, "--config", bad, "--profile", "prod", "webhook", "get", "wh-1")
+		if err == nil || !strings.Contains(err.Error(), `unknown field "org_key"`) {
+			t.Fatalf("error = %v, want unknown field", err)
+		}
+	})
+}
+
+func TestConfigPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes do not restrict access on Windows")
+	}
+	srv := authServer(t, new([]string))
+
+	tests := []struct {
+		name    string
+		content string
+		mode    os.FileMode
+		wantErr string
+	}{
+		{
+			name:    "private file with keys",
+			content: `{"profiles": {"prod": {"orgKey": "k"}}}`,
+			mode:    0o600,
+		},
+		{
+			name:    "world-readable file with keys",
+			content: `{"profiles": {"prod": {"orgKey": "k"}}}`,
+			mode:    0o644,
+			wantErr: "is accessible by other users (mode 0644): run chmod 600",
+		},
+		{
+			name:    "group-readable file with an integration key",
+			content: `{"profiles": {"prod": {"integrationKey": "k"}}}`,
+			mode:    0o640,
+			wantErr: "is accessible by other users (mode 0640)",
+		},
+		{
+			name:    "world-readable file without keys",
+			content: `{"profiles": {"prod": {"orgId": "org-1"}}}`,
+			mode:    0o644,
+			wantErr: "API key required: use --org-key/--integration-key or set XBOW_ORG_KEY/XBOW_INTEGRATION_KEY (or keys in a config profile)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := writeConfig(t, tt.content)
+			if err := os.Chmod(config, tt.mode); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("XBOW_BASE_URL", srv.URL)
+			t.Setenv("XBOW_ORG_KEY", "")
+			t.Setenv("XBOW_INTEGRATION_KEY", "")
+
+			_, err := executeCLI(t, "--config", config, "--profile", "prod", "webhook", "get", "wh-1")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maxWebhookClockSkew is the default clock skew allowed by webhook
+// signature verification (see xbow.WithMaxClockSkew).
+const maxWebhookClockSkew = 5 * time.Minute
+
+// doctorResult is what `xbow doctor` reports.
+type doctorResult struct {
+	BaseURL     string    `json:"baseUrl"`
+	Auth        string    `json:"auth"`
+	ServerTime  time.Time `json:"serverTime,omitzero"`
+	ClockOffset string    `json:"clockOffset,omitempty"`
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose connectivity, authentication and clock skew",
+	Long: `Check that the API is reachable and the organization key is accepted, and
+measure the local clock's offset from the server's. A positive offset means
+the local clock is behind. Webhook signature verification rejects deliveries
+when the offset exceeds the allowed skew (5m by default), so doctor fails
+in that case too.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		serverTime, offset, timeErr := client.Meta.GetServerTime(context.Background())
+		result := doctorResult{BaseURL: client.BaseURL(), Auth: pingStatus(timeErr)}
+		if timeErr == nil {
+			result.ServerTime = serverTime
+			result.ClockOffset = offset.Round(time.Second).String()
+		}
+
+		if structuredOutput() {
+			if err := printStructured(result); err != nil {
+				return err
+			}
+		} else {
+			w := newTabWriter()
+			printRow(w, "BASE URL:", result.BaseURL)
+			printRow(w, "AUTH:", result.Auth)
+			if timeErr == nil {
+				printRow(w, "SERVER TIME:", result.ServerTime.UTC().Format(time.RFC3339))
+				printRow(w, "CLOCK OFFSET:", result.ClockOffset)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+
+		if timeErr != nil {
+			return timeErr
+		}
+		if offset.Abs() > maxWebhookClockSkew {
+			return fmt.Errorf("local clock is off by %s, more than the %s webhook verification allows", result.ClockOffset, maxWebhookClockSkew)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rsclarke/xbow"
+)
+
+func TestDoctor(t *testing.T) {
+	withDate := func(offset time.Duration) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+			_, _ = w.Write([]byte(`[{"publicKey":"a2V5"}]`))
+		})
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		out, err := runCLI(t, withDate(0), "doctor")
+		if err != nil {
+			t.Fatalf("doctor error = %v", err)
+		}
+		for _, want := range []string{"AUTH:", "ok", "SERVER TIME:", "CLOCK OFFSET:"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("clock skew", func(t *testing.T) {
+		out, err := runCLI(t, withDate(-10*time.Minute), "doctor", "-o", "json")
+		if err == nil || !strings.Contains(err.Error(), "local clock is off by") {
+			t.Fatalf("error = %v, want clock skew error", err)
+		}
+		if !strings.Contains(out, `"clockOffset": "-10m`) && !strings.Contains(out, `"clockOffset": "-9m`) {
+			t.Errorf("output missing offset:\n%s", out)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		out, err := runCLI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"ERR_UNAUTHORIZED","error":"Unauthorized","message":"invalid API key"}`))
+		}), "doctor")
+		if !errors.Is(err, xbow.ErrUnauthorized) {
+			t.Fatalf("error = %v, want ErrUnauthorized", err)
+		}
+		if !strings.Contains(out, "unauthorized") || strings.Contains(out, "CLOCK OFFSET:") {
+			t.Errorf("output = %q", out)
+		}
+	})
+}
@@ -45,8 +45,9 @@ var findingGetCmd = &cobra.Command{
 // list
 
 var (
-	findingListAssetID string
-	findingListLimit   int
+	findingListAssetID     string
+	findingListLimit       int
+	findingListMinSeverity string
 )
 
 var findingListCmd = &cobra.Command{
@@ -63,13 +64,41 @@ var findingListCmd = &cobra.Command{
 			opts = &xbow.ListOptions{Limit: findingListLimit}
 		}
 
-		return printFindingList(client.Findings.AllByAsset(context.Background(), findingListAssetID, opts))
+		findings := client.Findings.AllByAsset(context.Background(), findingListAssetID, opts)
+		if findingListMinSeverity != "" {
+			threshold, err := xbow.ParseFindingSeverity(findingListMinSeverity)
+			if err != nil {
+				return err
+			}
+			findings = findingsAtLeast(findings, threshold)
+		}
+
+		return printFindingList(findings)
 	},
 }
 
+// findingsAtLeast filters seq down to findings at least as severe as threshold.
+func findingsAtLeast(seq iter.Seq2[xbow.FindingListItem, error], threshold xbow.FindingSeverity) iter.Seq2[xbow.FindingListItem, error] {
+	return func(yield func(xbow.FindingListItem, error) bool) {
+		for f, err := range seq {
+			if err != nil {
+				yield(f, err)
+				return
+			}
+			if !f.Severity.AtLeast(threshold) {
+				continue
+			}
+			if !yield(f, nil) {
+				return
+			}
+		}
+	}
+}
+
 func init() {
 	findingListCmd.Flags().StringVar(&findingListAssetID, "asset-id", "", "Asset ID to list findings for (required)")
 	findingListCmd.Flags().IntVar(&findingListLimit, "limit", 0, "Maximum number of results per page")
+	findingListCmd.Flags().StringVar(&findingListMinSeverity, "min-severity", "", "Only show findings at least this severe: informational, low, medium, high, critical")
 	_ = findingListCmd.MarkFlagRequired("asset-id")
 }
 
@@ -98,8 +127,8 @@ var findingVerifyFixCmd = &cobra.Command{
 // output helpers
 
 func printFinding(f *xbow.Finding) error {
-	if outputFormat == "json" {
-		return printJSON(f)
+	if structuredOutput() {
+		return printStructured(f)
 	}
 
 	w := newTabWriter()
@@ -118,7 +147,7 @@ func printFinding(f *xbow.Finding) error {
 }
 
 func printFindingList(iter iter.Seq2[xbow.FindingListItem, error]) error {
-	if outputFormat == "json" {
+	if structuredOutput() {
 		var items []xbow.FindingListItem
 		for f, err := range iter {
 			if err != nil {
@@ -126,15 +155,29 @@ func printFindingList(iter iter.Seq2[xbow.FindingListItem, error]) error {
 			}
 			items = append(items, f)
 		}
-		return printJSON(items)
+		return printStructured(items)
+	}
+
+	if csvOutput() {
+		return printCSV(iter, []string{"id", "name", "severity", "state", "created_at", "updated_at"}, func(f xbow.FindingListItem) []string {
+			return []string{f.ID, f.Name, string(f.Severity), string(f.State), csvTime(f.CreatedAt), csvTime(f.UpdatedAt)}
+		})
 	}
 
 	w := newTabWriter()
-	printRow(w, "ID", "NAME", "SEVERITY", "STATE", "CREATED")
+	if wideOutput() {
+		printRow(w, "ID", "NAME", "SEVERITY", "STATE", "CREATED", "UPDATED")
+	} else {
+		printRow(w, "ID", "NAME", "SEVERITY", "STATE", "CREATED")
+	}
 	for f, err := range iter {
 		if err != nil {
 			return err
 		}
+		if wideOutput() {
+			printRow(w, f.ID, f.Name, f.Severity, f.State, f.CreatedAt.Format("2006-01-02 15:04:05"), f.UpdatedAt.Format("2006-01-02 15:04:05"))
+			continue
+		}
 		printRow(w, f.ID, f.Name, f.Severity, f.State, f.CreatedAt.Format("2006-01-02"))
 	}
 	return w.Flush()
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testFindingJSON(id, severity string) string {
+	return fmt.Sprintf(`{"id":%q,"name":"Reflected XSS","severity":%q,"state":"open","summary":"Script injection in search","impact":"Session theft","mitigations":"Encode output","recipe":"Visit /search?q=<script>","evidence":"alert(1) executed","createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-02-03T04:05:06Z"}`, id, severity)
+}
+
+func TestFindingGet(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/findings/finding-1" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testFindingJSON("finding-1", "critical")))
+	})
+
+	out, err := runCLI(t, handler, "finding", "get", "finding-1")
+	if err != nil {
+		t.Fatalf("finding get error = %v", err)
+	}
+	for _, want := range []string{
+		"SEVERITY:     critical",
+		"STATE:        open",
+		"SUMMARY:      Script injection in search",
+		"IMPACT:       Session theft",
+		"MITIGATIONS:  Encode output",
+		"RECIPE:       Visit /search?q=<script>",
+		"EVIDENCE:     alert(1) executed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFindingList(t *testing.T) {
+	// Two pages linked by a cursor; the command should follow it.
+	var afters []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/assets/asset-1/findings" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		after := r.URL.Query().Get("after")
+		afters = append(afters, after)
+		if got := r.URL.Query().Get("limit"); got != "1" {
+			t.Errorf("limit = %q, want 1", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch after {
+		case "":
+			_, _ = fmt.Fprintf(w, `{"items":[%s],"nextCursor":"page-2"}`, testFindingJSON("finding-1", "high"))
+		case "page-2":
+			_, _ = fmt.Fprintf(w, `{"items":[%s]}`, testFindingJSON("finding-2", "low"))
+		default:
+			t.Errorf("unexpected cursor %q", after)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		afters = nil
+		out, err := runCLI(t, handler, "finding", "list", "--asset-id", "asset-1", "--limit", "1")
+		if err != nil {
+			t.Fatalf("finding list error = %v", err)
+		}
+		if len(afters) != 2 || afters[1] != "page-2" {
+			t.Errorf("requests used cursors %q, want [\"\" \"page-2\"]", afters)
+		}
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("got %d lines, want header and 2 rows:\n%s", len(lines), out)
+		}
+		if !strings.Contains(lines[1], "finding-1") || !strings.Contains(lines[2], "finding-2") {
+			t.Errorf("rows out of order:\n%s", out)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		afters = nil
+		out, err := runCLI(t, handler, "finding", "list", "--asset-id", "asset-1", "--limit", "1", "-o", "json")
+		if err != nil {
+			t.Fatalf("finding list error = %v", err)
+		}
+		for _, want := range []string{`"id": "finding-1"`, `"id": "finding-2"`, `"severity": "low"`} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+	})
+	t.Run("min severity", func(t *testing.T) {
+		afters = nil
+		out, err := runCLI(t, handler, "finding", "list", "--asset-id", "asset-1", "--limit", "1", "--min-severity", "medium")
+		if err != nil {
+			t.Fatalf("finding list error = %v", err)
+		}
+		if !strings.Contains(out, "finding-1") || strings.Contains(out, "finding-2") {
+			t.Errorf("want only the high finding:\n%s", out)
+		}
+	})
+
+	t.Run("unknown min severity", func(t *testing.T) {
+		_, err := runCLI(t, handler, "finding", "list", "--asset-id", "asset-1", "--min-severity", "severe")
+		if err == nil || !strings.Contains(err.Error(), "critical") {
+			t.Errorf("error = %v, want one listing the valid severities", err)
+		}
+	})
+}
+
+func TestFindingVerifyFix(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/findings/finding-1/verify-fix" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"assess-1","name":"Verify fix","assetId":"asset-1","organizationId":"org-1","state":"waiting-for-capacity","progress":0,"attackCredits":10,"recentEvents":[],"createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}`))
+	})
+
+	out, err := runCLI(t, handler, "finding", "verify-fix", "finding-1")
+	if err != nil {
+		t.Fatalf("finding verify-fix error = %v", err)
+	}
+	for _, want := range []string{"assess-1", "waiting-for-capacity"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
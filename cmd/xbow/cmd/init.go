@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rsclarke/xbow"
+	"github.com/spf13/cobra"
+)
+
+var initType string
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Print an example request file",
+	Long: `Print an example JSON request file to start from instead of a blank file.
+
+Types:
+  asset     UpdateAssetRequest, for "asset update --from-file"
+  webhook   CreateWebhookRequest
+
+Example:
+  xbow init --type asset > asset.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		example, ok := initExamples[initType]
+		if !ok {
+			return fmt.Errorf("unknown type %q (valid: %s)", initType, strings.Join(initTypes(), ", "))
+		}
+		return printJSON(example())
+	},
+}
+
+// initExamples builds the example request printed for each --type.
+var initExamples = map[string]func() any{
+	"asset":   exampleUpdateAssetRequest,
+	"webhook": exampleCreateWebhookRequest,
+}
+
+func initTypes() []string {
+	types := make([]string, 0, len(initExamples))
+	for t := range initExamples {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().StringVar(&initType, "type", "", "Example to print: asset, webhook (required)")
+	_ = initCmd.MarkFlagRequired("type")
+}
+
+// exampleUpdateAssetRequest returns an UpdateAssetRequest with every field
+// set to a representative value.
+func exampleUpdateAssetRequest() any {
+	sku := "standard"
+	email := "tester@example.com"
+	authURI := "otpauth://totp/Example:tester?secret=BASE32SECRET"
+	includeSubdomains := true
+
+	return &xbow.UpdateAssetRequest{
+		Name:                 "Example App",
+		StartURL:             "https://app.example.com",
+		MaxRequestsPerSecond: 10,
+		Sku:                  &sku,
+		ApprovedTimeWindows: &xbow.ApprovedTimeWindows{
+			Tz: "UTC",
+			Entries: []xbow.TimeWindowEntry{
+				{StartWeekday: 1, StartTime: "09:00", EndWeekday: 5, EndTime: "17:00"},
+			},
+		},
+		Credentials: []xbow.Credential{
+			{
+				Name:             "tester",
+				Type:             "basic",
+				Username:         "tester",
+				Password:         "change-me",
+				EmailAddress:     &email,
+				AuthenticatorURI: &authURI,
+			},
+		},
+		DNSBoundaryRules: []xbow.DNSBoundaryRule{
+			{
+				Action:            xbow.DNSBoundaryRuleActionAllowAttack,
+				Type:              "glob",
+				Filter:            "app.example.com",
+				IncludeSubdomains: &includeSubdomains,
+			},
+		},
+		Headers: map[string][]string{
+			"X-Scanner": {"xbow"},
+		},
+		HTTPBoundaryRules: []xbow.HTTPBoundaryRule{
+			{
+				Action:            xbow.HTTPBoundaryRuleActionDeny,
+				Type:              "prefix",
+				Filter:            "https://app.example.com/logout",
+				IncludeSubdomains: &includeSubdomains,
+			},
+		},
+	}
+}
+
+// exampleCreateWebhookRequest returns a CreateWebhookRequest with every field
+// set to a representative value.
+func exampleCreateWebhookRequest() any {
+	return &xbow.CreateWebhookRequest{
+		APIVersion: xbow.WebhookAPIVersion(xbow.APIVersion),
+		TargetURL:  "https://hooks.example.com/xbow",
+		Events: []xbow.WebhookEventType{
+			xbow.WebhookEventTypeAssessmentChanged,
+			xbow.WebhookEventTypeFindingChanged,
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rsclarke/xbow"
+)
+
+func TestInitExamples(t *testing.T) {
+	tests := []struct {
+		typ    string
+		target func() any
+	}{
+		{typ: "asset", target: func() any { return &xbow.UpdateAssetRequest{} }},
+		{typ: "webhook", target: func() any { return &xbow.CreateWebhookRequest{} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			data, err := json.Marshal(initExamples[tt.typ]())
+			if err != nil {
+				t.Fatalf("marshal example: %v", err)
+			}
+
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			got := tt.target()
+			if err := dec.Decode(got); err != nil {
+				t.Fatalf("decode example: %v", err)
+			}
+
+			// Every field of the request struct should appear in the example.
+			var keys map[string]json.RawMessage
+			if err := json.Unmarshal(data, &keys); err != nil {
+				t.Fatalf("decode keys: %v", err)
+			}
+			typ := reflect.TypeOf(got).Elem()
+			for i := range typ.NumField() {
+				name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+				if _, ok := keys[name]; !ok {
+					t.Errorf("example is missing field %q", name)
+				}
+			}
+		})
+	}
+}
+
+func TestInitAssetExampleLoadsFromFile(t *testing.T) {
+	data, err := json.MarshalIndent(exampleUpdateAssetRequest(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal example: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "asset.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("writing example: %v", err)
+	}
+
+	req, err := loadUpdateRequestFromFile(path)
+	if err != nil {
+		t.Fatalf("loadUpdateRequestFromFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(req, exampleUpdateAssetRequest()) {
+		t.Errorf("loaded request = %+v, want the example", req)
+	}
+}
@@ -71,8 +71,8 @@ var metaSigningKeysCmd = &cobra.Command{
 			return err
 		}
 
-		if outputFormat == "json" {
-			return printJSON(keys)
+		if structuredOutput() {
+			return printStructured(keys)
 		}
 
 		w := newTabWriter()
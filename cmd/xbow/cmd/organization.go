@@ -23,6 +23,8 @@ func init() {
 	organizationCmd.AddCommand(orgListCmd)
 	organizationCmd.AddCommand(orgCreateKeyCmd)
 	organizationCmd.AddCommand(orgRevokeKeyCmd)
+	organizationCmd.AddCommand(orgKeyCmd)
+	orgKeyCmd.AddCommand(orgKeyRotateCmd)
 }
 
 // get
@@ -81,7 +83,12 @@ Members are specified as repeatable flags:
 			req.ExternalID = &orgCreateExternalID
 		}
 
-		org, err := client.Organizations.Create(context.Background(), orgCreateIntegrationID, req)
+		integrationID, err := integrationIDFlag(orgCreateIntegrationID)
+		if err != nil {
+			return err
+		}
+
+		org, err := client.Organizations.Create(context.Background(), integrationID, req)
 		if err != nil {
 			return err
 		}
@@ -91,11 +98,10 @@ Members are specified as repeatable flags:
 }
 
 func init() {
-	orgCreateCmd.Flags().StringVar(&orgCreateIntegrationID, "integration-id", "", "Integration ID (required)")
+	orgCreateCmd.Flags().StringVar(&orgCreateIntegrationID, "integration-id", "", "Integration ID (required unless the config profile sets integrationId)")
 	orgCreateCmd.Flags().StringVar(&orgCreateName, "name", "", "Organization name (required)")
 	orgCreateCmd.Flags().StringVar(&orgCreateExternalID, "external-id", "", "External ID")
 	orgCreateCmd.Flags().StringArrayVar(&orgCreateMembers, "member", nil, `Member as "email=alice@example.com,name=Alice" (repeatable, at least one required)`)
-	_ = orgCreateCmd.MarkFlagRequired("integration-id")
 	_ = orgCreateCmd.MarkFlagRequired("name")
 	_ = orgCreateCmd.MarkFlagRequired("member")
 }
@@ -160,14 +166,18 @@ var orgListCmd = &cobra.Command{
 			opts = &xbow.ListOptions{Limit: orgListLimit}
 		}
 
-		return printOrganizationList(client.Organizations.AllByIntegration(context.Background(), orgListIntegrationID, opts))
+		integrationID, err := integrationIDFlag(orgListIntegrationID)
+		if err != nil {
+			return err
+		}
+
+		return printOrganizationList(client.Organizations.AllByIntegration(context.Background(), integrationID, opts))
 	},
 }
 
 func init() {
-	orgListCmd.Flags().StringVar(&orgListIntegrationID, "integration-id", "", "Integration ID (required)")
+	orgListCmd.Flags().StringVar(&orgListIntegrationID, "integration-id", "", "Integration ID (required unless the config profile sets integrationId)")
 	orgListCmd.Flags().IntVar(&orgListLimit, "limit", 0, "Maximum number of results per page")
-	_ = orgListCmd.MarkFlagRequired("integration-id")
 }
 
 // create-key
@@ -225,6 +235,74 @@ var orgRevokeKeyCmd = &cobra.Command{
 	},
 }
 
+// key
+
+var orgKeyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Manage organization API keys",
+	Long: `Manage organization API keys.
+
+The API has no endpoint for listing an organization's keys, so commands
+that replace a key take its ID explicitly.`,
+}
+
+// key rotate
+
+var (
+	orgKeyRotateName        string
+	orgKeyRotateRevoke      string
+	orgKeyRotateExpiresDays int
+)
+
+var orgKeyRotateCmd = &cobra.Command{
+	Use:   "rotate <org-id>",
+	Short: "Replace an organization API key with a new one",
+	Long: `Create a new API key for an organization, print it, then revoke the
+key given by --revoke.
+
+The new key is shown only once. If revoking the old key fails, the new
+key has still been created: the error names both key IDs so the old key
+can be revoked with "xbow organization revoke-key".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		req := &xbow.CreateKeyRequest{
+			Name: orgKeyRotateName,
+		}
+		if cmd.Flags().Changed("expires-in-days") {
+			req.ExpiresInDays = &orgKeyRotateExpiresDays
+		}
+
+		ctx := context.Background()
+		key, err := client.Organizations.CreateKey(ctx, args[0], req)
+		if err != nil {
+			return fmt.Errorf("creating new key: %w", err)
+		}
+
+		// Print before revoking so the new key is not lost if that fails.
+		if err := printAPIKey(key); err != nil {
+			return err
+		}
+
+		if err := client.Organizations.RevokeKey(ctx, orgKeyRotateRevoke); err != nil {
+			return fmt.Errorf("new key %s was created, but revoking old key %s failed: %w", key.ID, orgKeyRotateRevoke, err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	orgKeyRotateCmd.Flags().StringVar(&orgKeyRotateName, "name", "", "Name for the new key (required)")
+	orgKeyRotateCmd.Flags().StringVar(&orgKeyRotateRevoke, "revoke", "", "ID of the key to revoke (required)")
+	orgKeyRotateCmd.Flags().IntVar(&orgKeyRotateExpiresDays, "expires-in-days", 0, "Number of days until the new key expires")
+	_ = orgKeyRotateCmd.MarkFlagRequired("name")
+	_ = orgKeyRotateCmd.MarkFlagRequired("revoke")
+}
+
 // parsing helpers
 
 func parseMembers(raw []string) ([]xbow.OrganizationMember, error) {
@@ -252,8 +330,8 @@ func parseMembers(raw []string) ([]xbow.OrganizationMember, error) {
 // output helpers
 
 func printOrganization(o *xbow.Organization) error {
-	if outputFormat == "json" {
-		return printJSON(o)
+	if structuredOutput() {
+		return printStructured(o)
 	}
 
 	w := newTabWriter()
@@ -269,7 +347,7 @@ func printOrganization(o *xbow.Organization) error {
 }
 
 func printOrganizationList(iter iter.Seq2[xbow.OrganizationListItem, error]) error {
-	if outputFormat == "json" {
+	if structuredOutput() {
 		var items []xbow.OrganizationListItem
 		for o, err := range iter {
 			if err != nil {
@@ -277,23 +355,37 @@ func printOrganizationList(iter iter.Seq2[xbow.OrganizationListItem, error]) err
 			}
 			items = append(items, o)
 		}
-		return printJSON(items)
+		return printStructured(items)
+	}
+
+	if csvOutput() {
+		return printCSV(iter, []string{"id", "name", "external_id", "state", "created_at", "updated_at"}, func(o xbow.OrganizationListItem) []string {
+			return []string{o.ID, o.Name, stringOrEmpty(o.ExternalID), string(o.State), csvTime(o.CreatedAt), csvTime(o.UpdatedAt)}
+		})
 	}
 
 	w := newTabWriter()
-	printRow(w, "ID", "NAME", "STATE", "CREATED")
+	if wideOutput() {
+		printRow(w, "ID", "NAME", "EXTERNAL ID", "STATE", "CREATED", "UPDATED")
+	} else {
+		printRow(w, "ID", "NAME", "STATE", "CREATED")
+	}
 	for o, err := range iter {
 		if err != nil {
 			return err
 		}
+		if wideOutput() {
+			printRow(w, o.ID, o.Name, stringOrEmpty(o.ExternalID), o.State, o.CreatedAt.Format("2006-01-02 15:04:05"), o.UpdatedAt.Format("2006-01-02 15:04:05"))
+			continue
+		}
 		printRow(w, o.ID, o.Name, o.State, o.CreatedAt.Format("2006-01-02"))
 	}
 	return w.Flush()
 }
 
 func printAPIKey(k *xbow.OrganizationAPIKey) error {
-	if outputFormat == "json" {
-		return printJSON(k)
+	if structuredOutput() {
+		return printStructured(k)
 	}
 
 	w := newTabWriter()
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/rsclarke/xbow"
@@ -61,3 +63,58 @@ func TestParseMembers(t *testing.T) {
 		})
 	}
 }
+
+func TestOrganizationKeyRotate(t *testing.T) {
+	const newKeyJSON = `{"id":"key-new","name":"CI","key":"secret-new","expiresAt":null,"createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}`
+
+	handler := func(revokeStatus int) (http.Handler, *[]string) {
+		var calls []string
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/api/v1/organizations/org-1/keys":
+				_, _ = w.Write([]byte(newKeyJSON))
+			case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/keys/key-old":
+				w.WriteHeader(revokeStatus)
+				if revokeStatus != http.StatusNoContent {
+					_, _ = w.Write([]byte(`{"code":"ERR_INTERNAL","error":"Internal Server Error","message":"boom"}`))
+				}
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}), &calls
+	}
+
+	args := []string{"--integration-key", "test-integration-key", "organization", "key", "rotate", "org-1", "--name", "CI", "--revoke", "key-old"}
+
+	t.Run("creates then revokes", func(t *testing.T) {
+		h, calls := handler(http.StatusNoContent)
+		out, err := runCLI(t, h, args...)
+		if err != nil {
+			t.Fatalf("rotate error = %v", err)
+		}
+		want := []string{"POST /api/v1/organizations/org-1/keys", "DELETE /api/v1/keys/key-old"}
+		if !reflect.DeepEqual(*calls, want) {
+			t.Errorf("calls = %v, want %v", *calls, want)
+		}
+		if !strings.Contains(out, "secret-new") {
+			t.Errorf("output missing new key:\n%s", out)
+		}
+	})
+
+	t.Run("revoke failure keeps new key", func(t *testing.T) {
+		h, _ := handler(http.StatusInternalServerError)
+		out, err := runCLI(t, h, args...)
+		if err == nil {
+			t.Fatal("expected error when revoke fails")
+		}
+		if !strings.Contains(err.Error(), "key-new") || !strings.Contains(err.Error(), "key-old") {
+			t.Errorf("error %q should name both key IDs", err)
+		}
+		if !strings.Contains(out, "secret-new") {
+			t.Errorf("new key not printed before failure:\n%s", out)
+		}
+	})
+}
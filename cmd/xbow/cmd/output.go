@@ -2,21 +2,83 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 	"text/tabwriter"
+	"time"
 )
 
+// Values accepted by --output.
+const (
+	formatTable = "table"
+	formatWide  = "wide"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatCSV   = "csv"
+)
+
+// stdout is where command output is written. Tests replace it.
+var stdout io.Writer = os.Stdout
+
+// validateOutputFormat reports an error if --output is not a known format.
+func validateOutputFormat() error {
+	switch outputFormat {
+	case formatTable, formatWide, formatJSON, formatYAML, formatCSV:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q (valid: %s, %s, %s, %s, %s)", outputFormat, formatTable, formatWide, formatJSON, formatYAML, formatCSV)
+}
+
+// structuredOutput reports whether --output selects a machine-readable
+// format rather than a table.
+func structuredOutput() bool {
+	return outputFormat == formatJSON || outputFormat == formatYAML
+}
+
+// wideOutput reports whether tables should include their extra columns.
+func wideOutput() bool {
+	return outputFormat == formatWide
+}
+
+// csvOutput reports whether list commands should write CSV. Commands that
+// print a single resource show a table instead.
+func csvOutput() bool {
+	return outputFormat == formatCSV
+}
+
+// printStructured writes v in the machine-readable format selected by
+// --output.
+func printStructured(v any) error {
+	switch outputFormat {
+	case formatJSON:
+		return printJSON(v)
+	case formatYAML:
+		return printYAML(v)
+	}
+	return validateOutputFormat()
+}
+
 func printJSON(v any) error {
-	enc := json.NewEncoder(os.Stdout)
+	enc := json.NewEncoder(stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(v)
 }
 
+func printYAML(v any) error {
+	data, err := marshalYAML(v)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(data)
+	return err
+}
+
 func newTabWriter() *tabwriter.Writer {
-	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	return tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
 }
 
 func printRow(w io.Writer, cols ...any) {
@@ -28,3 +90,36 @@ func printRow(w io.Writer, cols ...any) {
 	}
 	_, _ = fmt.Fprintln(w)
 }
+
+// printCSV writes a header row followed by one row per item of seq, quoted
+// per RFC 4180.
+func printCSV[T any](seq iter.Seq2[T, error], header []string, row func(T) []string) error {
+	w := csv.NewWriter(stdout)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for item, err := range seq {
+		if err != nil {
+			w.Flush()
+			return err
+		}
+		if err := w.Write(row(item)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvTime formats t for CSV output as an ISO 8601 timestamp in UTC.
+func csvTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// stringOrEmpty returns *p, or "" if p is nil.
+func stringOrEmpty(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
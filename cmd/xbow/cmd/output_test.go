@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"bytes"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rsclarke/xbow"
+)
+
+// captureOutput runs fn with --output set to format and returns what it
+// wrote to stdout.
+func captureOutput(t *testing.T, format string, fn func() error) string {
+	t.Helper()
+	var buf bytes.Buffer
+	oldStdout, oldFormat := stdout, outputFormat
+	stdout, outputFormat = &buf, format
+	t.Cleanup(func() { stdout, outputFormat = oldStdout, oldFormat })
+
+	if err := fn(); err != nil {
+		t.Fatalf("print with --output %s: %v", format, err)
+	}
+	return buf.String()
+}
+
+func seqOf[T any](items ...T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+var (
+	testCreated = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	testUpdated = time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+)
+
+func TestPrintAssetFormats(t *testing.T) {
+	startURL := "https://app.example.com"
+	asset := &xbow.Asset{
+		ID:             "asset-1",
+		Name:           "Example App",
+		OrganizationID: "org-1",
+		Lifecycle:      xbow.AssetLifecycleActive,
+		Sku:            "standard",
+		StartURL:       &startURL,
+		CreatedAt:      testCreated,
+		UpdatedAt:      testUpdated,
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{formatTable, []string{"ID:", "asset-1", "ORGANIZATION ID:", "org-1", "START URL:"}},
+		{formatWide, []string{"ID:", "asset-1", "UPDATED:", "2026-02-03 04:05:06"}},
+		{formatJSON, []string{`"id": "asset-1"`, `"organizationId": "org-1"`, `"startUrl": "https://app.example.com"`}},
+		{formatYAML, []string{"id: asset-1\n", "organizationId: org-1\n", "startUrl: https://app.example.com\n", "credentials: null\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got := captureOutput(t, tt.format, func() error { return printAsset(asset) })
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintAssetListFormats(t *testing.T) {
+	items := []xbow.AssetListItem{
+		{ID: "asset-1", Name: "One", Lifecycle: xbow.AssetLifecycleActive, CreatedAt: testCreated, UpdatedAt: testUpdated},
+		{ID: "asset-2", Name: "Two", Lifecycle: xbow.AssetLifecycleArchived, CreatedAt: testCreated, UpdatedAt: testUpdated},
+	}
+
+	tests := []struct {
+		format  string
+		want    []string
+		notWant []string
+	}{
+		{formatTable, []string{"ID", "LIFECYCLE", "asset-2", "2026-01-02"}, []string{"UPDATED"}},
+		{formatWide, []string{"UPDATED", "2026-01-02 03:04:05", "2026-02-03 04:05:06"}, nil},
+		{formatJSON, []string{`"id": "asset-1"`, `"lifecycle": "archived"`}, nil},
+		{formatYAML, []string{"- id: asset-1\n  name: One\n", "  lifecycle: archived\n"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got := captureOutput(t, tt.format, func() error { return printAssetList(seqOf(items...)) })
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %q:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("output unexpectedly contains %q:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintFindingFormats(t *testing.T) {
+	finding := &xbow.Finding{
+		ID:        "finding-1",
+		Name:      "Reflected XSS",
+		Severity:  xbow.FindingSeverityHigh,
+		State:     xbow.FindingStateOpen,
+		Summary:   "Script injection in search",
+		Evidence:  "GET /search?q=<script>\nHTTP/1.1 200 OK\n",
+		CreatedAt: testCreated,
+		UpdatedAt: testUpdated,
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{formatTable, []string{"SEVERITY:", "high", "EVIDENCE:"}},
+		{formatWide, []string{"SEVERITY:", "high"}},
+		{formatJSON, []string{`"severity": "high"`, `"evidence": "GET /search?q=\u003cscript\u003e\nHTTP/1.1 200 OK\n"`}},
+		{formatYAML, []string{"severity: high\n", "evidence: |\n  GET /search?q=<script>\n  HTTP/1.1 200 OK\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got := captureOutput(t, tt.format, func() error { return printFinding(finding) })
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintFindingListFormats(t *testing.T) {
+	items := []xbow.FindingListItem{
+		{ID: "finding-1", Name: "Reflected XSS", Severity: xbow.FindingSeverityHigh, State: xbow.FindingStateOpen, CreatedAt: testCreated, UpdatedAt: testUpdated},
+	}
+
+	tests := []struct {
+		format  string
+		want    []string
+		notWant []string
+	}{
+		{formatTable, []string{"SEVERITY", "finding-1", "2026-01-02"}, []string{"UPDATED"}},
+		{formatWide, []string{"UPDATED", "2026-02-03 04:05:06"}, nil},
+		{formatJSON, []string{`"severity": "high"`}, nil},
+		{formatYAML, []string{"- id: finding-1\n", "  severity: high\n", "  createdAt: \"2026-01-02T03:04:05Z\"\n"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got := captureOutput(t, tt.format, func() error { return printFindingList(seqOf(items...)) })
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %q:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("output unexpectedly contains %q:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintListCSV(t *testing.T) {
+	externalID := "ext-1"
+	tests := []struct {
+		name  string
+		print func() error
+		want  string
+	}{
+		{
+			name: "assets",
+			print: func() error {
+				return printAssetList(seqOf(xbow.AssetListItem{ID: "asset-1", Name: "Shop, EU", Lifecycle: xbow.AssetLifecycleActive, CreatedAt: testCreated, UpdatedAt: testUpdated}))
+			},
+			want: "id,name,lifecycle,created_at,updated_at\n" +
+				"asset-1,\"Shop, EU\",active,2026-01-02T03:04:05Z,2026-02-03T04:05:06Z\n",
+		},
+		{
+			name: "assessments",
+			print: func() error {
+				return printAssessmentList(seqOf(xbow.AssessmentListItem{ID: "assess-1", Name: "Nightly, full", State: xbow.AssessmentStateRunning, Progress: 0.25, CreatedAt: testCreated, UpdatedAt: testUpdated}))
+			},
+			want: "id,name,state,progress,created_at,updated_at\n" +
+				"assess-1,\"Nightly, full\",running,0.25,2026-01-02T03:04:05Z,2026-02-03T04:05:06Z\n",
+		},
+		{
+			name: "findings",
+			print: func() error {
+				return printFindingList(seqOf(xbow.FindingListItem{ID: "finding-1", Name: `XSS in "search", reflected`, Severity: xbow.FindingSeverityHigh, State: xbow.FindingStateOpen, CreatedAt: testCreated, UpdatedAt: testUpdated}))
+			},
+			want: "id,name,severity,state,created_at,updated_at\n" +
+				"finding-1,\"XSS in \"\"search\"\", reflected\",high,open,2026-01-02T03:04:05Z,2026-02-03T04:05:06Z\n",
+		},
+		{
+			name: "organizations",
+			print: func() error {
+				return printOrganizationList(seqOf(xbow.OrganizationListItem{ID: "org-1", Name: "Acme, Inc.", ExternalID: &externalID, State: xbow.OrganizationStateActive, CreatedAt: testCreated, UpdatedAt: testUpdated}))
+			},
+			want: "id,name,external_id,state,created_at,updated_at\n" +
+				"org-1,\"Acme, Inc.\",ext-1,active,2026-01-02T03:04:05Z,2026-02-03T04:05:06Z\n",
+		},
+		{
+			name: "webhooks",
+			print: func() error {
+				return printWebhookList(seqOf(xbow.WebhookListItem{ID: "wh-1", TargetURL: "https://hooks.example.com/a,b", APIVersion: "2026-02-01", Events: []xbow.WebhookEventType{"ping", "finding.changed"}, CreatedAt: testCreated, UpdatedAt: testUpdated}))
+			},
+			want: "id,target_url,api_version,events,created_at,updated_at\n" +
+				"wh-1,\"https://hooks.example.com/a,b\",2026-02-01,ping finding.changed,2026-01-02T03:04:05Z,2026-02-03T04:05:06Z\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := captureOutput(t, formatCSV, tt.print)
+			if got != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	old := outputFormat
+	t.Cleanup(func() { outputFormat = old })
+
+	for _, format := range []string{formatTable, formatWide, formatJSON, formatYAML, formatCSV} {
+		outputFormat = format
+		if err := validateOutputFormat(); err != nil {
+			t.Errorf("validateOutputFormat(%q) error = %v", format, err)
+		}
+	}
+
+	outputFormat = "xml"
+	err := validateOutputFormat()
+	if err == nil || !strings.Contains(err.Error(), `unknown output format "xml"`) {
+		t.Errorf("validateOutputFormat(xml) error = %v, want unknown format", err)
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{
+			name: "nested object and list",
+			in: map[string]any{
+				"name":  "app",
+				"tags":  []string{"a", "b"},
+				"owner": map[string]any{"email": "x@example.com"},
+			},
+			want: "name: app\nowner:\n  email: x@example.com\ntags:\n- a\n- b\n",
+		},
+		{
+			name: "list of objects",
+			in:   []map[string]any{{"id": "1", "ok": true}, {"id": "2", "ok": false}},
+			want: "- id: \"1\"\n  ok: true\n- id: \"2\"\n  ok: false\n",
+		},
+		{
+			name: "ambiguous strings are quoted",
+			in: map[string]string{
+				"a": "true", "b": "123", "c": "", "d": "- x", "e": "key: value", "f": " padded",
+			},
+			want: "a: \"true\"\nb: \"123\"\nc: \"\"\nd: \"- x\"\ne: \"key: value\"\nf: \" padded\"\n",
+		},
+		{
+			name: "multi-line strings",
+			in:   map[string]string{"clip": "one\ntwo\n", "strip": "one\ntwo", "keep": "one\n\n"},
+			want: "clip: |\n  one\n  two\nkeep: \"one\\n\\n\"\nstrip: |-\n  one\n  two\n",
+		},
+		{
+			name: "empty collections and null",
+			in:   map[string]any{"list": []int{}, "map": map[string]int{}, "nil": nil, "n": 1.5},
+			want: "list: []\nmap: {}\n\"n\": 1.5\nnil: null\n",
+		},
+		{
+			name: "nested lists",
+			in:   [][]int{{1, 2}, {}},
+			want: "- - 1\n  - 2\n- []\n",
+		},
+		{
+			name: "scalar document",
+			in:   "hello",
+			want: "hello\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalYAML(tt.in)
+			if err != nil {
+				t.Fatalf("marshalYAML() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("marshalYAML() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rsclarke/xbow"
+	"github.com/spf13/cobra"
+)
+
+// pingResult is what `xbow ping` reports.
+type pingResult struct {
+	BaseURL string `json:"baseUrl"`
+	Auth    string `json:"auth"`
+}
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check connectivity and authentication",
+	Long:  "Make a cheap authenticated request to confirm the API is reachable and the organization key is accepted.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		pingErr := client.Ping(context.Background())
+		result := pingResult{BaseURL: client.BaseURL(), Auth: pingStatus(pingErr)}
+
+		if structuredOutput() {
+			if err := printStructured(result); err != nil {
+				return err
+			}
+		} else {
+			w := newTabWriter()
+			printRow(w, "BASE URL:", result.BaseURL)
+			printRow(w, "AUTH:", result.Auth)
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+		return pingErr
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+}
+
+// pingStatus summarises the outcome of Client.Ping.
+func pingStatus(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, xbow.ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, xbow.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, xbow.ErrMissingOrgKey):
+		return "no organization key"
+	}
+	return "failed"
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rsclarke/xbow"
+)
+
+func TestPing(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		out, err := runCLI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"publicKey":"a2V5"}]`))
+		}), "ping")
+		if err != nil {
+			t.Fatalf("ping error = %v", err)
+		}
+		if !strings.Contains(out, "BASE URL:") || !strings.Contains(out, "http://127.0.0.1") {
+			t.Errorf("output missing base URL:\n%s", out)
+		}
+		if !strings.Contains(out, "AUTH:") || !strings.Contains(out, "ok") {
+			t.Errorf("output missing auth status:\n%s", out)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		out, err := runCLI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"ERR_UNAUTHORIZED","error":"Unauthorized","message":"invalid API key"}`))
+		}), "ping", "-o", "json")
+		if !errors.Is(err, xbow.ErrUnauthorized) {
+			t.Fatalf("error = %v, want ErrUnauthorized", err)
+		}
+		if !strings.Contains(out, `"auth": "unauthorized"`) {
+			t.Errorf("output missing auth status:\n%s", out)
+		}
+	})
+}
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/rsclarke/xbow"
 	"github.com/spf13/cobra"
@@ -20,6 +22,7 @@ var reportCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(reportCmd)
 	reportCmd.AddCommand(reportGetCmd)
+	reportCmd.AddCommand(reportDownloadCmd)
 	reportCmd.AddCommand(reportSummaryCmd)
 	reportCmd.AddCommand(reportListCmd)
 }
@@ -40,7 +43,7 @@ var reportGetCmd = &cobra.Command{
 
 		data, err := client.Reports.Get(context.Background(), args[0])
 		if err != nil {
-			return err
+			return reportError(args[0], err)
 		}
 
 		if reportGetOutputFile != "" {
@@ -59,6 +62,67 @@ func init() {
 	reportGetCmd.Flags().StringVarP(&reportGetOutputFile, "output-file", "f", "", "Path to write the PDF file")
 }
 
+// download
+
+var reportDownloadOutputFile string
+
+var reportDownloadCmd = &cobra.Command{
+	Use:   "download <report-id>",
+	Short: "Stream a report PDF to a file",
+	Long: `Stream a report's PDF to the file given by --output-file, or to stdout
+with "--output-file -". The report is written as it arrives rather than
+being held in memory.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if reportDownloadOutputFile == "" {
+			return fmt.Errorf(`--output-file is required for binary output (use "-" for stdout)`)
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		return downloadReport(context.Background(), client, args[0], reportDownloadOutputFile)
+	},
+}
+
+func init() {
+	reportDownloadCmd.Flags().StringVarP(&reportDownloadOutputFile, "output-file", "f", "", `Path to write the PDF file, or "-" for stdout (required)`)
+}
+
+// downloadReport streams report id to path, or to stdout when path is "-".
+// A partially written file is removed if the download fails.
+func downloadReport(ctx context.Context, client *xbow.Client, id, path string) error {
+	if path == "-" {
+		_, err := client.Reports.Download(ctx, id, stdout)
+		return reportError(id, err)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644) //nolint:gosec // PDF output file; 0644 is intentional
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	_, err = client.Reports.Download(ctx, id, f)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("writing file: %w", closeErr)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return reportError(id, err)
+	}
+	return nil
+}
+
+// reportError replaces a not-found API error with a message naming the
+// report.
+func reportError(id string, err error) error {
+	if errors.Is(err, xbow.ErrNotFound) {
+		return fmt.Errorf("report %s not found", id)
+	}
+	return err
+}
+
 // summary
 
 var reportSummaryOutputFile string
@@ -75,11 +139,11 @@ var reportSummaryCmd = &cobra.Command{
 
 		summary, err := client.Reports.GetSummary(context.Background(), args[0])
 		if err != nil {
-			return err
+			return reportError(args[0], err)
 		}
 
-		if outputFormat == "json" {
-			return printJSON(summary)
+		if structuredOutput() {
+			return printStructured(summary)
 		}
 
 		if reportSummaryOutputFile != "" {
@@ -132,7 +196,7 @@ func init() {
 // output helpers
 
 func printReportList(iter iter.Seq2[xbow.ReportListItem, error]) error {
-	if outputFormat == "json" {
+	if structuredOutput() {
 		var items []xbow.ReportListItem
 		for r, err := range iter {
 			if err != nil {
@@ -140,7 +204,13 @@ func printReportList(iter iter.Seq2[xbow.ReportListItem, error]) error {
 			}
 			items = append(items, r)
 		}
-		return printJSON(items)
+		return printStructured(items)
+	}
+
+	if csvOutput() {
+		return printCSV(iter, []string{"id", "version", "created_at"}, func(r xbow.ReportListItem) []string {
+			return []string{r.ID, strconv.FormatInt(r.Version, 10), csvTime(r.CreatedAt)}
+		})
 	}
 
 	w := newTabWriter()
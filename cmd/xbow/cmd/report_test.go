@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPDF = "%PDF-1.7\nreport body\n%%EOF\n"
+
+func reportHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/reports/report-1":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte(testPDF))
+		case "/api/v1/reports/missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","message":"Report not found"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestReportDownload(t *testing.T) {
+	t.Run("writes file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.pdf")
+		out, err := runCLI(t, reportHandler(t), "report", "download", "report-1", "--output-file", path)
+		if err != nil {
+			t.Fatalf("download error = %v", err)
+		}
+		if out != "" {
+			t.Errorf("stdout = %q, want nothing", out)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("reading output: %v", err)
+		}
+		if string(data) != testPDF {
+			t.Errorf("file = %q, want %q", data, testPDF)
+		}
+	})
+
+	t.Run("dash writes stdout", func(t *testing.T) {
+		out, err := runCLI(t, reportHandler(t), "report", "download", "report-1", "-f", "-")
+		if err != nil {
+			t.Fatalf("download error = %v", err)
+		}
+		if out != testPDF {
+			t.Errorf("stdout = %q, want %q", out, testPDF)
+		}
+	})
+
+	t.Run("requires output file", func(t *testing.T) {
+		_, err := runCLI(t, reportHandler(t), "report", "download", "report-1")
+		if err == nil || !strings.Contains(err.Error(), "--output-file is required") {
+			t.Errorf("error = %v, want --output-file is required", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.pdf")
+		_, err := runCLI(t, reportHandler(t), "report", "download", "missing", "-f", path)
+		if err == nil || err.Error() != "report missing not found" {
+			t.Errorf("error = %v, want %q", err, "report missing not found")
+		}
+		if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+			t.Errorf("partial file left behind: %v", statErr)
+		}
+	})
+}
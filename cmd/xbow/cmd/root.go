@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/rsclarke/xbow"
 	"github.com/spf13/cobra"
@@ -11,7 +18,10 @@ import (
 var (
 	orgKey         string
 	integrationKey string
+	baseURL        string
 	outputFormat   string
+	dryRun         bool
+	apiVersion     string
 )
 
 var rootCmd = &cobra.Command{
@@ -19,42 +29,133 @@ var rootCmd = &cobra.Command{
 	Short:   "XBOW CLI - Interact with the XBOW API",
 	Long:    `A command-line interface for interacting with the XBOW security assessment platform.`,
 	Version: version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateOutputFormat()
+	},
 }
 
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	rootCmd.SilenceErrors = true
+	err := execute()
+	if err != nil {
+		_, _ = fmt.Fprintln(rootCmd.ErrOrStderr(), "Error:", explainError(err))
+	}
+	return err
+}
+
+// execute runs the root command. A request held back by --dry-run is
+// printed, and counts as success.
+func execute() error {
+	err := rootCmd.Execute()
+	var dryRunErr *xbow.DryRunError
+	if errors.As(err, &dryRunErr) {
+		return printDryRun(dryRunErr)
+	}
+	return err
+}
+
+// printDryRun writes the request in dryRunErr to stdout: the request line,
+// then its headers in order with credentials redacted, then its body with
+// JSON indented.
+func printDryRun(dryRunErr *xbow.DryRunError) error {
+	req := dryRunErr.Request
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", req.Method, req.URL)
+	for _, name := range slices.Sorted(maps.Keys(req.Header)) {
+		for _, v := range req.Header[name] {
+			if name == "Authorization" {
+				v = "REDACTED"
+			}
+			fmt.Fprintf(&b, "%s: %s\n", name, v)
+		}
+	}
+	if len(dryRunErr.Body) > 0 {
+		b.WriteString("\n")
+		var indented bytes.Buffer
+		if json.Indent(&indented, dryRunErr.Body, "", "  ") == nil {
+			b.Write(indented.Bytes())
+		} else {
+			b.Write(dryRunErr.Body)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(stdout, b.String())
+	return err
+}
+
+// explainError rewrites errors about a missing API key to say which flag or
+// environment variable supplies it.
+func explainError(err error) error {
+	switch {
+	case errors.Is(err, xbow.ErrMissingOrgKey):
+		return errors.New("this command needs an organization key: use --org-key or set XBOW_ORG_KEY (or orgKey in a config profile)")
+	case errors.Is(err, xbow.ErrMissingIntegrationKey):
+		return errors.New("this command needs an integration key: use --integration-key or set XBOW_INTEGRATION_KEY (or integrationKey in a config profile)")
+	case errors.Is(err, xbow.ErrMissingAnyKey):
+		return errors.New("this command needs an API key: use --org-key/--integration-key or set XBOW_ORG_KEY/XBOW_INTEGRATION_KEY (or keys in a config profile)")
+	}
+	return err
 }
 
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("xbow version %s\napi version %s\n", version, xbow.APIVersion))
 	rootCmd.PersistentFlags().StringVar(&orgKey, "org-key", "", "Organization API key (or set XBOW_ORG_KEY env var)")
 	rootCmd.PersistentFlags().StringVar(&integrationKey, "integration-key", "", "Integration API key (or set XBOW_INTEGRATION_KEY env var)")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, json")
+	rootCmd.PersistentFlags().StringVar(&baseURL, "base-url", "", "API base URL (or set XBOW_BASE_URL env var)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, wide, json, yaml, csv")
+	rootCmd.PersistentFlags().StringVar(&apiVersion, "api-version", "", "API version to request, such as next (or set XBOW_API_VERSION env var)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the request a command would send instead of sending it (reads are still sent)")
 }
 
+// extraClientOptions are appended to the options newClient builds. Tests
+// use it to point the CLI at an httptest server.
+var extraClientOptions []xbow.ClientOption
+
+// newClient builds a client from flags, environment variables and the active
+// config profile, in that order of precedence.
 func newClient() (*xbow.Client, error) {
+	profile, err := loadProfile()
+	if err != nil {
+		return nil, err
+	}
+
 	opts := []xbow.ClientOption{}
 
-	key := orgKey
-	if key == "" {
-		key = os.Getenv("XBOW_ORG_KEY")
-	}
+	key := setting(orgKey, "XBOW_ORG_KEY", profile.OrgKey)
 	if key != "" {
 		opts = append(opts, xbow.WithOrganizationKey(key))
 	}
 
-	intKey := integrationKey
-	if intKey == "" {
-		intKey = os.Getenv("XBOW_INTEGRATION_KEY")
-	}
+	intKey := setting(integrationKey, "XBOW_INTEGRATION_KEY", profile.IntegrationKey)
 	if intKey != "" {
 		opts = append(opts, xbow.WithIntegrationKey(intKey))
 	}
 
 	if key == "" && intKey == "" {
-		return nil, fmt.Errorf("API key required: use --org-key/--integration-key or set XBOW_ORG_KEY/XBOW_INTEGRATION_KEY")
+		return nil, fmt.Errorf("API key required: use --org-key/--integration-key or set XBOW_ORG_KEY/XBOW_INTEGRATION_KEY (or keys in a config profile)")
+	}
+
+	if u := setting(baseURL, "XBOW_BASE_URL", profile.BaseURL); u != "" {
+		opts = append(opts, xbow.WithBaseURL(u))
+	}
+
+	if v := flagOrEnv(apiVersion, "XBOW_API_VERSION"); v != "" {
+		opts = append(opts, xbow.WithAPIVersion(v))
+	}
+
+	if dryRun {
+		opts = append(opts, xbow.WithDryRun())
 	}
 
-	return xbow.NewClient(opts...)
+	return xbow.NewClient(append(opts, extraClientOptions...)...)
+}
+
+// flagOrEnv returns the flag value if set, otherwise the named environment
+// variable.
+func flagOrEnv(flag, env string) string {
+	if flag != "" {
+		return flag
+	}
+	return os.Getenv(env)
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rsclarke/xbow"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// runCLI executes the root command with args against handler and returns
+// what it wrote to stdout.
+func runCLI(t *testing.T, handler http.Handler, args ...string) (string, error) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	oldOpts := extraClientOptions
+	extraClientOptions = []xbow.ClientOption{xbow.WithBaseURL(srv.URL)}
+	t.Cleanup(func() { extraClientOptions = oldOpts })
+
+	return executeCLI(t, append([]string{"--org-key", "test-key"}, args...)...)
+}
+
+// executeCLI executes the root command with args and returns what it wrote
+// to stdout. Flags are reset afterwards.
+func executeCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	oldStdout := stdout
+	stdout = &buf
+	t.Cleanup(func() {
+		stdout = oldStdout
+		resetFlags(rootCmd)
+		rootCmd.SetArgs(nil)
+	})
+
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	err := execute()
+	return buf.String(), err
+}
+
+// resetFlags restores every flag under cmd to its default so one test's
+// flags do not leak into the next.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// authServer records the Authorization header of each request and answers
+// with a webhook.
+func authServer(t *testing.T, got *[]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got = append(*got, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"wh-1","apiVersion":"2026-02-01","targetUrl":"https://hooks.example.com","events":["ping"],"createdAt":"2026-01-01T00:00:00Z","updatedAt":"2026-01-01T00:00:00Z"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewClientFromEnvironment(t *testing.T) {
+	unreachable := "http://127.0.0.1:1"
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		wantAuth string
+		wantErr  string
+	}{
+		{
+			name:     "org key and base URL from env",
+			env:      map[string]string{"XBOW_ORG_KEY": "env-org-key", "XBOW_BASE_URL": "SERVER"},
+			wantAuth: "Bearer env-org-key",
+		},
+		{
+			name:     "flag overrides env key",
+			env:      map[string]string{"XBOW_ORG_KEY": "env-org-key", "XBOW_BASE_URL": "SERVER"},
+			args:     []string{"--org-key", "flag-org-key"},
+			wantAuth: "Bearer flag-org-key",
+		},
+		{
+			name:     "flag overrides env base URL",
+			env:      map[string]string{"XBOW_ORG_KEY": "env-org-key", "XBOW_BASE_URL": unreachable},
+			args:     []string{"--base-url", "SERVER"},
+			wantAuth: "Bearer env-org-key",
+		},
+		{
+			name:    "no key",
+			env:     map[string]string{"XBOW_BASE_URL": "SERVER"},
+			wantErr: "API key required",
+		},
+		{
+			name:    "integration key only for an org-key command",
+			env:     map[string]string{"XBOW_INTEGRATION_KEY": "env-int-key", "XBOW_BASE_URL": "SERVER"},
+			wantErr: "this command needs an organization key: use --org-key or set XBOW_ORG_KEY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var auths []string
+			srv := authServer(t, &auths)
+
+			for _, name := range []string{"XBOW_ORG_KEY", "XBOW_INTEGRATION_KEY", "XBOW_BASE_URL"} {
+				t.Setenv(name, strings.ReplaceAll(tt.env[name], "SERVER", srv.URL))
+			}
+			args := append([]string{}, tt.args...)
+			for i, a := range args {
+				args[i] = strings.ReplaceAll(a, "SERVER", srv.URL)
+			}
+
+			_, err := executeCLI(t, append(args, "webhook", "get", "wh-1")...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(explainError(err).Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if len(auths) != 0 {
+					t.Errorf("made %d requests, want none", len(auths))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(auths) != 1 || auths[0] != tt.wantAuth {
+				t.Errorf("Authorization = %q, want [%q]", auths, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	out, err := runCLI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}), "--dry-run", "asset", "create", "--org-id", "org-1", "--name", "My App", "--sku", "standard")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"POST http://127.0.0.1:",
+		"/api/v1/organizations/org-1/assets\n",
+		"Authorization: REDACTED\n",
+		"Content-Type: application/json\n",
+		"\n{\n  \"name\": \"My App\",\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "test-key") {
+		t.Errorf("output leaks the API key:\n%s", out)
+	}
+}
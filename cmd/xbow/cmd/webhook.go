@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"strconv"
 	"strings"
 
 	"github.com/rsclarke/xbow"
@@ -66,12 +67,17 @@ var webhookCreateCmd = &cobra.Command{
 			return err
 		}
 
-		events := make([]xbow.WebhookEventType, 0, len(webhookCreateEvents))
-		for _, e := range webhookCreateEvents {
-			events = append(events, xbow.WebhookEventType(e))
+		events, err := parseWebhookEvents(webhookCreateEvents)
+		if err != nil {
+			return err
+		}
+
+		orgID, err := orgIDFlag(webhookCreateOrgID)
+		if err != nil {
+			return err
 		}
 
-		webhook, err := client.Webhooks.Create(context.Background(), webhookCreateOrgID, &xbow.CreateWebhookRequest{
+		webhook, err := client.Webhooks.Create(context.Background(), orgID, &xbow.CreateWebhookRequest{
 			APIVersion: xbow.WebhookAPIVersion(webhookCreateAPIVersion),
 			TargetURL:  webhookCreateTargetURL,
 			Events:     events,
@@ -85,15 +91,28 @@ var webhookCreateCmd = &cobra.Command{
 }
 
 func init() {
-	webhookCreateCmd.Flags().StringVar(&webhookCreateOrgID, "org-id", "", "Organization ID (required)")
+	webhookCreateCmd.Flags().StringVar(&webhookCreateOrgID, "org-id", "", "Organization ID (required unless the config profile sets orgId)")
 	webhookCreateCmd.Flags().StringVar(&webhookCreateTargetURL, "target-url", "", "Webhook target URL (required)")
 	webhookCreateCmd.Flags().StringVar(&webhookCreateAPIVersion, "api-version", "2026-02-01", "Webhook API version")
 	webhookCreateCmd.Flags().StringArrayVar(&webhookCreateEvents, "event", nil, `Event type to subscribe to (repeatable, e.g. "assessment.changed")`)
-	_ = webhookCreateCmd.MarkFlagRequired("org-id")
 	_ = webhookCreateCmd.MarkFlagRequired("target-url")
 	_ = webhookCreateCmd.MarkFlagRequired("event")
 }
 
+// parseWebhookEvents converts --event flag values to event types, rejecting
+// unknown ones before any request is made.
+func parseWebhookEvents(values []string) ([]xbow.WebhookEventType, error) {
+	events := make([]xbow.WebhookEventType, 0, len(values))
+	for _, v := range values {
+		e, err := xbow.ParseWebhookEventType(v)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
 // update
 
 var (
@@ -121,9 +140,9 @@ var webhookUpdateCmd = &cobra.Command{
 			req.APIVersion = &v
 		}
 		if cmd.Flags().Changed("event") {
-			events := make([]xbow.WebhookEventType, 0, len(webhookUpdateEvents))
-			for _, e := range webhookUpdateEvents {
-				events = append(events, xbow.WebhookEventType(e))
+			events, err := parseWebhookEvents(webhookUpdateEvents)
+			if err != nil {
+				return err
 			}
 			req.Events = events
 		}
@@ -180,6 +199,7 @@ var webhookPingCmd = &cobra.Command{
 var (
 	webhookListOrgID string
 	webhookListLimit int
+	webhookListEvent string
 )
 
 var webhookListCmd = &cobra.Command{
@@ -196,14 +216,47 @@ var webhookListCmd = &cobra.Command{
 			opts = &xbow.ListOptions{Limit: webhookListLimit}
 		}
 
-		return printWebhookList(client.Webhooks.AllByOrganization(context.Background(), webhookListOrgID, opts))
+		orgID, err := orgIDFlag(webhookListOrgID)
+		if err != nil {
+			return err
+		}
+
+		webhooks := client.Webhooks.AllByOrganization(context.Background(), orgID, opts)
+		if webhookListEvent != "" {
+			event, err := xbow.ParseWebhookEventType(webhookListEvent)
+			if err != nil {
+				return err
+			}
+			webhooks = webhooksReceivingEvent(webhooks, event)
+		}
+
+		return printWebhookList(webhooks)
 	},
 }
 
+// webhooksReceivingEvent filters seq down to webhooks that receive event,
+// including wildcard subscriptions.
+func webhooksReceivingEvent(seq iter.Seq2[xbow.WebhookListItem, error], event xbow.WebhookEventType) iter.Seq2[xbow.WebhookListItem, error] {
+	return func(yield func(xbow.WebhookListItem, error) bool) {
+		for wh, err := range seq {
+			if err != nil {
+				yield(wh, err)
+				return
+			}
+			if len(xbow.FilterWebhooksByEvent([]xbow.WebhookListItem{wh}, event)) == 0 {
+				continue
+			}
+			if !yield(wh, nil) {
+				return
+			}
+		}
+	}
+}
+
 func init() {
-	webhookListCmd.Flags().StringVar(&webhookListOrgID, "org-id", "", "Organization ID (required)")
+	webhookListCmd.Flags().StringVar(&webhookListOrgID, "org-id", "", "Organization ID (required unless the config profile sets orgId)")
 	webhookListCmd.Flags().IntVar(&webhookListLimit, "limit", 0, "Maximum number of results per page")
-	_ = webhookListCmd.MarkFlagRequired("org-id")
+	webhookListCmd.Flags().StringVar(&webhookListEvent, "event", "", `Only show webhooks receiving this event type (includes "*" subscriptions)`)
 }
 
 // deliveries
@@ -236,8 +289,8 @@ func init() {
 // output helpers
 
 func printWebhook(wh *xbow.Webhook) error {
-	if outputFormat == "json" {
-		return printJSON(wh)
+	if structuredOutput() {
+		return printStructured(wh)
 	}
 
 	w := newTabWriter()
@@ -251,7 +304,7 @@ func printWebhook(wh *xbow.Webhook) error {
 }
 
 func printWebhookList(iter iter.Seq2[xbow.WebhookListItem, error]) error {
-	if outputFormat == "json" {
+	if structuredOutput() {
 		var items []xbow.WebhookListItem
 		for wh, err := range iter {
 			if err != nil {
@@ -259,22 +312,37 @@ func printWebhookList(iter iter.Seq2[xbow.WebhookListItem, error]) error {
 			}
 			items = append(items, wh)
 		}
-		return printJSON(items)
+		return printStructured(items)
+	}
+
+	if csvOutput() {
+		return printCSV(iter, []string{"id", "target_url", "api_version", "events", "created_at", "updated_at"}, func(wh xbow.WebhookListItem) []string {
+			return []string{wh.ID, wh.TargetURL, string(wh.APIVersion), strings.Join(webhookEventStrings(wh.Events), " "), csvTime(wh.CreatedAt), csvTime(wh.UpdatedAt)}
+		})
 	}
 
 	w := newTabWriter()
-	printRow(w, "ID", "TARGET URL", "API VERSION", "EVENTS", "CREATED")
+	if wideOutput() {
+		printRow(w, "ID", "TARGET URL", "API VERSION", "EVENTS", "CREATED", "UPDATED")
+	} else {
+		printRow(w, "ID", "TARGET URL", "API VERSION", "EVENTS", "CREATED")
+	}
 	for wh, err := range iter {
 		if err != nil {
 			return err
 		}
-		printRow(w, wh.ID, wh.TargetURL, wh.APIVersion, strings.Join(webhookEventStrings(wh.Events), ", "), wh.CreatedAt.Format("2006-01-02"))
+		events := strings.Join(webhookEventStrings(wh.Events), ", ")
+		if wideOutput() {
+			printRow(w, wh.ID, wh.TargetURL, wh.APIVersion, events, wh.CreatedAt.Format("2006-01-02 15:04:05"), wh.UpdatedAt.Format("2006-01-02 15:04:05"))
+			continue
+		}
+		printRow(w, wh.ID, wh.TargetURL, wh.APIVersion, events, wh.CreatedAt.Format("2006-01-02"))
 	}
 	return w.Flush()
 }
 
 func printDeliveryList(iter iter.Seq2[xbow.WebhookDelivery, error]) error {
-	if outputFormat == "json" {
+	if structuredOutput() {
 		var items []xbow.WebhookDelivery
 		for d, err := range iter {
 			if err != nil {
@@ -282,7 +350,13 @@ func printDeliveryList(iter iter.Seq2[xbow.WebhookDelivery, error]) error {
 			}
 			items = append(items, d)
 		}
-		return printJSON(items)
+		return printStructured(items)
+	}
+
+	if csvOutput() {
+		return printCSV(iter, []string{"sent_at", "success", "status"}, func(d xbow.WebhookDelivery) []string {
+			return []string{csvTime(d.SentAt), strconv.FormatBool(d.Success), strconv.Itoa(d.Response.Status)}
+		})
 	}
 
 	w := newTabWriter()
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rsclarke/xbow"
+)
+
+func TestParseWebhookEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []xbow.WebhookEventType
+		wantErr bool
+	}{
+		{
+			name:   "known events",
+			values: []string{"assessment.changed", "*"},
+			want:   []xbow.WebhookEventType{xbow.WebhookEventTypeAssessmentChanged, xbow.WebhookEventTypeAll},
+		},
+		{
+			name:   "none",
+			values: nil,
+			want:   []xbow.WebhookEventType{},
+		},
+		{
+			name:    "typo rejected",
+			values:  []string{"finding.changed", "asset.change"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWebhookEvents(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
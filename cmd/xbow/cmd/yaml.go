@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// marshalYAML renders v as YAML. It goes through encoding/json so the output
+// uses the same field names, omitempty rules and key order as --output json.
+func marshalYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := decodeYAMLNode(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	writeYAMLDocument(&b, node)
+	return b.Bytes(), nil
+}
+
+// yamlField is one key of a JSON object, kept in document order.
+type yamlField struct {
+	key   string
+	value any
+}
+
+// decodeYAMLNode reads the next JSON value from dec. Objects become
+// []yamlField, arrays []any, and scalars string, json.Number, bool or nil.
+func decodeYAMLNode(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		fields := []yamlField{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, yamlField{key: keyTok.(string), value: value})
+		}
+		_, err = dec.Token()
+		return fields, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			item, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err = dec.Token()
+		return items, err
+	}
+	return tok, nil
+}
+
+func writeYAMLDocument(b *bytes.Buffer, node any) {
+	if isYAMLBlock(node) {
+		writeYAMLBlock(b, node, 0)
+		return
+	}
+	b.WriteString(yamlScalar(node, 0))
+	b.WriteByte('\n')
+}
+
+// isYAMLBlock reports whether node is written as an indented block rather
+// than inline after its key.
+func isYAMLBlock(node any) bool {
+	switch n := node.(type) {
+	case []yamlField:
+		return len(n) > 0
+	case []any:
+		return len(n) > 0
+	}
+	return false
+}
+
+// writeYAMLBlock writes a non-empty object or array starting on a new line
+// at the given indent.
+func writeYAMLBlock(b *bytes.Buffer, node any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch n := node.(type) {
+	case []yamlField:
+		for _, f := range n {
+			b.WriteString(pad + yamlKey(f.key) + ":")
+			switch {
+			case !isYAMLBlock(f.value):
+				b.WriteString(" " + yamlScalar(f.value, indent+2) + "\n")
+			case isYAMLSequence(f.value):
+				b.WriteByte('\n')
+				writeYAMLBlock(b, f.value, indent)
+			default:
+				b.WriteByte('\n')
+				writeYAMLBlock(b, f.value, indent+2)
+			}
+		}
+	case []any:
+		for _, item := range n {
+			if !isYAMLBlock(item) {
+				b.WriteString(pad + "- " + yamlScalar(item, indent+2) + "\n")
+				continue
+			}
+			// Render the item as if indented under the dash, then put the
+			// dash in place of its first line's indentation.
+			var child bytes.Buffer
+			writeYAMLBlock(&child, item, indent+2)
+			b.WriteString(pad + "- ")
+			b.Write(child.Bytes()[indent+2:])
+		}
+	}
+}
+
+func isYAMLSequence(node any) bool {
+	_, ok := node.([]any)
+	return ok
+}
+
+// yamlScalar renders a scalar or empty collection. indent is the
+// indentation for the lines of a multi-line string.
+func yamlScalar(node any, indent int) string {
+	switch n := node.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(n)
+	case json.Number:
+		return n.String()
+	case string:
+		return yamlString(n, indent)
+	case []yamlField:
+		return "{}"
+	case []any:
+		return "[]"
+	}
+	return fmt.Sprint(node)
+}
+
+// yamlString renders s plain when YAML would read it back as the same
+// string, as a literal block when it spans lines, and double-quoted
+// otherwise.
+func yamlString(s string, indent int) string {
+	if isPlainYAML(s) {
+		return s
+	}
+	if block, ok := yamlLiteralBlock(s, indent); ok {
+		return block
+	}
+	return strconv.Quote(s)
+}
+
+// yamlKey renders an object key, which is never written as a block.
+func yamlKey(s string) string {
+	if isPlainYAML(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+func isPlainYAML(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`0123456789+.") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f || !strconv.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlLiteralBlock renders a multi-line string as a "|" block. It declines
+// strings a literal block cannot represent exactly.
+func yamlLiteralBlock(s string, indent int) (string, bool) {
+	if !strings.Contains(s, "\n") || strings.TrimSpace(s) == "" || strings.HasSuffix(s, "\n\n") {
+		return "", false
+	}
+	// The block's indentation is taken from its first non-empty line, so
+	// that line cannot itself start with a space.
+	if strings.HasPrefix(strings.TrimLeft(s, "\n"), " ") {
+		return "", false
+	}
+	for _, r := range s {
+		if r != '\n' && r != '\t' && (r < ' ' || r == 0x7f || !strconv.IsPrint(r)) {
+			return "", false
+		}
+	}
+
+	header := "|-"
+	body := s
+	if strings.HasSuffix(s, "\n") {
+		header = "|"
+		body = strings.TrimSuffix(s, "\n")
+	}
+
+	pad := strings.Repeat(" ", indent)
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return header + "\n" + strings.Join(lines, "\n"), true
+}
@@ -0,0 +1,76 @@
+package xbow
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// WithMaxConcurrentRequests caps the client's requests in flight at n.
+// Further requests wait, until a slot is free or their context is done. A
+// request holds its slot until its response body is closed, so a streaming
+// report download holds one for as long as it reads. Zero or less means no
+// cap, the default.
+//
+// The cap applies to each attempt, so a call waiting out a retry backoff
+// holds no slot. It sits below WithRateLimiter: a request first waits for
+// the rate limiter, then for a slot.
+//
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("key"),
+//	    xbow.WithMaxConcurrentRequests(8),
+//	)
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.maxConcurrent = n
+	}
+}
+
+// WithTransportMaxConcurrentRequests adds a cap on requests in flight to the
+// transport stack, shared by every client using the transport. It behaves
+// like WithMaxConcurrentRequests on the client.
+func WithTransportMaxConcurrentRequests(n int) TransportOption {
+	return func(c *transportConfig) {
+		c.maxConcurrent = n
+	}
+}
+
+// concurrencyTransport admits at most cap(slots) requests to base at once.
+type concurrencyTransport struct {
+	base  http.RoundTripper
+	slots chan struct{}
+}
+
+func newConcurrencyTransport(base http.RoundTripper, n int) *concurrencyTransport {
+	return &concurrencyTransport{base: base, slots: make(chan struct{}, n)}
+}
+
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := sync.OnceFunc(func() { <-t.slots })
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseOnCloseBody frees a request's slot when its response body is
+// closed.
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
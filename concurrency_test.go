@@ -0,0 +1,80 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConcurrentRequests(t *testing.T) {
+	const limit = 3
+	var inFlight, peak atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testAssetJSON))
+	}), WithMaxConcurrentRequests(limit))
+
+	var wg sync.WaitGroup
+	for range 4 * limit {
+		wg.Go(func() {
+			if _, err := client.Assets.Get(context.Background(), "asset-123"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > limit {
+		t.Errorf("peak concurrent requests = %d, want at most %d", got, limit)
+	}
+	if got := peak.Load(); got < 2 {
+		t.Errorf("peak concurrent requests = %d; requests did not overlap", got)
+	}
+}
+
+func TestWithMaxConcurrentRequests_WaitHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testAssetJSON))
+	}), WithMaxConcurrentRequests(1))
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		if _, err := client.Assets.Get(context.Background(), "asset-1"); err != nil {
+			t.Errorf("first request: unexpected error: %v", err)
+		}
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Assets.Get(ctx, "asset-2"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting request error = %v, want context.DeadlineExceeded", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	// The slot was freed when the first response was read.
+	go func() { <-started }()
+	if _, err := client.Assets.Get(context.Background(), "asset-3"); err != nil {
+		t.Errorf("after release: unexpected error: %v", err)
+	}
+}
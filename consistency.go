@@ -0,0 +1,65 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ConsistencyRetry configures GetEventuallyConsistent. Zero fields use
+// defaults.
+type ConsistencyRetry struct {
+	// MaxAttempts is the total number of calls, including the first.
+	// Default is 5.
+	MaxAttempts int
+
+	// InitialBackoff is the delay after the first 404. It doubles after each
+	// further 404. Default is 200ms.
+	InitialBackoff time.Duration
+}
+
+func (r *ConsistencyRetry) defaults() {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = 5
+	}
+	if r.InitialBackoff <= 0 {
+		r.InitialBackoff = 200 * time.Millisecond
+	}
+}
+
+// GetEventuallyConsistent calls get, retrying while it fails with
+// ErrNotFound. Use it for reads that immediately follow a write, such as
+// fetching an asset's findings right after Assets.Create, where replication
+// lag can briefly hide the new resource. Other errors are returned at once.
+// retry may be nil to use the defaults.
+//
+// Example:
+//
+//	asset, err := xbow.GetEventuallyConsistent(ctx, nil, func(ctx context.Context) (*xbow.Asset, error) {
+//	    return client.Assets.Get(ctx, created.ID)
+//	})
+func GetEventuallyConsistent[T any](ctx context.Context, retry *ConsistencyRetry, get func(context.Context) (T, error)) (T, error) {
+	var cfg ConsistencyRetry
+	if retry != nil {
+		cfg = *retry
+	}
+	cfg.defaults()
+
+	backoff := cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		v, err := get(ctx)
+		if err == nil || !errors.Is(err, ErrNotFound) || attempt >= cfg.MaxAttempts {
+			return v, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
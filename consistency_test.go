@@ -0,0 +1,80 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetEventuallyConsistent(t *testing.T) {
+	fast := &ConsistencyRetry{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run("retries 404 then returns object", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"Asset not found"}`))
+				return
+			}
+			_, _ = w.Write([]byte(testAssetJSON))
+		}))
+
+		got, err := GetEventuallyConsistent(context.Background(), fast, func(ctx context.Context) (*Asset, error) {
+			return client.Assets.Get(ctx, "asset-123")
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.ID != "asset-123" {
+			t.Errorf("ID = %q, want 'asset-123'", got.ID)
+		}
+		if n := calls.Load(); n != 2 {
+			t.Errorf("calls = %d, want 2", n)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls atomic.Int32
+		_, err := GetEventuallyConsistent(context.Background(), fast, func(context.Context) (*Asset, error) {
+			calls.Add(1)
+			return nil, &Error{StatusCode: 404}
+		})
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+		if n := calls.Load(); n != 3 {
+			t.Errorf("calls = %d, want 3", n)
+		}
+	})
+
+	t.Run("returns other errors immediately", func(t *testing.T) {
+		var calls atomic.Int32
+		_, err := GetEventuallyConsistent(context.Background(), fast, func(context.Context) (*Asset, error) {
+			calls.Add(1)
+			return nil, &Error{StatusCode: 403}
+		})
+		if !errors.Is(err, ErrForbidden) {
+			t.Errorf("err = %v, want ErrForbidden", err)
+		}
+		if n := calls.Load(); n != 1 {
+			t.Errorf("calls = %d, want 1", n)
+		}
+	})
+
+	t.Run("honors context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := GetEventuallyConsistent(ctx, &ConsistencyRetry{InitialBackoff: time.Hour}, func(context.Context) (*Asset, error) {
+			return nil, &Error{StatusCode: 404}
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package xbow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// WithHTTPDebug writes every HTTP attempt made by the client to w as it
+// appears on the wire, using httputil.DumpRequestOut and DumpResponse.
+// Credentials in the redacted headers, such as Authorization, are replaced
+// with "REDACTED". Binary response bodies, such as report PDFs, are left out
+// so streaming downloads are unaffected. Writes to w are serialized.
+//
+// It is meant for diagnosing serialization problems; request and response
+// bodies may still contain sensitive data.
+//
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("key"),
+//	    xbow.WithHTTPDebug(os.Stderr),
+//	)
+func WithHTTPDebug(w io.Writer) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.debug = w
+	}
+}
+
+// debugTransport dumps each request passed to base, and its response, to w.
+type debugTransport struct {
+	base http.RoundTripper
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqDump, err := dumpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+
+	var respDump []byte
+	if err == nil {
+		if respDump, err = dumpResponse(resp); err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(reqDump)
+	_, _ = io.WriteString(t.w, "\n\n")
+	if err != nil {
+		_, _ = fmt.Fprintf(t.w, "error: %v\n\n", err)
+	} else {
+		_, _ = t.w.Write(respDump)
+		_, _ = io.WriteString(t.w, "\n\n")
+	}
+
+	return resp, err
+}
+
+// dumpRequest dumps req with its redacted headers replaced. The body is
+// buffered and put back so req can still be sent.
+func dumpRequest(req *http.Request) ([]byte, error) {
+	clone := req.Clone(req.Context())
+	for name := range clone.Header {
+		if slices.Contains(redactedHeaders, http.CanonicalHeaderKey(name)) {
+			clone.Header.Set(name, "REDACTED")
+		}
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return httputil.DumpRequestOut(clone, true)
+}
+
+// dumpResponse dumps resp, including its body only when the body is text.
+// A dumped body is buffered and put back for the caller.
+func dumpResponse(resp *http.Response) ([]byte, error) {
+	if textContent(resp.Header.Get("Content-Type")) {
+		return httputil.DumpResponse(resp, true)
+	}
+
+	dump, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return nil, err
+	}
+	return append(dump, fmt.Sprintf("[%s body omitted]", resp.Header.Get("Content-Type"))...), nil
+}
+
+// textContent reports whether a body of the given media type is worth
+// dumping: JSON, XML, form data or any text type. An empty type counts as
+// text, since error responses do not always set one.
+func textContent(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
@@ -0,0 +1,87 @@
+package xbow
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithHTTPDebug(t *testing.T) {
+	t.Run("dumps request and response", func(t *testing.T) {
+		var dump bytes.Buffer
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"markdown":"# Summary"}`))
+		}), WithHTTPDebug(&dump))
+
+		if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out := dump.String()
+		for _, want := range []string{
+			"GET /api/v1/reports/report-1/summary HTTP/1.1",
+			"Authorization: REDACTED",
+			"HTTP/1.1 200 OK",
+			`{"markdown":"# Summary"}`,
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("dump missing %q:\n%s", want, out)
+			}
+		}
+		if strings.Contains(out, "test-org-key") {
+			t.Errorf("dump leaks the API key:\n%s", out)
+		}
+	})
+
+	t.Run("request body still sent", func(t *testing.T) {
+		var dump bytes.Buffer
+		var got []byte
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(testAssetJSON))
+		}), WithHTTPDebug(&dump))
+
+		_, err := client.Assets.Update(context.Background(), "asset-123", &UpdateAssetRequest{
+			Name:                 "Renamed",
+			StartURL:             "https://example.com",
+			MaxRequestsPerSecond: 10,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Contains(got, []byte(`"name":"Renamed"`)) {
+			t.Errorf("server got body %s", got)
+		}
+		if !strings.Contains(dump.String(), `"name":"Renamed"`) {
+			t.Errorf("dump missing request body:\n%s", dump.String())
+		}
+	})
+
+	t.Run("binary body omitted and streamed", func(t *testing.T) {
+		var dump bytes.Buffer
+		pdf := "%PDF-1.7 binary"
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte(pdf))
+		}), WithHTTPDebug(&dump))
+
+		var buf bytes.Buffer
+		if _, err := client.Reports.Download(context.Background(), "report-1", &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != pdf {
+			t.Errorf("downloaded %q, want %q", buf.String(), pdf)
+		}
+		if strings.Contains(dump.String(), pdf) {
+			t.Errorf("dump includes binary body:\n%s", dump.String())
+		}
+		if !strings.Contains(dump.String(), "[application/pdf body omitted]") {
+			t.Errorf("dump missing omission note:\n%s", dump.String())
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package xbow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithDryRun stops the client from sending requests that change anything.
+// Each POST, PUT, PATCH or DELETE is built as usual, with its headers and
+// JSON body, and then returned unsent inside a *DryRunError so the caller
+// can inspect exactly what would have been sent. Reads are still sent, so
+// operations that read before writing, such as Assets.Patch, preview the
+// write they would make.
+//
+//	client, err := xbow.NewClient(xbow.WithOrganizationKey("key"), xbow.WithDryRun())
+//	...
+//	_, err = client.Assets.Create(ctx, orgID, &xbow.CreateAssetRequest{Name: "app", Sku: "standard"})
+//	var dryRun *xbow.DryRunError
+//	if errors.As(err, &dryRun) {
+//	    fmt.Println(dryRun.Request.Method, dryRun.Request.URL)
+//	    fmt.Println(string(dryRun.Body))
+//	}
+//
+// The captured request carries the client's credentials in its
+// Authorization header; take care when logging it.
+func WithDryRun() ClientOption {
+	return func(c *clientConfig) {
+		c.transport.dryRun = true
+	}
+}
+
+// DryRunError is returned, wrapped, in place of a response for every request
+// held back by WithDryRun. Use errors.As to retrieve it.
+type DryRunError struct {
+	// Request is the request as it would have been sent. Its body, if any,
+	// reads Body.
+	Request *http.Request
+
+	// Body is the request body, or nil when there is none.
+	Body []byte
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("xbow: dry run: %s %s not sent", e.Request.Method, e.Request.URL)
+}
+
+// dryRunTransport holds back mutating requests, passing reads to base.
+type dryRunTransport struct {
+	base http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	captured := req.Clone(req.Context())
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		captured.Body = io.NopCloser(bytes.NewReader(body))
+		captured.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	return nil, &DryRunError{Request: captured, Body: body}
+}
@@ -0,0 +1,81 @@
+package xbow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestWithDryRun(t *testing.T) {
+	t.Run("captures Assets.Create without sending", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}), WithDryRun())
+
+		asset, err := client.Assets.Create(context.Background(), "org-123", &CreateAssetRequest{Name: "My App", Sku: "standard"})
+		if asset != nil {
+			t.Errorf("asset = %+v, want nil", asset)
+		}
+		var dryRun *DryRunError
+		if !errors.As(err, &dryRun) {
+			t.Fatalf("error = %v, want *DryRunError", err)
+		}
+
+		req := dryRun.Request
+		if req.Method != http.MethodPost {
+			t.Errorf("Method = %q, want POST", req.Method)
+		}
+		if req.URL.Path != "/api/v1/organizations/org-123/assets" {
+			t.Errorf("Path = %q", req.URL.Path)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer test-org-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := req.Header.Get("X-XBOW-API-Version"); got == "" {
+			t.Error("X-XBOW-API-Version header missing")
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(dryRun.Body, &body); err != nil {
+			t.Fatalf("body %q: %v", dryRun.Body, err)
+		}
+		if body["name"] != "My App" || body["sku"] != "standard" {
+			t.Errorf("body = %v", body)
+		}
+
+		// The captured request's body reads the same bytes.
+		got, err := io.ReadAll(req.Body)
+		if err != nil || string(got) != string(dryRun.Body) {
+			t.Errorf("Request.Body = %q, %v; want %q", got, err, dryRun.Body)
+		}
+	})
+
+	t.Run("sends reads", func(t *testing.T) {
+		var methods []string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methods = append(methods, r.Method)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(testAssetJSON))
+		}), WithDryRun())
+
+		if _, err := client.Assets.Get(context.Background(), "asset-123"); err != nil {
+			t.Fatalf("Get: unexpected error: %v", err)
+		}
+
+		name := "Renamed"
+		_, err := client.Assets.Patch(context.Background(), "asset-123", &PatchAssetRequest{Name: &name})
+		var dryRun *DryRunError
+		if !errors.As(err, &dryRun) {
+			t.Fatalf("Patch error = %v, want *DryRunError", err)
+		}
+		if dryRun.Request.Method != http.MethodPut {
+			t.Errorf("Method = %q, want PUT", dryRun.Request.Method)
+		}
+		if len(methods) != 2 || methods[0] != http.MethodGet || methods[1] != http.MethodGet {
+			t.Errorf("server saw %v, want only the two GETs", methods)
+		}
+	})
+}
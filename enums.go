@@ -0,0 +1,411 @@
+package xbow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Each enum-like string type has a String method, an IsValid method that
+// reports whether the value is one the API documents, a <Type>Values
+// function listing those values, and a Parse<Type> function that rejects
+// anything else. They let callers (and the CLI) catch typos before a
+// request is sent.
+
+// parseEnum returns s as a T if it is one of values, or an
+// ERR_INVALID_PARAM error naming the valid values otherwise.
+func parseEnum[T ~string](kind, s string, values []T) (T, error) {
+	v := T(s)
+	if slices.Contains(values, v) {
+		return v, nil
+	}
+	return "", &Error{
+		Code:    "ERR_INVALID_PARAM",
+		Message: fmt.Sprintf("invalid %s %q (valid: %s)", kind, s, joinValues(values)),
+	}
+}
+
+// joinValues lists enum values for an error message.
+func joinValues[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, val := range values {
+		names[i] = string(val)
+	}
+	return strings.Join(names, ", ")
+}
+
+// AssetLifecycleValues returns every documented AssetLifecycle.
+func AssetLifecycleValues() []AssetLifecycle {
+	return []AssetLifecycle{
+		AssetLifecycleActive,
+		AssetLifecycleArchived,
+	}
+}
+
+// String returns the wire value of s.
+func (s AssetLifecycle) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented AssetLifecycle.
+func (s AssetLifecycle) IsValid() bool {
+	return slices.Contains(AssetLifecycleValues(), s)
+}
+
+// ParseAssetLifecycle converts s to an AssetLifecycle, returning an error if
+// it is not a documented value.
+func ParseAssetLifecycle(s string) (AssetLifecycle, error) {
+	return parseEnum("asset lifecycle", s, AssetLifecycleValues())
+}
+
+// DNSBoundaryRuleActionValues returns every documented DNSBoundaryRuleAction.
+func DNSBoundaryRuleActionValues() []DNSBoundaryRuleAction {
+	return []DNSBoundaryRuleAction{
+		DNSBoundaryRuleActionAllowAttack,
+		DNSBoundaryRuleActionAllowVisit,
+		DNSBoundaryRuleActionDeny,
+	}
+}
+
+// String returns the wire value of s.
+func (s DNSBoundaryRuleAction) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented DNSBoundaryRuleAction.
+func (s DNSBoundaryRuleAction) IsValid() bool {
+	return slices.Contains(DNSBoundaryRuleActionValues(), s)
+}
+
+// ParseDNSBoundaryRuleAction converts s to a DNSBoundaryRuleAction,
+// returning an error if it is not a documented value.
+func ParseDNSBoundaryRuleAction(s string) (DNSBoundaryRuleAction, error) {
+	return parseEnum("DNS boundary rule action", s, DNSBoundaryRuleActionValues())
+}
+
+// HTTPBoundaryRuleActionValues returns every documented HTTPBoundaryRuleAction.
+func HTTPBoundaryRuleActionValues() []HTTPBoundaryRuleAction {
+	return []HTTPBoundaryRuleAction{
+		HTTPBoundaryRuleActionAllowAttack,
+		HTTPBoundaryRuleActionAllowAuth,
+		HTTPBoundaryRuleActionAllowVisit,
+		HTTPBoundaryRuleActionDeny,
+	}
+}
+
+// String returns the wire value of s.
+func (s HTTPBoundaryRuleAction) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented HTTPBoundaryRuleAction.
+func (s HTTPBoundaryRuleAction) IsValid() bool {
+	return slices.Contains(HTTPBoundaryRuleActionValues(), s)
+}
+
+// ParseHTTPBoundaryRuleAction converts s to an HTTPBoundaryRuleAction,
+// returning an error if it is not a documented value.
+func ParseHTTPBoundaryRuleAction(s string) (HTTPBoundaryRuleAction, error) {
+	return parseEnum("HTTP boundary rule action", s, HTTPBoundaryRuleActionValues())
+}
+
+// AssetCheckStateValues returns every documented AssetCheckState.
+func AssetCheckStateValues() []AssetCheckState {
+	return []AssetCheckState{
+		AssetCheckStateUnchecked,
+		AssetCheckStateChecking,
+		AssetCheckStateValid,
+		AssetCheckStateInvalid,
+	}
+}
+
+// String returns the wire value of s.
+func (s AssetCheckState) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented AssetCheckState.
+func (s AssetCheckState) IsValid() bool {
+	return slices.Contains(AssetCheckStateValues(), s)
+}
+
+// ParseAssetCheckState converts s to an AssetCheckState, returning an error
+// if it is not a documented value.
+func ParseAssetCheckState(s string) (AssetCheckState, error) {
+	return parseEnum("asset check state", s, AssetCheckStateValues())
+}
+
+// AssessmentStateValues returns every documented AssessmentState.
+func AssessmentStateValues() []AssessmentState {
+	return []AssessmentState{
+		AssessmentStateWaitingForCapacity,
+		AssessmentStateRunning,
+		AssessmentStateSucceeded,
+		AssessmentStateReportReady,
+		AssessmentStateFailed,
+		AssessmentStateCancelling,
+		AssessmentStateCancelled,
+		AssessmentStatePaused,
+		AssessmentStateWaitingForTimeWindow,
+	}
+}
+
+// String returns the wire value of s.
+func (s AssessmentState) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented AssessmentState.
+func (s AssessmentState) IsValid() bool {
+	return slices.Contains(AssessmentStateValues(), s)
+}
+
+// ParseAssessmentState converts s to an AssessmentState, returning an error
+// if it is not a documented value.
+func ParseAssessmentState(s string) (AssessmentState, error) {
+	return parseEnum("assessment state", s, AssessmentStateValues())
+}
+
+// IsTerminal reports whether an assessment in state s has finished and
+// will not change state again: succeeded, failed, cancelled or
+// report-ready.
+func (s AssessmentState) IsTerminal() bool {
+	return slices.Contains(terminalAssessmentStates, s)
+}
+
+// IsActive reports whether an assessment in state s is still in progress:
+// running, waiting-for-capacity, waiting-for-time-window, paused or
+// cancelling. Undocumented states are neither active nor terminal.
+func (s AssessmentState) IsActive() bool {
+	switch s {
+	case AssessmentStateRunning,
+		AssessmentStateWaitingForCapacity,
+		AssessmentStateWaitingForTimeWindow,
+		AssessmentStatePaused,
+		AssessmentStateCancelling:
+		return true
+	}
+	return false
+}
+
+// AssessmentEventTypeValues returns every documented AssessmentEventType.
+func AssessmentEventTypeValues() []AssessmentEventType {
+	return []AssessmentEventType{
+		AssessmentEventTypePaused,
+		AssessmentEventTypeAutoPaused,
+		AssessmentEventTypeResumed,
+	}
+}
+
+// String returns the wire value of t.
+func (t AssessmentEventType) String() string {
+	return string(t)
+}
+
+// IsValid reports whether t is a documented AssessmentEventType.
+func (t AssessmentEventType) IsValid() bool {
+	return slices.Contains(AssessmentEventTypeValues(), t)
+}
+
+// ParseAssessmentEventType converts s to an AssessmentEventType, returning
+// an error if it is not a documented value.
+func ParseAssessmentEventType(s string) (AssessmentEventType, error) {
+	return parseEnum("assessment event type", s, AssessmentEventTypeValues())
+}
+
+// FindingSeverityValues returns every documented FindingSeverity.
+func FindingSeverityValues() []FindingSeverity {
+	return []FindingSeverity{
+		FindingSeverityCritical,
+		FindingSeverityHigh,
+		FindingSeverityMedium,
+		FindingSeverityLow,
+		FindingSeverityInformational,
+	}
+}
+
+// String returns the wire value of s.
+func (s FindingSeverity) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented FindingSeverity.
+func (s FindingSeverity) IsValid() bool {
+	return slices.Contains(FindingSeverityValues(), s)
+}
+
+// ParseFindingSeverity converts s to a FindingSeverity, returning an error
+// if it is not a documented value.
+func ParseFindingSeverity(s string) (FindingSeverity, error) {
+	return parseEnum("finding severity", s, FindingSeverityValues())
+}
+
+// Rank orders severities from informational (1) up to critical (5).
+// Unknown severities rank 0, below all documented ones.
+func (s FindingSeverity) Rank() int {
+	switch s {
+	case FindingSeverityInformational:
+		return 1
+	case FindingSeverityLow:
+		return 2
+	case FindingSeverityMedium:
+		return 3
+	case FindingSeverityHigh:
+		return 4
+	case FindingSeverityCritical:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether s is as severe as other or more, so
+// FindingSeverityHigh.AtLeast(FindingSeverityHigh) is true.
+func (s FindingSeverity) AtLeast(other FindingSeverity) bool {
+	return s.Rank() >= other.Rank()
+}
+
+// FindingStateValues returns every documented FindingState.
+func FindingStateValues() []FindingState {
+	return []FindingState{
+		FindingStateOpen,
+		FindingStateChallenged,
+		FindingStateConfirmed,
+		FindingStateInvalid,
+		FindingStateFixed,
+	}
+}
+
+// String returns the wire value of s.
+func (s FindingState) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented FindingState.
+func (s FindingState) IsValid() bool {
+	return slices.Contains(FindingStateValues(), s)
+}
+
+// ParseFindingState converts s to a FindingState, returning an error if it
+// is not a documented value.
+func ParseFindingState(s string) (FindingState, error) {
+	return parseEnum("finding state", s, FindingStateValues())
+}
+
+// IsResolved reports whether a finding in state s needs no further action:
+// fixed, or judged invalid. Open, challenged and confirmed findings are
+// unresolved.
+func (s FindingState) IsResolved() bool {
+	return s == FindingStateFixed || s == FindingStateInvalid
+}
+
+// OrganizationStateValues returns every documented OrganizationState.
+func OrganizationStateValues() []OrganizationState {
+	return []OrganizationState{
+		OrganizationStateActive,
+		OrganizationStateDisabled,
+	}
+}
+
+// String returns the wire value of s.
+func (s OrganizationState) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented OrganizationState.
+func (s OrganizationState) IsValid() bool {
+	return slices.Contains(OrganizationStateValues(), s)
+}
+
+// ParseOrganizationState converts s to an OrganizationState, returning an
+// error if it is not a documented value.
+func ParseOrganizationState(s string) (OrganizationState, error) {
+	return parseEnum("organization state", s, OrganizationStateValues())
+}
+
+// WebhookAPIVersionValues returns every documented WebhookAPIVersion.
+func WebhookAPIVersionValues() []WebhookAPIVersion {
+	return []WebhookAPIVersion{
+		WebhookAPIVersionN20251101,
+		WebhookAPIVersionN20260201,
+		WebhookAPIVersionNext,
+		WebhookAPIVersionUnstable,
+	}
+}
+
+// String returns the wire value of s.
+func (s WebhookAPIVersion) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented WebhookAPIVersion.
+func (s WebhookAPIVersion) IsValid() bool {
+	return slices.Contains(WebhookAPIVersionValues(), s)
+}
+
+// ParseWebhookAPIVersion converts s to a WebhookAPIVersion, returning an
+// error if it is not a documented value.
+func ParseWebhookAPIVersion(s string) (WebhookAPIVersion, error) {
+	return parseEnum("webhook API version", s, WebhookAPIVersionValues())
+}
+
+// WebhookEventTypeValues returns every documented WebhookEventType.
+func WebhookEventTypeValues() []WebhookEventType {
+	return []WebhookEventType{
+		WebhookEventTypePing,
+		WebhookEventTypeTargetChanged,
+		WebhookEventTypeAssetChanged,
+		WebhookEventTypeAssessmentChanged,
+		WebhookEventTypeFindingChanged,
+		WebhookEventTypeChallengeChanged,
+		WebhookEventTypeAll,
+	}
+}
+
+// String returns the wire value of s.
+func (s WebhookEventType) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a documented WebhookEventType.
+func (s WebhookEventType) IsValid() bool {
+	return slices.Contains(WebhookEventTypeValues(), s)
+}
+
+// IsKnown reports whether s is one of the event types this SDK defines a
+// constant for, which is every type the API documents. Webhooks fetched
+// from the API and events delivered to a receiver keep types the SDK does
+// not know yet, such as those a newer API version adds, rather than
+// dropping them; IsKnown tells those apart. Requests are stricter:
+// Webhooks.Create and Webhooks.Update reject unknown types.
+func (s WebhookEventType) IsKnown() bool {
+	return s.IsValid()
+}
+
+// ParseWebhookEventType converts s to a WebhookEventType, returning an error
+// if it is not a documented value.
+func ParseWebhookEventType(s string) (WebhookEventType, error) {
+	return parseEnum("webhook event type", s, WebhookEventTypeValues())
+}
+
+// Matches reports whether a subscription to s receives events of type
+// actual. The wildcard WebhookEventTypeAll matches every type; any other
+// type matches only itself.
+func (s WebhookEventType) Matches(actual WebhookEventType) bool {
+	return s == WebhookEventTypeAll || s == actual
+}
+
+// WebhookEventSet is a set of subscribed event types, such as
+// Webhook.Events, that may include the wildcard.
+//
+//	if xbow.WebhookEventSet(webhook.Events).Contains(xbow.WebhookEventTypeFindingChanged) {
+//	    // the webhook receives finding events
+//	}
+type WebhookEventSet []WebhookEventType
+
+// Contains reports whether any type in set matches actual.
+func (set WebhookEventSet) Contains(actual WebhookEventType) bool {
+	return slices.ContainsFunc(set, func(t WebhookEventType) bool {
+		return t.Matches(actual)
+	})
+}
@@ -0,0 +1,226 @@
+package xbow
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnumParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func(string) (string, error)
+		valid   func(string) bool
+		input   string
+		wantErr bool
+	}{
+		{"AssetLifecycle valid", wrapParse(ParseAssetLifecycle), isValid[AssetLifecycle], "archived", false},
+		{"AssetLifecycle invalid", wrapParse(ParseAssetLifecycle), isValid[AssetLifecycle], "deleted", true},
+		{"DNSBoundaryRuleAction valid", wrapParse(ParseDNSBoundaryRuleAction), isValid[DNSBoundaryRuleAction], "allow-visit", false},
+		{"DNSBoundaryRuleAction rejects allow-auth", wrapParse(ParseDNSBoundaryRuleAction), isValid[DNSBoundaryRuleAction], "allow-auth", true},
+		{"HTTPBoundaryRuleAction valid", wrapParse(ParseHTTPBoundaryRuleAction), isValid[HTTPBoundaryRuleAction], "allow-auth", false},
+		{"HTTPBoundaryRuleAction invalid", wrapParse(ParseHTTPBoundaryRuleAction), isValid[HTTPBoundaryRuleAction], "allow", true},
+		{"AssetCheckState valid", wrapParse(ParseAssetCheckState), isValid[AssetCheckState], "checking", false},
+		{"AssetCheckState invalid", wrapParse(ParseAssetCheckState), isValid[AssetCheckState], "ok", true},
+		{"AssessmentState valid", wrapParse(ParseAssessmentState), isValid[AssessmentState], "waiting-for-time-window", false},
+		{"AssessmentState invalid", wrapParse(ParseAssessmentState), isValid[AssessmentState], "done", true},
+		{"AssessmentEventType valid", wrapParse(ParseAssessmentEventType), isValid[AssessmentEventType], "auto-paused", false},
+		{"AssessmentEventType invalid", wrapParse(ParseAssessmentEventType), isValid[AssessmentEventType], "autopaused", true},
+		{"FindingSeverity valid", wrapParse(ParseFindingSeverity), isValid[FindingSeverity], "informational", false},
+		{"FindingSeverity wrong case", wrapParse(ParseFindingSeverity), isValid[FindingSeverity], "High", true},
+		{"FindingState valid", wrapParse(ParseFindingState), isValid[FindingState], "challenged", false},
+		{"FindingState invalid", wrapParse(ParseFindingState), isValid[FindingState], "closed", true},
+		{"OrganizationState valid", wrapParse(ParseOrganizationState), isValid[OrganizationState], "disabled", false},
+		{"OrganizationState invalid", wrapParse(ParseOrganizationState), isValid[OrganizationState], "suspended", true},
+		{"WebhookAPIVersion valid", wrapParse(ParseWebhookAPIVersion), isValid[WebhookAPIVersion], "2026-02-01", false},
+		{"WebhookAPIVersion invalid", wrapParse(ParseWebhookAPIVersion), isValid[WebhookAPIVersion], "2024-01-01", true},
+		{"WebhookEventType valid", wrapParse(ParseWebhookEventType), isValid[WebhookEventType], "assessment.changed", false},
+		{"WebhookEventType wildcard", wrapParse(ParseWebhookEventType), isValid[WebhookEventType], "*", false},
+		{"WebhookEventType typo", wrapParse(ParseWebhookEventType), isValid[WebhookEventType], "assesment.changed", true},
+		{"empty", wrapParse(ParseFindingSeverity), isValid[FindingSeverity], "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.input)
+			if tt.wantErr {
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_PARAM" {
+					t.Fatalf("error = %v, want ERR_INVALID_PARAM", err)
+				}
+				if got != "" {
+					t.Errorf("value = %q, want empty on error", got)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.input {
+					t.Errorf("value = %q, want %q", got, tt.input)
+				}
+			}
+			if v := tt.valid(tt.input); v == tt.wantErr {
+				t.Errorf("IsValid() = %v, want %v", v, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	// Every listed value must round-trip through Parse and String.
+	checkValues(t, AssetLifecycleValues(), ParseAssetLifecycle, 2)
+	checkValues(t, DNSBoundaryRuleActionValues(), ParseDNSBoundaryRuleAction, 3)
+	checkValues(t, HTTPBoundaryRuleActionValues(), ParseHTTPBoundaryRuleAction, 4)
+	checkValues(t, AssetCheckStateValues(), ParseAssetCheckState, 4)
+	checkValues(t, AssessmentStateValues(), ParseAssessmentState, 9)
+	checkValues(t, AssessmentEventTypeValues(), ParseAssessmentEventType, 3)
+	checkValues(t, FindingSeverityValues(), ParseFindingSeverity, 5)
+	checkValues(t, FindingStateValues(), ParseFindingState, 5)
+	checkValues(t, OrganizationStateValues(), ParseOrganizationState, 2)
+	checkValues(t, WebhookAPIVersionValues(), ParseWebhookAPIVersion, 4)
+	checkValues(t, WebhookEventTypeValues(), ParseWebhookEventType, 7)
+}
+
+func TestAssessmentStateClassification(t *testing.T) {
+	terminal := map[AssessmentState]bool{
+		AssessmentStateSucceeded:   true,
+		AssessmentStateFailed:      true,
+		AssessmentStateCancelled:   true,
+		AssessmentStateReportReady: true,
+	}
+	for _, s := range AssessmentStateValues() {
+		if got := s.IsTerminal(); got != terminal[s] {
+			t.Errorf("%q.IsTerminal() = %v, want %v", s, got, terminal[s])
+		}
+		if got := s.IsActive(); got == terminal[s] {
+			t.Errorf("%q.IsActive() = %v, want %v", s, got, !terminal[s])
+		}
+	}
+
+	unknown := AssessmentState("archived")
+	if unknown.IsTerminal() || unknown.IsActive() {
+		t.Errorf("undocumented state classified as terminal or active")
+	}
+}
+
+func TestFindingStateIsResolved(t *testing.T) {
+	resolved := map[FindingState]bool{
+		FindingStateFixed:   true,
+		FindingStateInvalid: true,
+	}
+	for _, s := range FindingStateValues() {
+		if got := s.IsResolved(); got != resolved[s] {
+			t.Errorf("%q.IsResolved() = %v, want %v", s, got, resolved[s])
+		}
+	}
+}
+
+func TestFindingSeverityRank(t *testing.T) {
+	ascending := []FindingSeverity{
+		FindingSeverityInformational,
+		FindingSeverityLow,
+		FindingSeverityMedium,
+		FindingSeverityHigh,
+		FindingSeverityCritical,
+	}
+	for i := 1; i < len(ascending); i++ {
+		if ascending[i-1].Rank() >= ascending[i].Rank() {
+			t.Errorf("%q.Rank() = %d, not below %q.Rank() = %d", ascending[i-1], ascending[i-1].Rank(), ascending[i], ascending[i].Rank())
+		}
+	}
+	if r := FindingSeverity("severe").Rank(); r >= FindingSeverityInformational.Rank() {
+		t.Errorf("unknown severity ranks %d, want below informational", r)
+	}
+}
+
+func TestFindingSeverityAtLeast(t *testing.T) {
+	tests := []struct {
+		s, other FindingSeverity
+		want     bool
+	}{
+		{FindingSeverityHigh, FindingSeverityHigh, true},
+		{FindingSeverityCritical, FindingSeverityHigh, true},
+		{FindingSeverityMedium, FindingSeverityHigh, false},
+		{FindingSeverityInformational, FindingSeverityInformational, true},
+		{FindingSeverityInformational, FindingSeverityLow, false},
+		{FindingSeverityCritical, FindingSeverityCritical, true},
+		{FindingSeverity("severe"), FindingSeverityInformational, false},
+		{FindingSeverityInformational, FindingSeverity("severe"), true},
+	}
+	for _, tt := range tests {
+		if got := tt.s.AtLeast(tt.other); got != tt.want {
+			t.Errorf("%q.AtLeast(%q) = %v, want %v", tt.s, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookEventTypeMatches(t *testing.T) {
+	tests := []struct {
+		subscribed, actual WebhookEventType
+		want               bool
+	}{
+		{WebhookEventTypeFindingChanged, WebhookEventTypeFindingChanged, true},
+		{WebhookEventTypeFindingChanged, WebhookEventTypeAssetChanged, false},
+		{WebhookEventTypePing, WebhookEventTypeAll, false},
+		{WebhookEventTypeAll, WebhookEventTypeFindingChanged, true},
+		{WebhookEventTypeAll, WebhookEventTypePing, true},
+		{WebhookEventTypeAll, "something.new", true},
+	}
+	for _, tt := range tests {
+		if got := tt.subscribed.Matches(tt.actual); got != tt.want {
+			t.Errorf("%q.Matches(%q) = %v, want %v", tt.subscribed, tt.actual, got, tt.want)
+		}
+	}
+
+	set := WebhookEventSet{WebhookEventTypeAssetChanged, WebhookEventTypeFindingChanged}
+	if !set.Contains(WebhookEventTypeFindingChanged) || set.Contains(WebhookEventTypePing) {
+		t.Errorf("%v.Contains gave wrong result", set)
+	}
+	if !(WebhookEventSet{WebhookEventTypeAll}).Contains(WebhookEventTypePing) {
+		t.Error("wildcard set does not contain ping")
+	}
+	if (WebhookEventSet{}).Contains(WebhookEventTypePing) {
+		t.Error("empty set contains ping")
+	}
+}
+
+func TestEnumParseErrorListsValues(t *testing.T) {
+	_, err := ParseOrganizationState("suspended")
+	want := `invalid organization state "suspended" (valid: active, disabled)`
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Message != want {
+		t.Errorf("error = %v, want message %q", err, want)
+	}
+}
+
+type enum interface {
+	~string
+	IsValid() bool
+	String() string
+}
+
+func wrapParse[T ~string](parse func(string) (T, error)) func(string) (string, error) {
+	return func(s string) (string, error) {
+		v, err := parse(s)
+		return string(v), err
+	}
+}
+
+func isValid[T enum](s string) bool {
+	return T(s).IsValid()
+}
+
+func checkValues[T enum](t *testing.T, values []T, parse func(string) (T, error), want int) {
+	t.Helper()
+	if len(values) != want {
+		t.Errorf("%T: got %d values, want %d", values, len(values), want)
+	}
+	for _, v := range values {
+		got, err := parse(v.String())
+		if err != nil || got != v {
+			t.Errorf("Parse(%q) = %q, %v", v, got, err)
+		}
+		if !v.IsValid() {
+			t.Errorf("%q.IsValid() = false", v)
+		}
+	}
+}
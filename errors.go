@@ -4,15 +4,34 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/doordash-oss/oapi-codegen-dd/v3/pkg/runtime"
+	"github.com/rsclarke/xbow/internal/api"
 )
 
 // apiErrorEnvelope is used to extract structured error info from API responses.
 type apiErrorEnvelope struct {
-	Code    string `json:"code"`
-	Error   string `json:"error"`
-	Message string `json:"message"`
+	Code            string `json:"code"`
+	Error           string `json:"error"`
+	Message         string `json:"message"`
+	RequiredVersion string `json:"requiredVersion"`
+
+	// Validation and ValidationContext carry per-field detail on
+	// FST_ERR_VALIDATION responses, when the server includes it.
+	Validation        []validationItem `json:"validation"`
+	ValidationContext string           `json:"validationContext"`
+}
+
+// validationItem is one entry of the validation array on an
+// FST_ERR_VALIDATION response.
+type validationItem struct {
+	InstancePath string `json:"instancePath"`
+	Message      string `json:"message"`
+	Params       struct {
+		MissingProperty string `json:"missingProperty"`
+	} `json:"params"`
 }
 
 // Error codes returned by the API.
@@ -20,6 +39,10 @@ const (
 	ErrCodeValidation     = "FST_ERR_VALIDATION"
 	ErrCodeNotFound       = "ERR_NOT_FOUND"
 	ErrCodeQuotaExhausted = "ERR_QUOTA_EXHAUSTED"
+
+	// ErrCodeUnsupportedAPIVersion is returned when the requested operation
+	// does not exist in the API version the client is pinned to.
+	ErrCodeUnsupportedAPIVersion = "ERR_UNSUPPORTED_API_VERSION"
 )
 
 // Sentinel errors for use with errors.Is.
@@ -31,10 +54,45 @@ var (
 	ErrRateLimited    = errors.New("rate limited")
 	ErrInternalServer = errors.New("internal server error")
 
+	// ErrUnsupportedInAPIVersion matches errors for operations that were
+	// removed from, or not yet added to, the API version in use: a 410 Gone
+	// or an ERR_UNSUPPORTED_API_VERSION code. The *Error's RequiredVersion
+	// names the version to upgrade to when the server reports it.
+	ErrUnsupportedInAPIVersion = errors.New("operation not supported in this API version")
+
+	// ErrQuotaExhausted matches errors with the ERR_QUOTA_EXHAUSTED code,
+	// such as creating an assessment without enough attack credits left,
+	// whatever the HTTP status. Waiting does not help, so such errors do
+	// not match ErrRateLimited even when the status is 429.
+	ErrQuotaExhausted = errors.New("quota exhausted")
+
 	// Client-side configuration errors.
 	ErrMissingOrgKey         = errors.New("xbow: organization key is required")
 	ErrMissingIntegrationKey = errors.New("xbow: integration key is required")
 	ErrMissingAnyKey         = errors.New("xbow: organization key or integration key is required")
+
+	// ErrInvalidStateTransition is returned when a guarded operation, such as
+	// Pause with WithPauseStateCheck, is not allowed from the resource's
+	// current state.
+	ErrInvalidStateTransition = errors.New("xbow: invalid state transition")
+
+	// ErrResponseTooLarge matches errors for responses whose body exceeded
+	// the WithMaxResponseBytes limit.
+	ErrResponseTooLarge = errors.New("xbow: response too large")
+
+	// ErrCircuitOpen is returned without contacting the server while the
+	// circuit breaker enabled by WithCircuitBreaker is open.
+	ErrCircuitOpen = errors.New("xbow: circuit breaker open")
+
+	// ErrUnsupportedWebhookVersion is returned by ParseWebhookEvent when a
+	// payload declares an API version the SDK cannot decode.
+	ErrUnsupportedWebhookVersion = errors.New("xbow: unsupported webhook API version")
+
+	// ErrInvalidWebhookPayload matches errors for webhook payloads that
+	// cannot be decoded: those from ParseWebhookEvent, which have the
+	// ERR_INVALID_PAYLOAD code, and any a WebhookHandlerFunc wraps it in to
+	// have the delivery rejected with 400 Bad Request instead of retried.
+	ErrInvalidWebhookPayload = errors.New("xbow: invalid webhook payload")
 )
 
 // Error represents an API error response.
@@ -44,13 +102,74 @@ type Error struct {
 	ErrorType  string `json:"error"`
 	Message    string `json:"message"`
 	Wrapped    error  `json:"-"`
+
+	// RequiredVersion is the API version needed for the operation, when the
+	// server reports one alongside an unsupported-version error.
+	RequiredVersion string `json:"requiredVersion,omitempty"`
+
+	// RequestID is the server's id for the failed request, from the
+	// X-Request-Id response header. Quote it when reporting problems to
+	// XBOW. It is empty for client-side errors.
+	RequestID string `json:"requestId,omitempty"`
+
+	// Attempts is the number of attempts made before giving up, when the
+	// client's RetryPolicy has WrapExhausted set and every attempt got a
+	// retryable response. It is zero otherwise.
+	Attempts int `json:"-"`
+
+	// fields holds per-field problems parsed from a validation error. See
+	// AsValidationError.
+	fields []FieldError
+}
+
+// FieldError describes a problem with one field of a rejected request.
+type FieldError struct {
+	// Path locates the field, such as "body/startUrl" or "querystring/limit".
+	Path string
+	// Message describes the problem, such as `must match format "uri"`.
+	Message string
+}
+
+// ValidationError is a FST_ERR_VALIDATION error broken down by field. It is
+// obtained with AsValidationError; the error returned by the client is still
+// an *Error, so errors.Is(err, ErrBadRequest) and errors.As(err, &apiErr)
+// keep working.
+type ValidationError struct {
+	*Error
+	Fields []FieldError
+}
+
+// AsValidationError reports whether err is an API validation error and, if
+// so, returns its per-field detail. Fields may be empty when the server gave
+// no detail beyond the message.
+//
+// Example:
+//
+//	if verr, ok := xbow.AsValidationError(err); ok {
+//	    for _, f := range verr.Fields {
+//	        fmt.Printf("%s: %s\n", f.Path, f.Message)
+//	    }
+//	}
+func AsValidationError(err error) (*ValidationError, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != ErrCodeValidation {
+		return nil, false
+	}
+	return &ValidationError{Error: apiErr, Fields: apiErr.fields}, true
 }
 
 func (e *Error) Error() string {
+	var requestID string
+	if e.RequestID != "" {
+		requestID = ", request_id=" + e.RequestID
+	}
+	if e.Attempts > 0 {
+		requestID += fmt.Sprintf(", attempts=%d", e.Attempts)
+	}
 	if e.Message != "" {
-		return fmt.Sprintf("xbow: %s (status=%d, code=%s)", e.Message, e.StatusCode, e.Code)
+		return fmt.Sprintf("xbow: %s (status=%d, code=%s%s)", e.Message, e.StatusCode, e.Code, requestID)
 	}
-	return fmt.Sprintf("xbow: %s (status=%d)", e.ErrorType, e.StatusCode)
+	return fmt.Sprintf("xbow: %s (status=%d%s)", e.ErrorType, e.StatusCode, requestID)
 }
 
 // Unwrap returns the wrapped error.
@@ -67,16 +186,30 @@ func (e *Error) Is(target error) bool {
 		return true
 	case errors.Is(target, ErrForbidden) && e.StatusCode == 403:
 		return true
-	case errors.Is(target, ErrNotFound) && e.StatusCode == 404:
+	case errors.Is(target, ErrUnsupportedInAPIVersion) && e.isUnsupportedInAPIVersion():
+		return true
+	case errors.Is(target, ErrNotFound) && e.StatusCode == 404 && !e.isUnsupportedInAPIVersion():
+		return true
+	case errors.Is(target, ErrQuotaExhausted) && e.Code == ErrCodeQuotaExhausted:
 		return true
-	case errors.Is(target, ErrRateLimited) && e.StatusCode == 429:
+	case errors.Is(target, ErrRateLimited) && e.StatusCode == 429 && e.Code != ErrCodeQuotaExhausted:
 		return true
 	case errors.Is(target, ErrInternalServer) && e.StatusCode >= 500:
 		return true
+	case errors.Is(target, ErrResponseTooLarge) && e.Code == ErrCodeResponseTooLarge:
+		return true
+	case errors.Is(target, ErrInvalidWebhookPayload) && e.Code == "ERR_INVALID_PAYLOAD":
+		return true
 	}
 	return false
 }
 
+// isUnsupportedInAPIVersion reports whether the error says the operation
+// does not exist in the API version in use.
+func (e *Error) isUnsupportedInAPIVersion() bool {
+	return e.StatusCode == 410 || e.Code == ErrCodeUnsupportedAPIVersion
+}
+
 // IsNotFound returns true if the error is a 404 Not Found error.
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
@@ -87,6 +220,18 @@ func IsRateLimited(err error) bool {
 	return errors.Is(err, ErrRateLimited)
 }
 
+// IsQuotaExhausted returns true if the error is an ERR_QUOTA_EXHAUSTED
+// error.
+func IsQuotaExhausted(err error) bool {
+	return errors.Is(err, ErrQuotaExhausted)
+}
+
+// IsUnsupportedInAPIVersion returns true if the operation is not available in
+// the API version in use.
+func IsUnsupportedInAPIVersion(err error) bool {
+	return errors.Is(err, ErrUnsupportedInAPIVersion)
+}
+
 // wrapError converts a generated client error to our Error type.
 func wrapError(err error) error {
 	if err == nil {
@@ -106,6 +251,8 @@ func wrapError(err error) error {
 			apiErr.Code = parsed.Code
 			apiErr.ErrorType = parsed.Error
 			apiErr.Message = parsed.Message
+			apiErr.RequiredVersion = parsed.RequiredVersion
+			apiErr.fields = parsed.fieldErrors()
 		} else {
 			// Fall back to status-based defaults
 			switch apiErr.StatusCode {
@@ -119,6 +266,8 @@ func wrapError(err error) error {
 			case 404:
 				apiErr.ErrorType = "Not Found"
 				apiErr.Code = ErrCodeNotFound
+			case 410:
+				apiErr.ErrorType = "Gone"
 			case 429:
 				apiErr.ErrorType = "Too Many Requests"
 			default:
@@ -147,6 +296,8 @@ func wrapRawError(statusCode int, body []byte) *Error {
 		apiErr.Code = envelope.Code
 		apiErr.ErrorType = envelope.Error
 		apiErr.Message = envelope.Message
+		apiErr.RequiredVersion = envelope.RequiredVersion
+		apiErr.fields = envelope.fieldErrors()
 	} else {
 		switch statusCode {
 		case 400:
@@ -159,6 +310,8 @@ func wrapRawError(statusCode int, body []byte) *Error {
 		case 404:
 			apiErr.ErrorType = "Not Found"
 			apiErr.Code = ErrCodeNotFound
+		case 410:
+			apiErr.ErrorType = "Gone"
 		case 429:
 			apiErr.ErrorType = "Too Many Requests"
 		default:
@@ -186,5 +339,61 @@ func parseAPIError(err error) *apiErrorEnvelope {
 		return &envelope
 	}
 
+	// Typed error responses from the generated client report only
+	// "unmapped client error" from Error(), but expose the decoded body.
+	var resp api.ErrorResponse
+	if errors.As(err, &resp) {
+		code, errorType, message := resp.ErrorFields()
+		if code != "" {
+			return &apiErrorEnvelope{Code: code, Error: errorType, Message: message}
+		}
+	}
+
 	return nil
 }
+
+// validationContexts are the request parts Fastify names at the start of
+// each problem in a validation message.
+var validationContexts = []string{"body", "querystring", "params", "headers"}
+
+// fieldErrors returns the per-field problems of a validation error. It uses
+// the structured validation array when present, and otherwise splits the
+// message, which joins problems as "body/name must ..., body/url must ...".
+func (e *apiErrorEnvelope) fieldErrors() []FieldError {
+	if e.Code != ErrCodeValidation {
+		return nil
+	}
+
+	if len(e.Validation) > 0 {
+		fields := make([]FieldError, 0, len(e.Validation))
+		for _, v := range e.Validation {
+			path := e.ValidationContext + v.InstancePath
+			if v.Params.MissingProperty != "" {
+				path += "/" + v.Params.MissingProperty
+			}
+			fields = append(fields, FieldError{Path: strings.TrimPrefix(path, "/"), Message: v.Message})
+		}
+		return fields
+	}
+
+	var fields []FieldError
+	for _, part := range strings.Split(e.Message, ", ") {
+		path, msg, ok := strings.Cut(part, " ")
+		if ok && isValidationPath(path) {
+			fields = append(fields, FieldError{Path: path, Message: msg})
+			continue
+		}
+		// Not the start of a new problem: a comma inside the previous message.
+		if len(fields) == 0 {
+			return nil
+		}
+		fields[len(fields)-1].Message += ", " + part
+	}
+	return fields
+}
+
+// isValidationPath reports whether s looks like "body" or "body/name".
+func isValidationPath(s string) bool {
+	ctx, _, _ := strings.Cut(s, "/")
+	return slices.Contains(validationContexts, ctx)
+}
@@ -1,8 +1,11 @@
 package xbow
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/doordash-oss/oapi-codegen-dd/v3/pkg/runtime"
@@ -24,6 +27,16 @@ func TestErrorMessage(t *testing.T) {
 			err:  Error{StatusCode: 500, ErrorType: "Internal Server Error"},
 			want: "xbow: Internal Server Error (status=500)",
 		},
+		{
+			name: "with request id",
+			err:  Error{StatusCode: 404, Code: "ERR_NOT_FOUND", Message: "Assessment not found", RequestID: "req-123"},
+			want: "xbow: Assessment not found (status=404, code=ERR_NOT_FOUND, request_id=req-123)",
+		},
+		{
+			name: "with request id without message",
+			err:  Error{StatusCode: 500, ErrorType: "Internal Server Error", RequestID: "req-123"},
+			want: "xbow: Internal Server Error (status=500, request_id=req-123)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -49,6 +62,9 @@ func TestErrorIs(t *testing.T) {
 		{"429 is ErrRateLimited", 429, ErrRateLimited, true},
 		{"500 is ErrInternalServer", 500, ErrInternalServer, true},
 		{"502 is ErrInternalServer", 502, ErrInternalServer, true},
+		{"410 is ErrUnsupportedInAPIVersion", 410, ErrUnsupportedInAPIVersion, true},
+		{"410 is not ErrNotFound", 410, ErrNotFound, false},
+		{"404 is not ErrUnsupportedInAPIVersion", 404, ErrUnsupportedInAPIVersion, false},
 		{"404 is not ErrBadRequest", 404, ErrBadRequest, false},
 		{"200 is not ErrNotFound", 200, ErrNotFound, false},
 	}
@@ -195,6 +211,74 @@ func TestWrapRawError(t *testing.T) {
 	})
 }
 
+func TestErrorRequestID(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderRequestID, "req-"+r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"Not found"}`))
+	}))
+
+	tests := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{
+			name: "generated client",
+			call: func() error {
+				_, err := client.Assets.Get(context.Background(), "asset-1")
+				return err
+			},
+			want: "req-/api/v1/assets/asset-1",
+		},
+		{
+			name: "raw request",
+			call: func() error {
+				_, err := client.Reports.Get(context.Background(), "report-1")
+				return err
+			},
+			want: "req-/api/v1/reports/report-1",
+		},
+		{
+			name: "no content response",
+			call: func() error {
+				return client.Webhooks.Delete(context.Background(), "webhook-1")
+			},
+			want: "req-/api/v1/webhooks/webhook-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *Error, got %T: %v", err, err)
+			}
+			if apiErr.RequestID != tt.want {
+				t.Errorf("RequestID = %q, want %q", apiErr.RequestID, tt.want)
+			}
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("errors.Is(err, ErrNotFound) = false")
+			}
+		})
+	}
+
+	t.Run("no header", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"Not found"}`))
+		}))
+		_, err := client.Assets.Get(context.Background(), "asset-1")
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.RequestID != "" {
+			t.Errorf("error = %v, want *Error without RequestID", err)
+		}
+	})
+}
+
 func TestIsNotFound(t *testing.T) {
 	notFoundErr := &Error{StatusCode: 404}
 	otherErr := &Error{StatusCode: 500}
@@ -218,3 +302,265 @@ func TestIsRateLimited(t *testing.T) {
 		t.Error("IsRateLimited() should return false for non-429")
 	}
 }
+
+func TestIsQuotaExhausted(t *testing.T) {
+	body := []byte(`{"code":"ERR_QUOTA_EXHAUSTED","error":"Payment Required","message":"Not enough attack credits"}`)
+	for _, status := range []int{402, 429} {
+		got := wrapRawError(status, body)
+		if !IsQuotaExhausted(got) {
+			t.Errorf("status %d: IsQuotaExhausted() should return true for ERR_QUOTA_EXHAUSTED", status)
+		}
+		if IsRateLimited(got) {
+			t.Errorf("status %d: IsRateLimited() should return false for ERR_QUOTA_EXHAUSTED", status)
+		}
+	}
+
+	if IsQuotaExhausted(&Error{StatusCode: 429}) {
+		t.Error("IsQuotaExhausted() should return false for a plain 429")
+	}
+
+	t.Run("assessment creation", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write(body)
+		}))
+
+		_, err := client.Assessments.Create(context.Background(), "asset-1", &CreateAssessmentRequest{AttackCredits: 100})
+		if !errors.Is(err, ErrQuotaExhausted) {
+			t.Errorf("error = %v, want ErrQuotaExhausted", err)
+		}
+	})
+}
+
+func TestIsUnsupportedInAPIVersion(t *testing.T) {
+	t.Run("410 with code carries required version", func(t *testing.T) {
+		body := []byte(`{"code":"ERR_UNSUPPORTED_API_VERSION","error":"Gone","message":"Endpoint removed","requiredVersion":"2026-02-01"}`)
+		got := wrapRawError(410, body)
+
+		if !IsUnsupportedInAPIVersion(got) {
+			t.Error("IsUnsupportedInAPIVersion() should return true for 410")
+		}
+		if IsNotFound(got) {
+			t.Error("IsNotFound() should return false for 410")
+		}
+		if got.Code != ErrCodeUnsupportedAPIVersion {
+			t.Errorf("Code = %q, want %q", got.Code, ErrCodeUnsupportedAPIVersion)
+		}
+		if got.RequiredVersion != "2026-02-01" {
+			t.Errorf("RequiredVersion = %q, want '2026-02-01'", got.RequiredVersion)
+		}
+	})
+
+	t.Run("404 with code is not ErrNotFound", func(t *testing.T) {
+		body := []byte(`{"code":"ERR_UNSUPPORTED_API_VERSION","error":"Not Found","message":"Unknown in this version","requiredVersion":"next"}`)
+		got := wrapRawError(404, body)
+
+		if !IsUnsupportedInAPIVersion(got) {
+			t.Error("IsUnsupportedInAPIVersion() should return true for ERR_UNSUPPORTED_API_VERSION")
+		}
+		if IsNotFound(got) {
+			t.Error("IsNotFound() should return false for ERR_UNSUPPORTED_API_VERSION")
+		}
+		if got.RequiredVersion != "next" {
+			t.Errorf("RequiredVersion = %q, want 'next'", got.RequiredVersion)
+		}
+	})
+
+	t.Run("410 without body defaults to Gone", func(t *testing.T) {
+		got := wrapRawError(410, []byte("gone"))
+
+		if !IsUnsupportedInAPIVersion(got) {
+			t.Error("IsUnsupportedInAPIVersion() should return true for 410")
+		}
+		if got.ErrorType != "Gone" {
+			t.Errorf("ErrorType = %q, want 'Gone'", got.ErrorType)
+		}
+	})
+
+	t.Run("wrapError copies required version", func(t *testing.T) {
+		inner := fmt.Errorf(`{"code":"ERR_UNSUPPORTED_API_VERSION","error":"Gone","message":"removed","requiredVersion":"2026-02-01"}`)
+		err := wrapError(runtime.NewClientAPIError(inner, runtime.WithStatusCode(410)))
+
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected *Error, got %T", err)
+		}
+		if !errors.Is(err, ErrUnsupportedInAPIVersion) {
+			t.Error("expected errors.Is(err, ErrUnsupportedInAPIVersion) to be true")
+		}
+		if apiErr.RequiredVersion != "2026-02-01" {
+			t.Errorf("RequiredVersion = %q, want '2026-02-01'", apiErr.RequiredVersion)
+		}
+	})
+}
+
+func TestAsValidationError(t *testing.T) {
+	wantFields := func(t *testing.T, got []FieldError, want []FieldError) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("Fields = %+v, want %+v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Fields[%d] = %+v, want %+v", i, got[i], want[i])
+			}
+		}
+	}
+
+	t.Run("structured validation array", func(t *testing.T) {
+		body := []byte(`{
+			"code": "FST_ERR_VALIDATION",
+			"error": "Bad Request",
+			"message": "body/name must NOT have fewer than 1 characters, body must have required property 'startUrl'",
+			"validationContext": "body",
+			"validation": [
+				{"instancePath": "/name", "message": "must NOT have fewer than 1 characters", "params": {"limit": 1}},
+				{"instancePath": "", "message": "must have required property 'startUrl'", "params": {"missingProperty": "startUrl"}}
+			]
+		}`)
+		err := error(wrapRawError(400, body))
+
+		verr, ok := AsValidationError(err)
+		if !ok {
+			t.Fatal("AsValidationError() = false, want true")
+		}
+		wantFields(t, verr.Fields, []FieldError{
+			{Path: "body/name", Message: "must NOT have fewer than 1 characters"},
+			{Path: "body/startUrl", Message: "must have required property 'startUrl'"},
+		})
+		if verr.StatusCode != 400 {
+			t.Errorf("StatusCode = %d, want 400", verr.StatusCode)
+		}
+		if !errors.Is(err, ErrBadRequest) {
+			t.Error("errors.Is(err, ErrBadRequest) = false, want true")
+		}
+	})
+
+	t.Run("fields parsed from message", func(t *testing.T) {
+		jsonErr := fmt.Errorf(`{"code":"FST_ERR_VALIDATION","error":"Bad Request","message":"body/startUrl must match format \"uri\", body/maxRequestsPerSecond must be >= 1, querystring/limit must be <= 100"}`)
+		err := wrapError(runtime.NewClientAPIError(jsonErr, runtime.WithStatusCode(400)))
+
+		verr, ok := AsValidationError(err)
+		if !ok {
+			t.Fatal("AsValidationError() = false, want true")
+		}
+		wantFields(t, verr.Fields, []FieldError{
+			{Path: "body/startUrl", Message: `must match format "uri"`},
+			{Path: "body/maxRequestsPerSecond", Message: "must be >= 1"},
+			{Path: "querystring/limit", Message: "must be <= 100"},
+		})
+		if !errors.Is(err, ErrBadRequest) {
+			t.Error("errors.Is(err, ErrBadRequest) = false, want true")
+		}
+	})
+
+	t.Run("comma inside a message", func(t *testing.T) {
+		body := []byte(`{"code":"FST_ERR_VALIDATION","error":"Bad Request","message":"body/action must be equal to one of the allowed values: allow-attack, deny, body/type must be string"}`)
+		verr, ok := AsValidationError(wrapRawError(400, body))
+		if !ok {
+			t.Fatal("AsValidationError() = false, want true")
+		}
+		wantFields(t, verr.Fields, []FieldError{
+			{Path: "body/action", Message: "must be equal to one of the allowed values: allow-attack, deny"},
+			{Path: "body/type", Message: "must be string"},
+		})
+	})
+
+	t.Run("unstructured message has no fields", func(t *testing.T) {
+		body := []byte(`{"code":"FST_ERR_VALIDATION","error":"Bad Request","message":"Invalid request"}`)
+		verr, ok := AsValidationError(wrapRawError(400, body))
+		if !ok {
+			t.Fatal("AsValidationError() = false, want true")
+		}
+		if len(verr.Fields) != 0 {
+			t.Errorf("Fields = %+v, want none", verr.Fields)
+		}
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		if _, ok := AsValidationError(wrapRawError(404, []byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"x"}`))); ok {
+			t.Error("AsValidationError(404) = true, want false")
+		}
+		if _, ok := AsValidationError(errors.New("plain")); ok {
+			t.Error("AsValidationError(plain) = true, want false")
+		}
+	})
+
+	t.Run("through the client", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"FST_ERR_VALIDATION","error":"Bad Request","message":"params/assessmentId must match format \"uuid\""}`))
+		}))
+
+		_, err := client.Assessments.Get(context.Background(), "not-a-uuid")
+		verr, ok := AsValidationError(err)
+		if !ok {
+			t.Fatalf("AsValidationError(%v) = false, want true", err)
+		}
+		wantFields(t, verr.Fields, []FieldError{
+			{Path: "params/assessmentId", Message: `must match format "uuid"`},
+		})
+	})
+}
+
+func TestWrapErrorTypedResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		call   func(*Client) error
+	}{
+		{
+			name:   "single schema",
+			status: http.StatusNotFound,
+			body:   `{"code":"ERR_NOT_FOUND","error":"Not Found","message":"assessment not found"}`,
+			call: func(c *Client) error {
+				_, err := c.Assessments.Get(context.Background(), "a-1")
+				return err
+			},
+		},
+		{
+			name:   "either schema",
+			status: http.StatusPaymentRequired,
+			body:   `{"code":"ERR_QUOTA_EXHAUSTED","error":"Payment Required","message":"no credits left"}`,
+			call: func(c *Client) error {
+				_, err := c.Assessments.Create(context.Background(), "asset-1", &CreateAssessmentRequest{AttackCredits: 1})
+				return err
+			},
+		},
+		{
+			name:   "union schema",
+			status: http.StatusConflict,
+			body:   `{"code":"ERR_INVALID_STATE","error":"Conflict","message":"finding is not open"}`,
+			call: func(c *Client) error {
+				_, err := c.Findings.VerifyFix(context.Background(), "f-1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+
+			err := tt.call(client)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v (%T), want *Error", err, err)
+			}
+			var want apiErrorEnvelope
+			if err := json.Unmarshal([]byte(tt.body), &want); err != nil {
+				t.Fatal(err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.Code != want.Code || apiErr.ErrorType != want.Error || apiErr.Message != want.Message {
+				t.Errorf("error = %+v, want status %d and body %s", apiErr, tt.status, tt.body)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package xbow
 
 import (
 	"context"
+	"fmt"
 	"iter"
+	"slices"
+	"sync"
 
 	"github.com/rsclarke/xbow/internal/api"
 )
@@ -13,12 +16,16 @@ type FindingsService struct {
 }
 
 // Get retrieves a finding by ID.
-func (s *FindingsService) Get(ctx context.Context, id string) (*Finding, error) {
+func (s *FindingsService) Get(ctx context.Context, id string, reqOpts ...RequestOption) (*Finding, error) {
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
 		return nil, err
 	}
 
+	rc := newRequestConfig(reqOpts)
+	ctx, cancel := rc.context(ctx)
+	defer cancel()
+
 	opts := &api.GetAPIV1FindingsFindingIDRequestOptions{
 		PathParams: &api.GetAPIV1FindingsFindingIDPath{
 			FindingID: id,
@@ -28,9 +35,10 @@ func (s *FindingsService) Get(ctx context.Context, id string) (*Finding, error)
 		},
 	}
 
-	resp, err := s.client.raw.GetAPIV1FindingsFindingID(ctx, opts, auth)
+	ctx, rid := recordRequestID(ctx)
+	resp, err := s.client.raw.GetAPIV1FindingsFindingID(ctx, opts, rc.editors(auth)...)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return findingFromGetResponse(resp), nil
@@ -62,9 +70,10 @@ func (s *FindingsService) ListByAsset(ctx context.Context, assetID string, opts
 		}
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1AssetsAssetIDFindings(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return findingsPageFromResponse(resp), nil
@@ -103,14 +112,198 @@ func (s *FindingsService) VerifyFix(ctx context.Context, id string) (*Assessment
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1FindingsFindingIDVerifyFix(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return assessmentFromVerifyFixResponse(resp), nil
 }
 
+// AssetFinding is a finding yielded by AllByOrganization, with the asset it
+// was found on.
+type AssetFinding struct {
+	AssetID string `json:"assetId"`
+	FindingListItem
+}
+
+// allByOrganizationConcurrency bounds the assets whose findings
+// AllByOrganization lists at once, and how far ahead of the caller it
+// fetches.
+const allByOrganizationConcurrency = 4
+
+// assetFindings holds the findings of one asset, or the error that stopped
+// listing them, once done is closed.
+type assetFindings struct {
+	assetID string
+	items   []FindingListItem
+	err     error
+	done    chan struct{}
+}
+
+// AllByOrganization returns an iterator over the findings of every asset in
+// an organization. The API has no organization-wide findings listing, so it
+// lists the organization's assets and then each asset's findings, fetching
+// a few assets ahead of the caller at a time.
+//
+// Findings are yielded grouped by asset, in the order the assets are
+// listed, and within an asset in the order AllByAsset yields them. opts
+// sets the page size of both listings, and MaxItems caps the findings
+// yielded in total; After and Prefetch are ignored. The iterator stops at
+// the first error, after yielding the findings of the assets before it.
+//
+//	for f, err := range client.Findings.AllByOrganization(ctx, orgID, nil) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(f.AssetID, f.Severity, f.Name)
+//	}
+func (s *FindingsService) AllByOrganization(ctx context.Context, organizationID string, opts *ListOptions) iter.Seq2[AssetFinding, error] {
+	var listOpts *ListOptions
+	maxItems := 0
+	if opts != nil {
+		listOpts = &ListOptions{Limit: opts.Limit}
+		maxItems = opts.MaxItems
+	}
+
+	return func(yield func(AssetFinding, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		// The producer sends one slot per asset, in order, starting each
+		// asset's listing as it goes. Each listing holds a token from sem
+		// while it runs, which bounds the listings in flight; the buffer of
+		// slots bounds how far ahead of the consumer the producer gets.
+		slots := make(chan *assetFindings, allByOrganizationConcurrency-1)
+		sem := make(chan struct{}, allByOrganizationConcurrency)
+		// stopErr records why the producer gave up sending. It is read only
+		// after slots is closed.
+		var stopErr error
+		wg.Go(func() {
+			defer close(slots)
+			send := func(slot *assetFindings) bool {
+				select {
+				case slots <- slot:
+					return true
+				case <-ctx.Done():
+					stopErr = ctx.Err()
+					return false
+				}
+			}
+			for asset, err := range s.client.Assets.AllByOrganization(ctx, organizationID, listOpts) {
+				if err != nil {
+					slot := &assetFindings{err: err, done: make(chan struct{})}
+					close(slot.done)
+					send(slot)
+					return
+				}
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					stopErr = ctx.Err()
+					return
+				}
+				slot := &assetFindings{assetID: asset.ID, done: make(chan struct{})}
+				wg.Go(func() {
+					defer func() { <-sem }()
+					defer close(slot.done)
+					slot.items, slot.err = Collect(s.AllByAsset(ctx, slot.assetID, listOpts))
+					if slot.err != nil {
+						slot.err = fmt.Errorf("xbow: listing findings for asset %s: %w", slot.assetID, slot.err)
+					}
+				})
+				if !send(slot) {
+					return
+				}
+			}
+		})
+
+		yielded := 0
+		for slot := range slots {
+			<-slot.done
+			if slot.err != nil {
+				yield(AssetFinding{}, slot.err)
+				return
+			}
+			for _, item := range slot.items {
+				if !yield(AssetFinding{AssetID: slot.assetID, FindingListItem: item}, nil) {
+					return
+				}
+				yielded++
+				if maxItems > 0 && yielded >= maxItems {
+					return
+				}
+			}
+		}
+		// The consumer never stopped, so only the caller's context can
+		// have ended the producer early.
+		if stopErr != nil {
+			yield(AssetFinding{}, stopErr)
+		}
+	}
+}
+
+// verifyFixBatchConcurrency bounds the VerifyFix calls VerifyFixBatch has in
+// flight at once.
+const verifyFixBatchConcurrency = 4
+
+// VerifyFixResult is the outcome of one finding in VerifyFixBatch: the
+// verification assessment on success, or the error.
+type VerifyFixResult struct {
+	FindingID  string
+	Assessment *Assessment
+	Err        error
+}
+
+// VerifyFixBatch requests fix verification for each of ids, a few at a time,
+// and returns one result per id in the same order. A failure for one finding
+// does not stop the others; it is recorded in that finding's result. The
+// returned error is non-nil only when no request could be made, such as
+// when the client has no organization key. Findings not yet started when
+// ctx is done get ctx's error.
+func (s *FindingsService) VerifyFixBatch(ctx context.Context, ids []string) ([]VerifyFixResult, error) {
+	if _, err := s.client.orgAuthEditor(); err != nil {
+		return nil, err
+	}
+
+	results := make([]VerifyFixResult, len(ids))
+	sem := make(chan struct{}, verifyFixBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		results[i].FindingID = id
+		if id == "" {
+			results[i].Err = &Error{Code: "ERR_INVALID_PARAM", Message: "finding id is required"}
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			results[i].Assessment, results[i].Err = s.VerifyFix(ctx, id)
+		})
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// SortFindingsBySeverity sorts items in place, most severe first. Findings
+// of equal severity keep their order. The API has no server-side sort, so
+// this is applied to already-fetched items.
+func SortFindingsBySeverity(items []FindingListItem) {
+	slices.SortStableFunc(items, func(a, b FindingListItem) int {
+		return b.Severity.Rank() - a.Severity.Rank()
+	})
+}
+
 // Conversion functions from generated types to domain types
 
 func findingFromGetResponse(r *api.GetAPIV1FindingsFindingIDResponse) *Finding {
@@ -1,6 +1,13 @@
 package xbow
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -293,3 +300,258 @@ func TestAssessmentFromVerifyFixResponse(t *testing.T) {
 		t.Errorf("AttackCredits = %d, want 40", got.AttackCredits)
 	}
 }
+
+func TestVerifyFixBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/findings/"), "/verify-fix")
+		if strings.HasPrefix(id, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"finding not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(testAssessmentJSON(AssessmentStateWaitingForCapacity)))
+	}))
+
+	ids := []string{"f-1", "missing-1", "f-2", "", "f-3", "missing-2", "f-4", "f-5"}
+	results, err := client.Findings.VerifyFixBatch(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(ids) {
+		t.Fatalf("got %d results, want %d", len(results), len(ids))
+	}
+
+	for i, r := range results {
+		if r.FindingID != ids[i] {
+			t.Errorf("result %d FindingID = %q, want %q", i, r.FindingID, ids[i])
+		}
+		switch {
+		case ids[i] == "":
+			var apiErr *Error
+			if !errors.As(r.Err, &apiErr) || apiErr.Code != "ERR_INVALID_PARAM" {
+				t.Errorf("result %d error = %v, want ERR_INVALID_PARAM", i, r.Err)
+			}
+		case strings.HasPrefix(ids[i], "missing"):
+			if !IsNotFound(r.Err) || r.Assessment != nil {
+				t.Errorf("result %d = %+v, want not found", i, r)
+			}
+		default:
+			if r.Err != nil || r.Assessment == nil || r.Assessment.State != AssessmentStateWaitingForCapacity {
+				t.Errorf("result %d = %+v, want an assessment", i, r)
+			}
+		}
+	}
+
+	if got := maxInFlight.Load(); got > verifyFixBatchConcurrency {
+		t.Errorf("max concurrent requests = %d, want at most %d", got, verifyFixBatchConcurrency)
+	}
+}
+
+func TestVerifyFixBatchSetupError(t *testing.T) {
+	client, err := NewClient(WithIntegrationKey("int-key"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	results, err := client.Findings.VerifyFixBatch(context.Background(), []string{"f-1"})
+	if !errors.Is(err, ErrMissingOrgKey) || results != nil {
+		t.Errorf("got %v, %v, want ErrMissingOrgKey", results, err)
+	}
+}
+
+func TestSortFindingsBySeverity(t *testing.T) {
+	items := []FindingListItem{
+		{ID: "low", Severity: FindingSeverityLow},
+		{ID: "critical", Severity: FindingSeverityCritical},
+		{ID: "high-1", Severity: FindingSeverityHigh},
+		{ID: "info", Severity: FindingSeverityInformational},
+		{ID: "high-2", Severity: FindingSeverityHigh},
+		{ID: "medium", Severity: FindingSeverityMedium},
+	}
+	SortFindingsBySeverity(items)
+
+	var got []string
+	for _, f := range items {
+		got = append(got, f.ID)
+	}
+	want := "critical high-1 high-2 medium low info"
+	if strings.Join(got, " ") != want {
+		t.Errorf("order = %v, want %s", got, want)
+	}
+}
+
+// orgFindingsHandler serves an organization with assets asset-1 and
+// asset-2 (on separate pages), where asset-1 has findings f-1 and f-2 (on
+// separate pages) and asset-2 has f-3. Requests for failAsset's findings
+// fail.
+func orgFindingsHandler(t *testing.T, failAsset string) http.Handler {
+	listItem := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"name":"Finding %s","severity":"high","state":"open","createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}`, id, id)
+	}
+	assetItem := func(id string) string {
+		return fmt.Sprintf(`{"id":%q,"name":"Asset %s","lifecycle":"active","createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}`, id, id)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		after := r.URL.Query().Get("after")
+		switch r.URL.Path {
+		case "/api/v1/organizations/org-1/assets":
+			if after == "" {
+				_, _ = fmt.Fprintf(w, `{"items":[%s],"nextCursor":"a2"}`, assetItem("asset-1"))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"items":[%s]}`, assetItem("asset-2"))
+		case "/api/v1/assets/" + failAsset + "/findings":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"code":"ERR_INTERNAL","error":"Internal Server Error","message":"boom"}`))
+		case "/api/v1/assets/asset-1/findings":
+			if after == "" {
+				_, _ = fmt.Fprintf(w, `{"items":[%s],"nextCursor":"f2"}`, listItem("f-1"))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"items":[%s]}`, listItem("f-2"))
+		case "/api/v1/assets/asset-2/findings":
+			_, _ = fmt.Fprintf(w, `{"items":[%s]}`, listItem("f-3"))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestFindingsAllByOrganization(t *testing.T) {
+	t.Run("flattens findings per asset", func(t *testing.T) {
+		client := newTestClient(t, orgFindingsHandler(t, ""))
+
+		got, err := Collect(client.Findings.AllByOrganization(context.Background(), "org-1", nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var pairs []string
+		for _, f := range got {
+			pairs = append(pairs, f.AssetID+"/"+f.ID)
+		}
+		want := "asset-1/f-1 asset-1/f-2 asset-2/f-3"
+		if strings.Join(pairs, " ") != want {
+			t.Errorf("findings = %v, want %s", pairs, want)
+		}
+		if got[0].Severity != FindingSeverityHigh {
+			t.Errorf("Severity = %q, want high", got[0].Severity)
+		}
+	})
+
+	t.Run("max items", func(t *testing.T) {
+		client := newTestClient(t, orgFindingsHandler(t, ""))
+
+		got, err := Collect(client.Findings.AllByOrganization(context.Background(), "org-1", &ListOptions{MaxItems: 2}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 || got[1].ID != "f-2" {
+			t.Errorf("got %+v, want f-1 and f-2", got)
+		}
+	})
+
+	t.Run("error after earlier assets", func(t *testing.T) {
+		client := newTestClient(t, orgFindingsHandler(t, "asset-2"))
+
+		got, err := Collect(client.Findings.AllByOrganization(context.Background(), "org-1", nil))
+		if len(got) != 2 {
+			t.Errorf("got %d findings before the error, want asset-1's 2", len(got))
+		}
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("error = %v, want the 500 *Error", err)
+		}
+		if !strings.Contains(err.Error(), "asset-2") {
+			t.Errorf("error %q does not name the asset", err)
+		}
+	})
+
+	t.Run("break stops fetching", func(t *testing.T) {
+		client := newTestClient(t, orgFindingsHandler(t, ""))
+
+		for f, err := range client.Findings.AllByOrganization(context.Background(), "org-1", nil) {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f.ID != "f-1" {
+				t.Errorf("first finding = %q, want f-1", f.ID)
+			}
+			break
+		}
+	})
+}
+
+func TestFindingsAllByOrganizationConcurrency(t *testing.T) {
+	const assets = 3 * allByOrganizationConcurrency
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+	)
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/organizations/org-1/assets" {
+			items := make([]string, assets)
+			for i := range items {
+				items[i] = fmt.Sprintf(`{"id":"asset-%d","name":"Asset %d","lifecycle":"active","createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}`, i, i)
+			}
+			_, _ = fmt.Fprintf(w, `{"items":[%s]}`, strings.Join(items, ","))
+			return
+		}
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"items":[]}`))
+	})
+	client := newTestClient(t, handler)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Collect(client.Findings.AllByOrganization(context.Background(), "org-1", nil))
+		done <- err
+	}()
+
+	// Wait for the listings to fill every slot, then give any listing
+	// beyond the bound time to start before checking.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		n := inFlight
+		mu.Unlock()
+		if n >= allByOrganizationConcurrency || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	got := peak
+	mu.Unlock()
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != allByOrganizationConcurrency {
+		t.Errorf("peak listings in flight = %d, want %d", got, allByOrganizationConcurrency)
+	}
+}
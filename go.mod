@@ -6,6 +6,8 @@ require (
 	github.com/doordash-oss/oapi-codegen-dd/v3 v3.66.2
 	github.com/go-playground/validator/v10 v10.30.1
 	github.com/spf13/cobra v1.10.2
+	github.com/spf13/pflag v1.0.10
+	github.com/yuin/goldmark v1.8.2
 )
 
 require (
@@ -22,7 +24,6 @@ require (
 	github.com/pb33f/libopenapi v0.31.2 // indirect
 	github.com/pb33f/ordered-map/v2 v2.3.0 // indirect
 	github.com/rogpeppe/go-internal v1.14.1 // indirect
-	github.com/spf13/pflag v1.0.10 // indirect
 	go.yaml.in/yaml/v4 v4.0.0-rc.3 // indirect
 	golang.org/x/crypto v0.47.0 // indirect
 	golang.org/x/mod v0.31.0 // indirect
@@ -0,0 +1,176 @@
+package api
+
+// This file is not generated. It gives the generated error response types a
+// common accessor, so callers can read a decoded error body with errors.As
+// instead of depending on each type's field names. A field renamed by the
+// generator breaks the build here rather than silently losing error detail.
+
+// ErrorResponse is implemented by every error response type of the
+// generated client. ErrorFields returns the code, error and message fields
+// of the decoded body; all are empty when the body held none of them.
+type ErrorResponse interface {
+	error
+	ErrorFields() (code, errorType, message string)
+}
+
+func (r GetAPIV1AssessmentsAssessmentIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1AssessmentsAssessmentIDCancelErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1AssessmentsAssessmentIDPauseErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1AssessmentsAssessmentIDResumeErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1AssetsAssetIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PutAPIV1AssetsAssetIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1AssetsAssetIDAssessmentsErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1AssetsAssetIDFindingsErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1AssetsAssetIDReportsErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1FindingsFindingIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1IntegrationsIntegrationIDOrganizationsErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1IntegrationsIntegrationIDOrganizationsErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r DeleteAPIV1KeysKeyIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1MetaOpenapiJSONErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1OrganizationsOrganizationIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PutAPIV1OrganizationsOrganizationIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1OrganizationsOrganizationIDAssetsErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1OrganizationsOrganizationIDAssetsErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1OrganizationsOrganizationIDKeysErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1OrganizationsOrganizationIDWebhooksErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1OrganizationsOrganizationIDWebhooksErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1ReportsReportIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1ReportsReportIDSummaryErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r DeleteAPIV1WebhooksWebhookIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1WebhooksWebhookIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PatchAPIV1WebhooksWebhookIDErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r GetAPIV1WebhooksWebhookIDDeliveriesErrorResponse) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1AssetsAssetIDAssessments_ErrorResponse_OneOf_0) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1AssetsAssetIDAssessments_ErrorResponse_OneOf_1) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1WebhooksWebhookIDPing_ErrorResponse_OneOf_0) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1WebhooksWebhookIDPing_ErrorResponse_OneOf_1) ErrorFields() (string, string, string) {
+	return string(r.Code), string(r.ErrorData), r.Message
+}
+
+func (r PostAPIV1AssetsAssetIDAssessmentsErrorResponse) ErrorFields() (string, string, string) {
+	if r.PostAPIV1AssetsAssetIDAssessments_ErrorResponse_OneOf == nil {
+		return "", "", ""
+	}
+	return eitherFields(r.PostAPIV1AssetsAssetIDAssessments_ErrorResponse_OneOf.Value())
+}
+
+func (r PostAPIV1WebhooksWebhookIDPingErrorResponse) ErrorFields() (string, string, string) {
+	if r.PostAPIV1WebhooksWebhookIDPing_ErrorResponse_OneOf == nil {
+		return "", "", ""
+	}
+	return eitherFields(r.PostAPIV1WebhooksWebhookIDPing_ErrorResponse_OneOf.Value())
+}
+
+// PostAPIV1FindingsFindingIDVerifyFix_ErrorResponse_OneOf is a raw union,
+// but all of its variants share the code, error and message fields, so any
+// variant reads them.
+func (r PostAPIV1FindingsFindingIDVerifyFixErrorResponse) ErrorFields() (string, string, string) {
+	if r.PostAPIV1FindingsFindingIDVerifyFix_ErrorResponse_OneOf == nil {
+		return "", "", ""
+	}
+	v, err := r.PostAPIV1FindingsFindingIDVerifyFix_ErrorResponse_OneOf.AsPostAPIV1FindingsFindingIDVerifyFix_ErrorResponse_OneOf_0()
+	if err != nil {
+		return "", "", ""
+	}
+	return string(v.Code), string(v.ErrorData), v.Message
+}
+
+// eitherFields returns the fields of the variant held by a runtime.Either.
+func eitherFields(v any) (string, string, string) {
+	if f, ok := v.(interface {
+		ErrorFields() (string, string, string)
+	}); ok {
+		return f.ErrorFields()
+	}
+	return "", "", ""
+}
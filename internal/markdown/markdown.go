@@ -0,0 +1,173 @@
+// Package markdown renders the Markdown of report summaries as HTML that is
+// safe to embed in a page.
+//
+// Parsing and rendering are done by goldmark, a CommonMark implementation,
+// with its defaults: no extensions and no raw HTML. On top of that, raw
+// HTML in the source is escaped and shown as text rather than dropped, and
+// link and image URLs are limited to http, https, mailto and relative
+// references, so report text cannot inject script.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Limits on the sources Render parses as Markdown. goldmark is linear in
+// the number of lines, but some constructs, such as unclosed link
+// destinations or deeply nested containers, cost time quadratic in the
+// length of the line they are on. Bounding both keeps the worst case for
+// hostile report text to a fraction of a second. Sources beyond either
+// limit are shown escaped in a <pre> block instead.
+const (
+	MaxSize       = 256 << 10
+	MaxLineLength = 4 << 10
+)
+
+var md = goldmark.New(
+	goldmark.WithParserOptions(
+		parser.WithASTTransformers(util.Prioritized(urlFilter{}, 0)),
+	),
+	goldmark.WithRendererOptions(
+		renderer.WithNodeRenderers(util.Prioritized(escapedHTMLRenderer{}, 0)),
+	),
+)
+
+// Render returns src rendered as HTML.
+func Render(src string) string {
+	if !withinLimits(src) {
+		return preformatted(src)
+	}
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(src), &buf); err != nil {
+		// Converting into a bytes.Buffer only fails on a renderer bug; fall
+		// back to showing the source rather than losing it.
+		return preformatted(src)
+	}
+	return buf.String()
+}
+
+// withinLimits reports whether src is no longer than MaxSize and has no
+// line longer than MaxLineLength.
+func withinLimits(src string) bool {
+	if len(src) > MaxSize {
+		return false
+	}
+	for line := range strings.Lines(src) {
+		if len(line) > MaxLineLength {
+			return false
+		}
+	}
+	return true
+}
+
+func preformatted(src string) string {
+	return "<pre>" + html.EscapeString(src) + "</pre>\n"
+}
+
+// urlFilter removes links and images whose URL is not safe, keeping their
+// text, and turns unsafe autolinks back into the text they were written as.
+type urlFilter struct{}
+
+func (urlFilter) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	var unsafe []ast.Node
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link:
+			if !safeURL(string(n.Destination)) {
+				unsafe = append(unsafe, n)
+			}
+		case *ast.Image:
+			if !safeURL(string(n.Destination)) {
+				unsafe = append(unsafe, n)
+			}
+		case *ast.AutoLink:
+			if !safeURL(string(n.URL(source))) {
+				unsafe = append(unsafe, n)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, n := range unsafe {
+		parent := n.Parent()
+		if link, ok := n.(*ast.AutoLink); ok {
+			parent.ReplaceChild(parent, n, ast.NewString([]byte("<"+string(link.URL(source))+">")))
+			continue
+		}
+		for c := n.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			parent.InsertBefore(parent, n, c)
+			c = next
+		}
+		parent.RemoveChild(parent, n)
+	}
+}
+
+// safeURL reports whether u is a relative reference or uses the http,
+// https or mailto scheme.
+func safeURL(u string) bool {
+	if u == "" {
+		return false
+	}
+	i := strings.IndexAny(u, ":/?#")
+	if i < 0 || u[i] != ':' {
+		return true
+	}
+	switch strings.ToLower(u[:i]) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// escapedHTMLRenderer renders raw HTML, inline or as a block, as escaped
+// text. goldmark's safe mode replaces it with a comment, which would hide
+// report text such as a payload quoted outside a code span.
+type escapedHTMLRenderer struct{}
+
+func (escapedHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindRawHTML, renderRawHTML)
+	reg.Register(ast.KindHTMLBlock, renderHTMLBlock)
+}
+
+func renderRawHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	n := node.(*ast.RawHTML)
+	for i := range n.Segments.Len() {
+		seg := n.Segments.At(i)
+		_, _ = w.WriteString(html.EscapeString(string(seg.Value(source))))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func renderHTMLBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.HTMLBlock)
+	var b strings.Builder
+	for i := range n.Lines().Len() {
+		line := n.Lines().At(i)
+		b.Write(line.Value(source))
+	}
+	if n.HasClosure() {
+		b.Write(n.ClosureLine.Value(source))
+	}
+	_, _ = w.WriteString("<p>" + html.EscapeString(strings.TrimRight(b.String(), "\n")) + "</p>\n")
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,140 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"heading", "# Summary", "<h1>Summary</h1>\n"},
+		{"heading closing hashes", "### Details ###", "<h3>Details</h3>\n"},
+		{"not a heading", "#hashtag", "<p>#hashtag</p>\n"},
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one\ntwo</p>\n<p>three</p>\n"},
+		{
+			"fenced code",
+			"```http\nGET /?q=<b> HTTP/1.1\n```",
+			"<pre><code class=\"language-http\">GET /?q=&lt;b&gt; HTTP/1.1\n</code></pre>\n",
+		},
+		{"unsafe language", "```\"><script>\nx\n```", "<pre><code class=\"language-&quot;&gt;&lt;script&gt;\">x\n</code></pre>\n"},
+		{"unclosed fence", "~~~\na", "<pre><code>a\n</code></pre>\n"},
+		{"rule", "a\n\n---\n", "<p>a</p>\n<hr>\n"},
+		{"quote", "> quoted\n> text", "<blockquote>\n<p>quoted\ntext</p>\n</blockquote>\n"},
+		{"bullets", "- one\n- two", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n"},
+		{"ordered", "3. three\n4. four", "<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n"},
+		{
+			"nested list",
+			"- parent\n  - child\n- next",
+			"<ul>\n<li>parent\n<ul>\n<li>child</li>\n</ul>\n</li>\n<li>next</li>\n</ul>\n",
+		},
+		{"list ends paragraph", "Steps:\n1. go", "<p>Steps:</p>\n<ol>\n<li>go</li>\n</ol>\n"},
+		{"code span", "run `rm -rf <dir>`", "<p>run <code>rm -rf &lt;dir&gt;</code></p>\n"},
+		{"emphasis", "*a* **b** _c_", "<p><em>a</em> <strong>b</strong> <em>c</em></p>\n"},
+		{"nested emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>\n"},
+		{"snake case", "user_id and api_key", "<p>user_id and api_key</p>\n"},
+		{"unmatched", "2 * 3 and **x", "<p>2 * 3 and **x</p>\n"},
+		{"escape", `\*not em\*`, "<p>*not em*</p>\n"},
+		{"link", "[docs](https://example.com/a?b=1&c=2)", "<p><a href=\"https://example.com/a?b=1&amp;c=2\">docs</a></p>\n"},
+		{"relative link", "[x](/findings/1)", "<p><a href=\"/findings/1\">x</a></p>\n"},
+		{"autolink", "<https://example.com>", "<p><a href=\"https://example.com\">https://example.com</a></p>\n"},
+		{"image", "![shot](https://example.com/s.png)", "<p><img src=\"https://example.com/s.png\" alt=\"shot\"></p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src); got != tt.want {
+				t.Errorf("Render(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderNeutralizesHTML(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"script", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"attribute", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{"javascript link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"mixed case scheme", "[click](JavaScript:alert(1))", "<p>click</p>\n"},
+		{"data image", "![x](data:image/svg+xml,%3Csvg%20onload=alert(1)%3E)", "<p>x</p>\n"},
+		{"javascript autolink", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>\n"},
+		{"quote in url", `[x](https://e.com/"onmouseover="alert(1))`, "<p><a href=\"https://e.com/%22onmouseover=%22alert(1)\">x</a></p>\n"},
+		{"heading", "# <script>x</script>", "<h1>&lt;script&gt;x&lt;/script&gt;</h1>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.src)
+			if got != tt.want {
+				t.Errorf("Render(%q)\n got %q\nwant %q", tt.src, got, tt.want)
+			}
+			if strings.Contains(strings.ToLower(got), "<script") {
+				t.Errorf("Render(%q) contains a script tag: %q", tt.src, got)
+			}
+		})
+	}
+}
+
+func TestRenderLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		pre  bool
+	}{
+		{"at limits", strings.Repeat(strings.Repeat("a", MaxLineLength-1)+"\n", MaxSize/MaxLineLength-1), false},
+		{"too large", strings.Repeat("a\n", MaxSize/2+1), true},
+		{"line too long", "# Title\n\n" + strings.Repeat("a", MaxLineLength+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.src + "<script>")
+			if pre := strings.HasPrefix(got, "<pre>"); pre != tt.pre {
+				t.Errorf("rendered as <pre> = %v, want %v", pre, tt.pre)
+			}
+			if strings.Contains(got, "<script>") {
+				t.Error("output contains a script tag")
+			}
+		})
+	}
+}
+
+// pathological lists inputs that made earlier renderers take time quadratic
+// in their length. Each is repeated up to the size limits.
+var pathological = []string{"_a ", "[a](", "[", "*_", "<a ", "![", "> ", "- > ", "* - > 1. "}
+
+// fillLimits repeats p on lines of MaxLineLength up to MaxSize, the largest
+// input Render parses.
+func fillLimits(p string) string {
+	line := strings.Repeat(p, (MaxLineLength-1)/len(p)) + "\n"
+	return strings.Repeat(line, MaxSize/len(line))
+}
+
+func TestRenderPathological(t *testing.T) {
+	if testing.Short() {
+		t.Skip("slow")
+	}
+	for _, p := range pathological {
+		src := fillLimits(p)
+		start := time.Now()
+		Render(src)
+		// The worst of these takes about half a second; the quadratic
+		// renderer this replaced took minutes.
+		if d := time.Since(start); d > 10*time.Second {
+			t.Errorf("Render(%q repeated) took %v", p, d)
+		}
+	}
+}
+
+func BenchmarkRenderPathological(b *testing.B) {
+	for _, p := range pathological {
+		src := fillLimits(p)
+		b.Run(strings.TrimSpace(p), func(b *testing.B) {
+			b.SetBytes(int64(len(src)))
+			for b.Loop() {
+				Render(src)
+			}
+		})
+	}
+}
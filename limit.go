@@ -0,0 +1,103 @@
+package xbow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// defaultMaxResponseBytes caps response bodies when WithMaxResponseBytes is
+// not given.
+const defaultMaxResponseBytes = 32 << 20 // 32 MB
+
+// ErrCodeResponseTooLarge is the code of the *Error returned when a response
+// body exceeds the WithMaxResponseBytes limit.
+const ErrCodeResponseTooLarge = "ERR_RESPONSE_TOO_LARGE"
+
+// WithMaxResponseBytes caps the size of response bodies the client will
+// read. A response that exceeds it fails with an *Error whose Code is
+// ErrCodeResponseTooLarge, which matches ErrResponseTooLarge, instead of
+// being decoded. Default is 32 MB; zero or less removes the cap.
+//
+// Report downloads (ReportsService.Get and Download) stream and are not
+// capped.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.maxResponseBytes = n
+	}
+}
+
+type unlimitedBodyKey struct{}
+
+// withUnlimitedBody marks ctx so that responses to its requests are not
+// capped by WithMaxResponseBytes.
+func withUnlimitedBody(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unlimitedBodyKey{}, true)
+}
+
+// maxBytesTransport caps the response body of each request passed to base.
+type maxBytesTransport struct {
+	base  http.RoundTripper
+	limit int64
+}
+
+func (t *maxBytesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		return resp, err
+	}
+	if unlimited, _ := req.Context().Value(unlimitedBodyKey{}).(bool); unlimited {
+		return resp, nil
+	}
+
+	// Fail early when the server declares an oversized body.
+	if resp.ContentLength > t.limit {
+		_ = resp.Body.Close()
+		return nil, responseTooLarge(resp.StatusCode, t.limit)
+	}
+
+	resp.Body = &maxBytesBody{body: resp.Body, remaining: t.limit, limit: t.limit, status: resp.StatusCode}
+	return resp, nil
+}
+
+// maxBytesBody reads at most limit bytes from body, then fails with
+// ERR_RESPONSE_TOO_LARGE if more remain.
+type maxBytesBody struct {
+	body      io.ReadCloser
+	remaining int64
+	limit     int64
+	status    int
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Look for one more byte to tell a body of exactly limit bytes from
+		// an oversized one.
+		var one [1]byte
+		n, err := b.body.Read(one[:])
+		if n > 0 {
+			return 0, responseTooLarge(b.status, b.limit)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *maxBytesBody) Close() error {
+	return b.body.Close()
+}
+
+func responseTooLarge(status int, limit int64) *Error {
+	return &Error{
+		StatusCode: status,
+		Code:       ErrCodeResponseTooLarge,
+		Message:    fmt.Sprintf("response body exceeds %d bytes", limit),
+	}
+}
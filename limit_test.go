@@ -0,0 +1,114 @@
+package xbow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// oversizedReportSummary returns a fake transport that answers every request
+// with a JSON report summary whose body is about size bytes. When
+// declareLength is false the Content-Length is unknown, as with chunked
+// responses.
+func oversizedReportSummary(size int, declareLength bool) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"markdown":"` + strings.Repeat("a", size) + `"}`
+		resp := &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: -1,
+			Request:       req,
+		}
+		if declareLength {
+			resp.ContentLength = int64(len(body))
+		}
+		return resp, nil
+	})
+}
+
+func TestWithMaxResponseBytes(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int64
+		size          int
+		declareLength bool
+		wantErr       bool
+	}{
+		{name: "within limit", limit: 1024, size: 100},
+		{name: "unknown length over limit", limit: 1024, size: 4096, wantErr: true},
+		{name: "declared length over limit", limit: 1024, size: 4096, declareLength: true, wantErr: true},
+		{name: "no limit", limit: 0, size: 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(
+				WithBaseURL("https://xbow.test"),
+				WithOrganizationKey("test-org-key"),
+				WithHTTPClient(&http.Client{Transport: oversizedReportSummary(tt.size, tt.declareLength)}),
+				WithMaxResponseBytes(tt.limit),
+			)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			_, err = c.Reports.GetSummary(context.Background(), "report-1")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("error = %v, want ErrResponseTooLarge", err)
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Code != ErrCodeResponseTooLarge {
+				t.Errorf("error = %v, want code %s", err, ErrCodeResponseTooLarge)
+			}
+		})
+	}
+}
+
+func TestMaxBytesBody(t *testing.T) {
+	t.Run("exactly max bytes", func(t *testing.T) {
+		b := &maxBytesBody{body: io.NopCloser(strings.NewReader("12345")), remaining: 5, limit: 5}
+		got, err := io.ReadAll(b)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(got) != "12345" {
+			t.Errorf("got %q, want %q", got, "12345")
+		}
+	})
+
+	t.Run("one byte over", func(t *testing.T) {
+		b := &maxBytesBody{body: io.NopCloser(strings.NewReader("123456")), remaining: 5, limit: 5}
+		if _, err := io.ReadAll(b); !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("error = %v, want ErrResponseTooLarge", err)
+		}
+	})
+}
+
+func TestMaxResponseBytes_ReportDownloadUnlimited(t *testing.T) {
+	pdf := bytes.Repeat([]byte("%"), 4096)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(pdf)
+	}), WithMaxResponseBytes(1024))
+
+	var buf bytes.Buffer
+	n, err := c.Reports.Download(context.Background(), "report-1", &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != int64(len(pdf)) {
+		t.Errorf("n = %d, want %d", n, len(pdf))
+	}
+}
@@ -0,0 +1,92 @@
+package xbow
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// HeaderRequestID is the response header carrying the server's id for a
+// request. Quote it when reporting problems to XBOW.
+const HeaderRequestID = "X-Request-Id"
+
+// redactedHeaders lists request headers whose values are never logged.
+var redactedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// WithLogger logs every HTTP attempt made by the client at debug level,
+// including each retry. Records carry the method, path, status, duration,
+// request id and request headers, with credentials redacted. Errors from the
+// transport are logged at warn level.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.logger = l
+	}
+}
+
+// WithTransportLogger adds request logging to the transport stack.
+// It behaves like WithLogger on the client.
+func WithTransportLogger(l *slog.Logger) TransportOption {
+	return func(c *transportConfig) {
+		c.logger = l
+	}
+}
+
+// loggingTransport logs each request passed to base.
+type loggingTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	ctx := req.Context()
+	if !t.logger.Enabled(ctx, level) {
+		return resp, err
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if id := resp.Header.Get(HeaderRequestID); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+	}
+	attrs = append(attrs, logHeaders(req.Header))
+	t.logger.LogAttrs(ctx, level, "xbow request", attrs...)
+
+	return resp, err
+}
+
+// logHeaders returns h as a "headers" group, sorted by name, with the
+// values of redactedHeaders replaced.
+func logHeaders(h http.Header) slog.Attr {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		value := h.Get(name)
+		if slices.Contains(redactedHeaders, http.CanonicalHeaderKey(name)) {
+			value = "REDACTED"
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group("headers", attrs...)
+}
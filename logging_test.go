@@ -0,0 +1,141 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// captureHandler is a slog.Handler that records every record it handles.
+type captureHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler      { return h }
+
+// recordAttrs flattens r's attributes into a map keyed by dotted path.
+func recordAttrs(r slog.Record) map[string]string {
+	attrs := map[string]string{}
+	var add func(prefix string, a slog.Attr)
+	add = func(prefix string, a slog.Attr) {
+		if a.Value.Kind() == slog.KindGroup {
+			for _, ga := range a.Value.Group() {
+				add(prefix+a.Key+".", ga)
+			}
+			return
+		}
+		attrs[prefix+a.Key] = a.Value.String()
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		add("", a)
+		return true
+	})
+	return attrs
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Run("logs each attempt without credentials", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(HeaderRequestID, "req-42")
+			flakyHandler(1, `{"markdown":"ok"}`, &calls).ServeHTTP(w, r)
+		})
+		capture := &captureHandler{}
+		client := newTestClient(t, handler,
+			WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+			WithLogger(slog.New(capture)),
+		)
+
+		if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(capture.records) != 2 {
+			t.Fatalf("got %d records, want one per attempt (2)", len(capture.records))
+		}
+		for i, wantStatus := range []string{"503", "200"} {
+			r := capture.records[i]
+			if r.Level != slog.LevelDebug {
+				t.Errorf("records[%d].Level = %v, want DEBUG", i, r.Level)
+			}
+			attrs := recordAttrs(r)
+			if attrs["method"] != http.MethodGet {
+				t.Errorf("records[%d] method = %q, want GET", i, attrs["method"])
+			}
+			if attrs["path"] != "/api/v1/reports/report-1/summary" {
+				t.Errorf("records[%d] path = %q", i, attrs["path"])
+			}
+			if attrs["status"] != wantStatus {
+				t.Errorf("records[%d] status = %q, want %s", i, attrs["status"], wantStatus)
+			}
+			if attrs["request_id"] != "req-42" {
+				t.Errorf("records[%d] request_id = %q, want req-42", i, attrs["request_id"])
+			}
+			if _, ok := attrs["duration"]; !ok {
+				t.Errorf("records[%d] has no duration", i)
+			}
+			if attrs["headers.Authorization"] != "REDACTED" {
+				t.Errorf("records[%d] Authorization = %q, want REDACTED", i, attrs["headers.Authorization"])
+			}
+			for k, v := range attrs {
+				if strings.Contains(v, "test-org-key") {
+					t.Errorf("records[%d] %s leaks the API key: %q", i, k, v)
+				}
+			}
+		}
+	})
+
+	t.Run("logs transport errors at warn", func(t *testing.T) {
+		capture := &captureHandler{}
+		rt := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}), WithTransportLogger(slog.New(capture)))
+
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com/api/v1/x", nil)
+		if _, err := rt.RoundTrip(req); err == nil {
+			t.Fatal("expected error")
+		}
+		if len(capture.records) != 1 {
+			t.Fatalf("got %d records, want 1", len(capture.records))
+		}
+		if r := capture.records[0]; r.Level != slog.LevelWarn {
+			t.Errorf("Level = %v, want WARN", r.Level)
+		}
+		if got := recordAttrs(capture.records[0])["error"]; got != "connection refused" {
+			t.Errorf("error = %q, want 'connection refused'", got)
+		}
+	})
+
+	t.Run("skips work when debug is disabled", func(t *testing.T) {
+		var buf strings.Builder
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+		}), WithLogger(logger))
+
+		if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("logged %q, want nothing", buf.String())
+		}
+	})
+}
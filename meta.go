@@ -3,6 +3,7 @@ package xbow
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/rsclarke/xbow/internal/api"
 )
@@ -15,6 +16,8 @@ type MetaService struct {
 // WebhookSigningKey represents a public key used to verify webhook signatures.
 type WebhookSigningKey struct {
 	// PublicKey is a Base64-encoded Ed25519 public key in SPKI format.
+	// NewWebhookVerifier also accepts a PEM "PUBLIC KEY" block or a
+	// base64-encoded raw 32-byte key here.
 	PublicKey string `json:"publicKey"`
 }
 
@@ -49,14 +52,47 @@ func (s *MetaService) GetWebhookSigningKeys(ctx context.Context) ([]WebhookSigni
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1MetaWebhooksSigningKeys(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return webhookSigningKeysFromResponse(resp), nil
 }
 
+// GetServerTime estimates the offset of the local clock from the server's,
+// to diagnose webhook verification failures caused by clock skew. It makes
+// one authenticated request, reads the server time from the response's Date
+// header, and compares it with the local time halfway through the request.
+// It returns the server time and the offset, which is positive when the
+// local clock is behind. The Date header has one-second resolution, so the
+// offset is accurate to about a second plus network jitter.
+//
+// Example:
+//
+//	_, offset, err := client.Meta.GetServerTime(ctx)
+//	if err == nil && offset.Abs() > time.Minute {
+//	    log.Printf("local clock is off by %v", offset)
+//	}
+func (s *MetaService) GetServerTime(ctx context.Context) (time.Time, time.Duration, error) {
+	ctx, meta := WithResponseMeta(ctx)
+	sent := time.Now()
+	if _, err := s.GetWebhookSigningKeys(ctx); err != nil {
+		return time.Time{}, 0, err
+	}
+	received := time.Now()
+
+	if meta.Date.IsZero() {
+		return time.Time{}, 0, &Error{Code: "ERR_NO_SERVER_TIME", Message: "response has no Date header"}
+	}
+	// Date is truncated to the second, so it is on average half a second
+	// behind the server's clock.
+	server := meta.Date.Add(500 * time.Millisecond)
+	local := sent.Add(received.Sub(sent) / 2)
+	return meta.Date, server.Sub(local).Round(time.Millisecond), nil
+}
+
 // webhookSigningKeysFromResponse converts the generated response to domain types.
 func webhookSigningKeysFromResponse(r *api.GetAPIV1MetaWebhooksSigningKeysResponse) []WebhookSigningKey {
 	if r == nil {
@@ -1,7 +1,11 @@
 package xbow
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/rsclarke/xbow/internal/api"
 )
@@ -70,3 +74,38 @@ func TestMetaServiceInitialized(t *testing.T) {
 		t.Error("client.Meta is nil, expected initialized MetaService")
 	}
 }
+
+func TestGetServerTime(t *testing.T) {
+	t.Run("offset from Date header", func(t *testing.T) {
+		serverNow := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Date", serverNow.Format(http.TimeFormat))
+			_, _ = w.Write([]byte(`[{"publicKey":"a2V5"}]`))
+		}))
+
+		got, offset, err := client.Meta.GetServerTime(context.Background())
+		if err != nil {
+			t.Fatalf("GetServerTime() error = %v", err)
+		}
+		if !got.Equal(serverNow) {
+			t.Errorf("server time = %v, want %v", got, serverNow)
+		}
+		if d := (offset - time.Hour).Abs(); d > 2*time.Second {
+			t.Errorf("offset = %v, want about 1h", offset)
+		}
+	})
+
+	t.Run("no Date header", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header()["Date"] = nil
+			_, _ = w.Write([]byte(`[]`))
+		}))
+
+		var apiErr *Error
+		if _, _, err := client.Meta.GetServerTime(context.Background()); !errors.As(err, &apiErr) || apiErr.Code != "ERR_NO_SERVER_TIME" {
+			t.Errorf("error = %v, want ERR_NO_SERVER_TIME", err)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package xbow
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Metrics receives one observation per API call, for export to a metrics
+// system such as Prometheus without the SDK depending on it.
+type Metrics interface {
+	// ObserveRequest is called when a call completes. op identifies the
+	// endpoint by method and route template, such as
+	// "GET /api/v1/assets/{assetId}", so it is safe to use as a label.
+	// status is the final HTTP status, or 0 when err is set. dur spans the
+	// whole call, including retries and rate-limit waits, up to the
+	// response headers.
+	ObserveRequest(op string, status int, dur time.Duration, err error)
+}
+
+// NopMetrics discards all observations. It is the default.
+type NopMetrics struct{}
+
+// ObserveRequest does nothing.
+func (NopMetrics) ObserveRequest(string, int, time.Duration, error) {}
+
+// WithMetrics reports every API call to m, once per call however many
+// attempts it takes. By default nothing is reported.
+//
+//	type promMetrics struct {
+//	    requests *prometheus.CounterVec   // labels: op, class
+//	    latency  *prometheus.HistogramVec // labels: op
+//	}
+//
+//	func (m promMetrics) ObserveRequest(op string, status int, dur time.Duration, err error) {
+//	    class := "error"
+//	    if err == nil {
+//	        class = strconv.Itoa(status/100) + "xx"
+//	    }
+//	    m.requests.WithLabelValues(op, class).Inc()
+//	    m.latency.WithLabelValues(op).Observe(dur.Seconds())
+//	}
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.metrics = m
+	}
+}
+
+// WithTransportMetrics adds metrics reporting to the transport stack.
+// It behaves like WithMetrics on the client.
+func WithTransportMetrics(m Metrics) TransportOption {
+	return func(c *transportConfig) {
+		c.metrics = m
+	}
+}
+
+// apiRoutes lists the path templates of the API, used to name operations
+// without putting resource ids into metric labels.
+var apiRoutes = []string{
+	"/api/v1/assessments/{assessmentId}",
+	"/api/v1/assessments/{assessmentId}/cancel",
+	"/api/v1/assessments/{assessmentId}/pause",
+	"/api/v1/assessments/{assessmentId}/resume",
+	"/api/v1/assets/{assetId}",
+	"/api/v1/assets/{assetId}/assessments",
+	"/api/v1/assets/{assetId}/findings",
+	"/api/v1/assets/{assetId}/reports",
+	"/api/v1/findings/{findingId}",
+	"/api/v1/findings/{findingId}/verify-fix",
+	"/api/v1/integrations/{integrationId}/organizations",
+	"/api/v1/keys/{keyId}",
+	"/api/v1/meta/openapi.json",
+	"/api/v1/meta/webhooks-signing-keys",
+	"/api/v1/organizations/{organizationId}",
+	"/api/v1/organizations/{organizationId}/assets",
+	"/api/v1/organizations/{organizationId}/keys",
+	"/api/v1/organizations/{organizationId}/webhooks",
+	"/api/v1/reports/{reportId}",
+	"/api/v1/reports/{reportId}/summary",
+	"/api/v1/webhooks/{webhookId}",
+	"/api/v1/webhooks/{webhookId}/deliveries",
+	"/api/v1/webhooks/{webhookId}/ping",
+}
+
+// operationName returns the method and route template matching path, such
+// as "GET /api/v1/assets/{assetId}". Any prefix before /api/ in path, from a
+// base URL with a path, is ignored. Unknown paths are named by method
+// alone, as in "GET unknown".
+func operationName(method, path string) string {
+	if i := strings.Index(path, "/api/"); i >= 0 {
+		path = path[i:]
+	}
+	segments := strings.Split(path, "/")
+	for _, route := range apiRoutes {
+		if routeMatches(strings.Split(route, "/"), segments) {
+			return method + " " + route
+		}
+	}
+	return method + " unknown"
+}
+
+func routeMatches(route, segments []string) bool {
+	if len(route) != len(segments) {
+		return false
+	}
+	for i, r := range route {
+		if strings.HasPrefix(r, "{") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if r != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// metricsTransport reports each request passed to base.
+type metricsTransport struct {
+	base    http.RoundTripper
+	metrics Metrics
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	t.metrics.ObserveRequest(operationName(req.Method, req.URL.Path), status, time.Since(start), err)
+	return resp, err
+}
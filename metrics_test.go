@@ -0,0 +1,151 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// observation is one call to Metrics.ObserveRequest.
+type observation struct {
+	op     string
+	status int
+	dur    time.Duration
+	err    error
+}
+
+// recordingMetrics is a minimal Metrics adapter that keeps every
+// observation, standing in for a Prometheus or OpenTelemetry exporter.
+type recordingMetrics struct {
+	mu  sync.Mutex
+	obs []observation
+}
+
+func (m *recordingMetrics) ObserveRequest(op string, status int, dur time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.obs = append(m.obs, observation{op: op, status: status, dur: dur, err: err})
+}
+
+func (m *recordingMetrics) observations() []observation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.obs)
+}
+
+func TestWithMetrics(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		m := &recordingMetrics{}
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(testAssetJSON))
+		}), WithMetrics(m))
+
+		if _, err := client.Assets.Get(context.Background(), "asset-123"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		obs := m.observations()
+		if len(obs) != 1 {
+			t.Fatalf("observations = %d, want 1", len(obs))
+		}
+		if obs[0].op != "GET /api/v1/assets/{assetId}" {
+			t.Errorf("op = %q", obs[0].op)
+		}
+		if obs[0].status != http.StatusOK || obs[0].err != nil {
+			t.Errorf("status = %d, err = %v, want 200 and nil", obs[0].status, obs[0].err)
+		}
+		if obs[0].dur <= 0 {
+			t.Errorf("dur = %v, want positive", obs[0].dur)
+		}
+	})
+
+	t.Run("error status once across retries", func(t *testing.T) {
+		m := &recordingMetrics{}
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":"ERR_UNAVAILABLE","error":"Service Unavailable","message":"down"}`))
+		}),
+			WithMetrics(m),
+			WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		)
+
+		if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err == nil {
+			t.Fatal("expected error")
+		}
+
+		obs := m.observations()
+		if len(obs) != 1 {
+			t.Fatalf("observations = %d, want 1 per call", len(obs))
+		}
+		if obs[0].op != "GET /api/v1/reports/{reportId}/summary" || obs[0].status != http.StatusServiceUnavailable {
+			t.Errorf("got op = %q, status = %d", obs[0].op, obs[0].status)
+		}
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		m := &recordingMetrics{}
+		client, err := NewClient(
+			WithOrganizationKey("key"),
+			WithMetrics(m),
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			})}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+
+		if _, err := client.Webhooks.Get(context.Background(), "wh-1"); err == nil {
+			t.Fatal("expected error")
+		}
+
+		obs := m.observations()
+		if len(obs) != 1 {
+			t.Fatalf("observations = %d, want 1", len(obs))
+		}
+		if obs[0].op != "GET /api/v1/webhooks/{webhookId}" || obs[0].status != 0 || obs[0].err == nil {
+			t.Errorf("got op = %q, status = %d, err = %v", obs[0].op, obs[0].status, obs[0].err)
+		}
+	})
+}
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/api/v1/assets/asset-1", "GET /api/v1/assets/{assetId}"},
+		{"POST", "/api/v1/assets/asset-1/assessments", "POST /api/v1/assets/{assetId}/assessments"},
+		{"POST", "/api/v1/assessments/a-1/cancel", "POST /api/v1/assessments/{assessmentId}/cancel"},
+		{"GET", "/api/v1/meta/openapi.json", "GET /api/v1/meta/openapi.json"},
+		{"GET", "/proxy/api/v1/findings/f-1", "GET /api/v1/findings/{findingId}"},
+		{"GET", "/api/v1/assets/", "GET unknown"},
+		{"GET", "/api/v2/other", "GET unknown"},
+	}
+	for _, tt := range tests {
+		if got := operationName(tt.method, tt.path); got != tt.want {
+			t.Errorf("operationName(%q, %q) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestAPIRoutesCoverGeneratedClient guards apiRoutes against drifting from
+// the generated client when the spec is updated.
+func TestAPIRoutesCoverGeneratedClient(t *testing.T) {
+	src, err := os.ReadFile("internal/api/xbow.gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range regexp.MustCompile(`ExecuteRequest\(ctx, req, "([^"]+)"\)`).FindAllSubmatch(src, -1) {
+		if route := string(m[1]); !slices.Contains(apiRoutes, route) {
+			t.Errorf("apiRoutes is missing %q", route)
+		}
+	}
+}
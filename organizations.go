@@ -30,9 +30,10 @@ func (s *OrganizationsService) Get(ctx context.Context, id string) (*Organizatio
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1OrganizationsOrganizationID(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return organizationFromGetResponse(resp), nil
@@ -75,9 +76,10 @@ func (s *OrganizationsService) Update(ctx context.Context, id string, req *Updat
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PutAPIV1OrganizationsOrganizationID(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return organizationFromPutResponse(resp), nil
@@ -134,9 +136,10 @@ func (s *OrganizationsService) Create(ctx context.Context, integrationID string,
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1IntegrationsIntegrationIDOrganizations(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return organizationFromCreateResponse(resp), nil
@@ -169,9 +172,10 @@ func (s *OrganizationsService) ListByIntegration(ctx context.Context, integratio
 		}
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1IntegrationsIntegrationIDOrganizations(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return organizationsPageFromResponse(resp), nil
@@ -215,16 +219,17 @@ func (s *OrganizationsService) CreateKey(ctx context.Context, organizationID str
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1OrganizationsOrganizationIDKeys(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return apiKeyFromResponse(resp), nil
 }
 
-// RevokeKey revokes an organization API key.
-// This endpoint requires an integration key.
+// RevokeKey revokes an organization API key. Any 2xx response is success,
+// whatever its body. This endpoint requires an integration key.
 func (s *OrganizationsService) RevokeKey(ctx context.Context, keyID string) error {
 	auth, err := s.client.integrationAuthEditor()
 	if err != nil {
@@ -240,12 +245,9 @@ func (s *OrganizationsService) RevokeKey(ctx context.Context, keyID string) erro
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	_, err = s.client.raw.DeleteAPIV1KeysKeyID(ctx, opts, auth)
-	if err != nil {
-		return wrapError(err)
-	}
-
-	return nil
+	return rid.noContentError(err)
 }
 
 // Conversion functions from generated types to domain types
@@ -2,7 +2,9 @@ package xbow
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -350,3 +352,33 @@ func TestOrganizationStateConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestOrganizationAPIKeyListItemOmitsKey(t *testing.T) {
+	expires := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	key := OrganizationAPIKey{
+		ID:        "key-123",
+		Name:      "CI",
+		Key:       "secret-value",
+		ExpiresAt: &expires,
+	}
+
+	item := key.ListItem()
+	if item.ID != key.ID || item.Name != key.Name || item.ExpiresAt != key.ExpiresAt {
+		t.Errorf("ListItem() = %+v, want fields copied from %+v", item, key)
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if _, ok := fields["key"]; ok {
+		t.Errorf("list item JSON has key field: %s", data)
+	}
+	if strings.Contains(string(data), "secret-value") {
+		t.Errorf("list item JSON leaks secret: %s", data)
+	}
+}
@@ -2,14 +2,79 @@ package xbow
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"iter"
+	"sync"
 )
 
 // ListOptions specifies pagination options for list operations.
 type ListOptions struct {
 	Limit int
+
+	// After is the cursor to start listing after. A cursor from
+	// Page.Cursor resumes exactly with the item following that page.
 	After string
+
+	// MaxItems caps the total number of items yielded by an All* iterator.
+	// Zero means no cap. List methods, which fetch a single page, ignore it.
+	MaxItems int
+
+	// Prefetch lets an All* iterator fetch up to this many pages ahead in a
+	// background goroutine while the caller processes the current one.
+	// Items are still yielded in order. The goroutine stops when the loop
+	// ends, including on break. Zero fetches each page only when it is
+	// needed. List methods ignore it.
+	Prefetch int
+}
+
+// ListOption sets a field of ListOptions.
+type ListOption func(*ListOptions)
+
+// WithLimit sets the page size.
+func WithLimit(n int) ListOption {
+	return func(o *ListOptions) {
+		o.Limit = n
+	}
+}
+
+// WithAfter sets the cursor to start listing after.
+func WithAfter(cursor string) ListOption {
+	return func(o *ListOptions) {
+		o.After = cursor
+	}
+}
+
+// WithMaxItems caps the total number of items yielded by an All* iterator.
+func WithMaxItems(n int) ListOption {
+	return func(o *ListOptions) {
+		o.MaxItems = n
+	}
+}
+
+// WithPrefetch lets an All* iterator fetch up to n pages ahead of the
+// caller. See ListOptions.Prefetch.
+func WithPrefetch(n int) ListOption {
+	return func(o *ListOptions) {
+		o.Prefetch = n
+	}
+}
+
+// NewListOptions builds ListOptions from the given options.
+//
+// Example:
+//
+//	for asset, err := range client.Assets.AllByOrganization(ctx, orgID,
+//	    xbow.NewListOptions(xbow.WithLimit(50), xbow.WithMaxItems(200))) {
+//	    ...
+//	}
+func NewListOptions(opts ...ListOption) *ListOptions {
+	o := &ListOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
 }
 
 // PageInfo contains pagination metadata.
@@ -24,22 +89,76 @@ type Page[T any] struct {
 	PageInfo PageInfo
 }
 
+// Cursor returns the cursor for the page after p and whether there is one.
+// Passing it back as ListOptions.After resumes the listing with the item
+// following the last item of p, so a batch job can save it once a page is
+// processed and carry on from there after a restart. See Resumable to also
+// keep track of position within a page.
+func (p *Page[T]) Cursor() (string, bool) {
+	if !p.PageInfo.HasMore || p.PageInfo.NextCursor == nil || *p.PageInfo.NextCursor == "" {
+		return "", false
+	}
+	return *p.PageInfo.NextCursor, true
+}
+
+// Filter returns a copy of p holding only the items for which pred returns
+// true. PageInfo is kept, so Cursor still continues the listing after the
+// last item of p; a filtered page may be empty while HasMore is true.
+func (p *Page[T]) Filter(pred func(T) bool) *Page[T] {
+	var items []T
+	for _, item := range p.Items {
+		if pred(item) {
+			items = append(items, item)
+		}
+	}
+	return &Page[T]{Items: items, PageInfo: p.PageInfo}
+}
+
 // listFunc is a function that fetches a page of items.
 type listFunc[T any] func(ctx context.Context, opts *ListOptions) (*Page[T], error)
 
 // paginate creates an iterator that automatically handles pagination.
 func paginate[T any](ctx context.Context, opts *ListOptions, fetch listFunc[T]) iter.Seq2[T, error] {
+	maxItems, prefetch := 0, 0
+	if opts != nil {
+		maxItems, prefetch = opts.MaxItems, opts.Prefetch
+	}
+
+	pageSeq := pages(ctx, opts, fetch)
+	if prefetch > 0 {
+		pageSeq = prefetchPages(ctx, prefetch, maxItems, func(ctx context.Context) iter.Seq2[*Page[T], error] {
+			return pages(ctx, opts, fetch)
+		})
+	}
+
 	return func(yield func(T, error) bool) {
 		var zero T
-
-		cursor := ""
-		if opts != nil {
-			cursor = opts.After
+		yielded := 0
+		for page, err := range pageSeq {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+				yielded++
+				if maxItems > 0 && yielded >= maxItems {
+					return
+				}
+			}
 		}
+	}
+}
 
-		limit := 0
+// pages iterates over the pages of a listing, following cursors until the
+// server reports no more. It stops after the first error.
+func pages[T any](ctx context.Context, opts *ListOptions, fetch listFunc[T]) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		cursor, limit := "", 0
 		if opts != nil {
-			limit = opts.Limit
+			cursor, limit = opts.After, opts.Limit
 		}
 
 		for {
@@ -50,14 +169,11 @@ func paginate[T any](ctx context.Context, opts *ListOptions, fetch listFunc[T])
 
 			page, err := fetch(ctx, pageOpts)
 			if err != nil {
-				yield(zero, err)
+				yield(nil, err)
 				return
 			}
-
-			for _, item := range page.Items {
-				if !yield(item, nil) {
-					return
-				}
+			if !yield(page, nil) {
+				return
 			}
 
 			if !page.PageInfo.HasMore {
@@ -65,11 +181,11 @@ func paginate[T any](ctx context.Context, opts *ListOptions, fetch listFunc[T])
 			}
 
 			if page.PageInfo.NextCursor == nil || *page.PageInfo.NextCursor == "" {
-				yield(zero, fmt.Errorf("xbow: server indicated more pages but returned no cursor"))
+				yield(nil, fmt.Errorf("xbow: server indicated more pages but returned no cursor"))
 				return
 			}
 			if *page.PageInfo.NextCursor == cursor {
-				yield(zero, fmt.Errorf("xbow: server returned same cursor, stopping to prevent infinite loop"))
+				yield(nil, fmt.Errorf("xbow: server returned same cursor, stopping to prevent infinite loop"))
 				return
 			}
 			cursor = *page.PageInfo.NextCursor
@@ -77,6 +193,181 @@ func paginate[T any](ctx context.Context, opts *ListOptions, fetch listFunc[T])
 	}
 }
 
+// pageResult carries one page, or the error that ended the listing, from
+// the prefetching goroutine.
+type pageResult[T any] struct {
+	page *Page[T]
+	err  error
+}
+
+// prefetchPages runs the page iterator from newSeq in a goroutine, keeping
+// up to n pages fetched ahead of the consumer. The goroutine stops fetching
+// once maxItems items (if positive) have been fetched. When the consumer
+// stops early, the goroutine's context is cancelled, aborting any request
+// in flight, and prefetchPages waits for it to exit before returning.
+func prefetchPages[T any](ctx context.Context, n, maxItems int, newSeq func(context.Context) iter.Seq2[*Page[T], error]) iter.Seq2[*Page[T], error] {
+	return func(yield func(*Page[T], error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		// The goroutine holds one page while it waits to send, so the
+		// channel buffers n-1 for n pages ahead in total.
+		results := make(chan pageResult[T], n-1)
+		done := make(chan struct{})
+		defer func() {
+			cancel()
+			<-done
+		}()
+
+		// stopErr records why the goroutine gave up sending. It is read
+		// only after results is closed.
+		var stopErr error
+		go func() {
+			defer close(done)
+			defer close(results)
+			fetched := 0
+			for page, err := range newSeq(ctx) {
+				select {
+				case results <- pageResult[T]{page: page, err: err}:
+				case <-ctx.Done():
+					stopErr = ctx.Err()
+					return
+				}
+				if err != nil {
+					return
+				}
+				fetched += len(page.Items)
+				if maxItems > 0 && fetched >= maxItems {
+					return
+				}
+			}
+		}()
+
+		for r := range results {
+			if !yield(r.page, r.err) || r.err != nil {
+				return
+			}
+		}
+		// The consumer never stopped, so only the caller's context can
+		// have ended the goroutine early.
+		if stopErr != nil {
+			yield(nil, stopErr)
+		}
+	}
+}
+
+// Resumable iterates over a listing while keeping a checkpoint of the items
+// consumed so far, so a job that stops part way, even by crashing, can save
+// the checkpoint and later resume without processing any item twice.
+//
+// Example:
+//
+//	r, err := xbow.NewResumable(func(ctx context.Context, opts *xbow.ListOptions) (*xbow.Page[xbow.FindingListItem], error) {
+//	    return client.Findings.ListByAsset(ctx, assetID, opts)
+//	}, nil, loadCheckpoint())
+//	if err != nil {
+//	    return err
+//	}
+//	for f, err := range r.All(ctx) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(f)
+//	    saveCheckpoint(r.Checkpoint())
+//	}
+type Resumable[T any] struct {
+	fetch listFunc[T]
+
+	mu  sync.Mutex
+	pos resumePosition
+}
+
+// resumePosition is the state encoded in a Resumable checkpoint: the cursor
+// the current page was fetched with, how many of its items have been
+// consumed, and the page size, which must not change for skip to stay
+// accurate.
+type resumePosition struct {
+	After string `json:"a,omitempty"`
+	Skip  int    `json:"s,omitempty"`
+	Limit int    `json:"l,omitempty"`
+}
+
+// NewResumable returns a Resumable listing the pages returned by fetch,
+// typically a closure over a List method. When checkpoint is empty the
+// listing starts from opts.After with page size opts.Limit; otherwise it
+// resumes from checkpoint, a value returned by Checkpoint, and opts is
+// ignored. MaxItems and Prefetch are not supported. An invalid checkpoint
+// is an *Error with code ERR_INVALID_REQUEST.
+func NewResumable[T any](fetch func(ctx context.Context, opts *ListOptions) (*Page[T], error), opts *ListOptions, checkpoint string) (*Resumable[T], error) {
+	r := &Resumable[T]{fetch: fetch}
+	if checkpoint == "" {
+		if opts != nil {
+			r.pos = resumePosition{After: opts.After, Limit: opts.Limit}
+		}
+		return r, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(checkpoint)
+	if err == nil {
+		err = json.Unmarshal(data, &r.pos)
+	}
+	if err != nil || r.pos.Skip < 0 {
+		return nil, &Error{Code: "ERR_INVALID_REQUEST", Message: "invalid checkpoint"}
+	}
+	return r, nil
+}
+
+// All yields the items after the current checkpoint. An item counts as
+// consumed once the loop body has finished with it, whether it continues
+// or breaks, so the checkpoint never covers an item the body was still
+// working on. Calling All again continues from the checkpoint.
+func (r *Resumable[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		r.mu.Lock()
+		start := r.pos
+		r.mu.Unlock()
+
+		skip := start.Skip
+		cursor := start.After
+		for page, err := range pages(ctx, &ListOptions{After: start.After, Limit: start.Limit}, r.fetch) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			items := page.Items[min(skip, len(page.Items)):]
+			consumed := len(page.Items) - len(items)
+			skip = 0
+			for _, item := range items {
+				more := yield(item, nil)
+				consumed++
+				r.setPosition(cursor, consumed, start.Limit)
+				if !more {
+					return
+				}
+			}
+			if next, ok := page.Cursor(); ok {
+				cursor = next
+				r.setPosition(cursor, 0, start.Limit)
+			}
+		}
+	}
+}
+
+func (r *Resumable[T]) setPosition(after string, skip, limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pos = resumePosition{After: after, Skip: skip, Limit: limit}
+}
+
+// Checkpoint returns an opaque token recording the items consumed so far.
+// Pass it to NewResumable to resume after them. It is safe to call while
+// All is running, for example from a signal handler.
+func (r *Resumable[T]) Checkpoint() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, _ := json.Marshal(r.pos)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
 // Collect gathers all items from an iterator into a slice.
 func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
 	var items []T
@@ -88,3 +379,60 @@ func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
 	}
 	return items, nil
 }
+
+// CollectN gathers at most n items from an iterator into a slice, stopping
+// the iterator once it has them, so no further pages are fetched. Like
+// Collect, it returns the items gathered so far with the first error.
+func CollectN[T any](seq iter.Seq2[T, error], n int) ([]T, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	items := make([]T, 0, min(n, 64))
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+		if len(items) == n {
+			break
+		}
+	}
+	return items, nil
+}
+
+// First returns the first item of an iterator and stops it. ok is false if
+// the sequence is empty. Use it for existence checks, such as whether an
+// asset has any findings, without fetching more than one page.
+func First[T any](seq iter.Seq2[T, error]) (item T, ok bool, err error) {
+	for item, err := range seq {
+		if err != nil {
+			var zero T
+			return zero, false, err
+		}
+		return item, true, nil
+	}
+	return item, false, nil
+}
+
+// MapSeq returns an iterator yielding f applied to each item of seq. Errors
+// are passed through unchanged, with the zero value of B, and f is not
+// called for them.
+//
+//	names := xbow.MapSeq(client.Assets.AllByOrganization(ctx, orgID, nil),
+//	    func(a xbow.AssetListItem) string { return a.Name })
+func MapSeq[A, B any](seq iter.Seq2[A, error], f func(A) B) iter.Seq2[B, error] {
+	return func(yield func(B, error) bool) {
+		for item, err := range seq {
+			if err != nil {
+				var zero B
+				if !yield(zero, err) {
+					return
+				}
+				continue
+			}
+			if !yield(f(item), nil) {
+				return
+			}
+		}
+	}
+}
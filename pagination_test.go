@@ -3,7 +3,14 @@ package xbow
 import (
 	"context"
 	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func ptr(s string) *string { return &s }
@@ -206,6 +213,353 @@ func TestPaginate(t *testing.T) {
 	})
 }
 
+func TestPaginate_MaxItems(t *testing.T) {
+	pages := []*Page[string]{
+		{Items: []string{"a", "b", "c"}, PageInfo: PageInfo{NextCursor: ptr("cursor1"), HasMore: true}},
+		{Items: []string{"d", "e", "f"}, PageInfo: PageInfo{NextCursor: ptr("cursor2"), HasMore: true}},
+		{Items: []string{"g"}, PageInfo: PageInfo{HasMore: false}},
+	}
+
+	tests := []struct {
+		name      string
+		maxItems  int
+		want      []string
+		wantCalls int
+	}{
+		{"zero is unlimited", 0, []string{"a", "b", "c", "d", "e", "f", "g"}, 3},
+		{"halts mid-page", 2, []string{"a", "b"}, 1},
+		{"halts at page boundary", 3, []string{"a", "b", "c"}, 1},
+		{"halts on later page", 5, []string{"a", "b", "c", "d", "e"}, 2},
+		{"cap above total", 100, []string{"a", "b", "c", "d", "e", "f", "g"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			fetch := func(ctx context.Context, opts *ListOptions) (*Page[string], error) {
+				calls++
+				return pages[calls-1], nil
+			}
+
+			got, err := Collect(paginate(context.Background(), NewListOptions(WithMaxItems(tt.maxItems)), fetch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("fetch called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// cursorFetch serves n pages of size items each, named "p<page>-<item>",
+// linked by cursors. Each fetch first runs before, if set.
+func cursorFetch(n, size int, before func(ctx context.Context, page int) error) listFunc[string] {
+	return func(ctx context.Context, opts *ListOptions) (*Page[string], error) {
+		page := 0
+		if opts.After != "" {
+			page, _ = strconv.Atoi(opts.After)
+		}
+		if before != nil {
+			if err := before(ctx, page); err != nil {
+				return nil, err
+			}
+		}
+		p := &Page[string]{}
+		for i := range size {
+			p.Items = append(p.Items, fmt.Sprintf("p%d-%d", page, i))
+		}
+		if page+1 < n {
+			p.PageInfo = PageInfo{NextCursor: ptr(strconv.Itoa(page + 1)), HasMore: true}
+		}
+		return p, nil
+	}
+}
+
+func TestPaginate_Prefetch(t *testing.T) {
+	t.Run("preserves order", func(t *testing.T) {
+		for _, prefetch := range []int{1, 2, 5} {
+			want, err := Collect(paginate(context.Background(), nil, cursorFetch(10, 3, nil)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			slow := func(context.Context, int) error {
+				time.Sleep(time.Millisecond)
+				return nil
+			}
+			got, err := Collect(paginate(context.Background(), NewListOptions(WithPrefetch(prefetch)), cursorFetch(10, 3, slow)))
+			if err != nil {
+				t.Fatalf("prefetch %d: unexpected error: %v", prefetch, err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("prefetch %d: got %v, want %v", prefetch, got, want)
+			}
+		}
+	})
+
+	t.Run("fetches ahead", func(t *testing.T) {
+		var fetched atomic.Int32
+		fetch := cursorFetch(10, 1, func(context.Context, int) error {
+			fetched.Add(1)
+			return nil
+		})
+		for range paginate(context.Background(), NewListOptions(WithPrefetch(3)), fetch) {
+			// Hold the first page until the goroutine has fetched and
+			// queued three more, then stop.
+			deadline := time.Now().Add(5 * time.Second)
+			for fetched.Load() < 4 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			break
+		}
+		if got := fetched.Load(); got != 4 {
+			t.Errorf("fetched %d pages while holding the first, want 4", got)
+		}
+	})
+
+	t.Run("propagates errors", func(t *testing.T) {
+		boom := errors.New("boom")
+		fetch := cursorFetch(5, 2, func(_ context.Context, page int) error {
+			if page == 2 {
+				return boom
+			}
+			return nil
+		})
+		got, err := Collect(paginate(context.Background(), NewListOptions(WithPrefetch(2)), fetch))
+		if !errors.Is(err, boom) {
+			t.Fatalf("error = %v, want boom", err)
+		}
+		if want := []string{"p0-0", "p0-1", "p1-0", "p1-1"}; !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("early break cancels in-flight fetch", func(t *testing.T) {
+		var cancelled atomic.Bool
+		fetch := cursorFetch(5, 2, func(ctx context.Context, page int) error {
+			if page == 0 {
+				return nil
+			}
+			<-ctx.Done()
+			cancelled.Store(true)
+			return ctx.Err()
+		})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for item, err := range paginate(context.Background(), NewListOptions(WithPrefetch(2)), fetch) {
+				if err != nil || item != "p0-0" {
+					t.Errorf("got %q, %v", item, err)
+				}
+				break
+			}
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("iterator did not return after break")
+		}
+		if !cancelled.Load() {
+			t.Error("in-flight fetch was not cancelled")
+		}
+	})
+
+	t.Run("caller cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		fetch := cursorFetch(5, 2, func(ctx context.Context, _ int) error {
+			return ctx.Err()
+		})
+
+		var err error
+		for _, e := range paginate(ctx, NewListOptions(WithPrefetch(1)), fetch) {
+			cancel()
+			if e != nil {
+				err = e
+			}
+		}
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("max items stops fetching", func(t *testing.T) {
+		var fetched atomic.Int32
+		fetch := cursorFetch(10, 3, func(context.Context, int) error {
+			fetched.Add(1)
+			return nil
+		})
+		got, err := Collect(paginate(context.Background(), NewListOptions(WithPrefetch(5), WithMaxItems(4)), fetch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 4 {
+			t.Errorf("got %d items, want 4", len(got))
+		}
+		if n := fetched.Load(); n != 2 {
+			t.Errorf("fetched %d pages, want 2", n)
+		}
+	})
+}
+
+func TestPageCursor(t *testing.T) {
+	fetch := cursorFetch(3, 2, nil)
+	ctx := context.Background()
+
+	first, err := fetch(ctx, &ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor, ok := first.Cursor()
+	if !ok || cursor != "1" {
+		t.Fatalf("Cursor() = %q, %v, want \"1\", true", cursor, ok)
+	}
+
+	got, err := Collect(paginate(ctx, &ListOptions{After: cursor}, fetch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"p1-0", "p1-1", "p2-0", "p2-1"}; !slices.Equal(got, want) {
+		t.Errorf("resumed items = %v, want %v", got, want)
+	}
+
+	last, err := fetch(ctx, &ListOptions{After: "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor, ok := last.Cursor(); ok {
+		t.Errorf("last page Cursor() = %q, true, want false", cursor)
+	}
+}
+
+func TestPageFilter(t *testing.T) {
+	page := &Page[int]{
+		Items:    []int{1, 2, 3, 4},
+		PageInfo: PageInfo{NextCursor: ptr("next"), HasMore: true},
+	}
+
+	even := page.Filter(func(n int) bool { return n%2 == 0 })
+	if !slices.Equal(even.Items, []int{2, 4}) {
+		t.Errorf("Items = %v, want [2 4]", even.Items)
+	}
+	if cursor, ok := even.Cursor(); !ok || cursor != "next" {
+		t.Errorf("Cursor() = %q, %v, want \"next\", true", cursor, ok)
+	}
+	if !slices.Equal(page.Items, []int{1, 2, 3, 4}) {
+		t.Errorf("original Items = %v, want unchanged", page.Items)
+	}
+
+	none := page.Filter(func(int) bool { return false })
+	if len(none.Items) != 0 || !none.PageInfo.HasMore {
+		t.Errorf("got %v with HasMore %v, want no items and HasMore true", none.Items, none.PageInfo.HasMore)
+	}
+}
+
+func TestResumable(t *testing.T) {
+	ctx := context.Background()
+	all := []string{"p0-0", "p0-1", "p0-2", "p1-0", "p1-1", "p1-2", "p2-0", "p2-1", "p2-2"}
+
+	t.Run("resumes mid-page after break", func(t *testing.T) {
+		r, err := NewResumable(cursorFetch(3, 3, nil), nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for item, err := range r.All(ctx) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, item)
+			if len(got) == 4 {
+				break
+			}
+		}
+
+		var fetched []string
+		resumed, err := NewResumable(cursorFetch(3, 3, func(_ context.Context, page int) error {
+			fetched = append(fetched, strconv.Itoa(page))
+			return nil
+		}), nil, r.Checkpoint())
+		if err != nil {
+			t.Fatalf("NewResumable(checkpoint) error = %v", err)
+		}
+		rest, err := Collect(resumed.All(ctx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got = append(got, rest...); !slices.Equal(got, all) {
+			t.Errorf("items = %v, want each exactly once: %v", got, all)
+		}
+		if want := []string{"1", "2"}; !slices.Equal(fetched, want) {
+			t.Errorf("resumed fetches = %v, want %v", fetched, want)
+		}
+	})
+
+	t.Run("checkpoint survives a failed fetch", func(t *testing.T) {
+		failOn := 2
+		r, err := NewResumable(cursorFetch(3, 3, func(_ context.Context, page int) error {
+			if page == failOn {
+				return errors.New("boom")
+			}
+			return nil
+		}), nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Collect(r.All(ctx))
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		failOn = -1
+		rest, err := Collect(r.All(ctx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got = append(got, rest...); !slices.Equal(got, all) {
+			t.Errorf("items = %v, want %v", got, all)
+		}
+	})
+
+	t.Run("checkpoint at the end yields nothing more", func(t *testing.T) {
+		r, _ := NewResumable(cursorFetch(2, 2, nil), &ListOptions{Limit: 2}, "")
+		if _, err := Collect(r.All(ctx)); err != nil {
+			t.Fatal(err)
+		}
+		resumed, err := NewResumable(cursorFetch(2, 2, nil), nil, r.Checkpoint())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rest, err := Collect(resumed.All(ctx)); err != nil || len(rest) != 0 {
+			t.Errorf("after end: items = %v, err = %v, want none", rest, err)
+		}
+	})
+
+	t.Run("invalid checkpoint", func(t *testing.T) {
+		var apiErr *Error
+		if _, err := NewResumable(cursorFetch(1, 1, nil), nil, "not a checkpoint!"); !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_REQUEST" {
+			t.Errorf("error = %v, want ERR_INVALID_REQUEST", err)
+		}
+	})
+}
+
+func TestNewListOptions(t *testing.T) {
+	got := NewListOptions(WithLimit(50), WithAfter("cursor"), WithMaxItems(200))
+	want := &ListOptions{Limit: 50, After: "cursor", MaxItems: 200}
+	if *got != *want {
+		t.Errorf("NewListOptions() = %+v, want %+v", got, want)
+	}
+
+	if got := NewListOptions(); *got != (ListOptions{}) {
+		t.Errorf("NewListOptions() with no options = %+v, want zero value", got)
+	}
+}
+
 func TestCollect(t *testing.T) {
 	t.Run("collects all items", func(t *testing.T) {
 		seq := func(yield func(int, error) bool) {
@@ -244,3 +598,156 @@ func TestCollect(t *testing.T) {
 		}
 	})
 }
+
+// countingSeq yields 1..n, recording how many items were produced.
+func countingSeq(n int, produced *int) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for i := 1; i <= n; i++ {
+			*produced = i
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestCollectN(t *testing.T) {
+	t.Run("stops after n items", func(t *testing.T) {
+		var produced int
+		got, err := CollectN(countingSeq(10, &produced), 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("got %v, want [1 2 3]", got)
+		}
+		if produced != 3 {
+			t.Errorf("iterator produced %d items, want 3", produced)
+		}
+	})
+
+	t.Run("shorter sequence", func(t *testing.T) {
+		var produced int
+		got, err := CollectN(countingSeq(2, &produced), 5)
+		if err != nil || !slices.Equal(got, []int{1, 2}) {
+			t.Errorf("got %v, %v, want [1 2], nil", got, err)
+		}
+	})
+
+	t.Run("empty sequence", func(t *testing.T) {
+		var produced int
+		got, err := CollectN(countingSeq(0, &produced), 5)
+		if err != nil || len(got) != 0 {
+			t.Errorf("got %v, %v, want no items", got, err)
+		}
+	})
+
+	t.Run("non-positive n reads nothing", func(t *testing.T) {
+		var produced int
+		got, err := CollectN(countingSeq(5, &produced), 0)
+		if err != nil || len(got) != 0 || produced != 0 {
+			t.Errorf("got %v, %v (produced %d), want nothing read", got, err, produced)
+		}
+	})
+
+	t.Run("returns partial results on error", func(t *testing.T) {
+		expectedErr := errors.New("mid-stream error")
+		seq := func(yield func(int, error) bool) {
+			if !yield(1, nil) {
+				return
+			}
+			yield(0, expectedErr)
+		}
+
+		got, err := CollectN(seq, 5)
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("error = %v, want %v", err, expectedErr)
+		}
+		if !slices.Equal(got, []int{1}) {
+			t.Errorf("got %v before error, want [1]", got)
+		}
+	})
+}
+
+func TestFirst(t *testing.T) {
+	t.Run("returns first item and stops", func(t *testing.T) {
+		var produced int
+		got, ok, err := First(countingSeq(10, &produced))
+		if err != nil || !ok || got != 1 {
+			t.Errorf("First() = %v, %v, %v, want 1, true, nil", got, ok, err)
+		}
+		if produced != 1 {
+			t.Errorf("iterator produced %d items, want 1", produced)
+		}
+	})
+
+	t.Run("empty sequence", func(t *testing.T) {
+		var produced int
+		got, ok, err := First(countingSeq(0, &produced))
+		if err != nil || ok || got != 0 {
+			t.Errorf("First() = %v, %v, %v, want 0, false, nil", got, ok, err)
+		}
+	})
+
+	t.Run("propagates error", func(t *testing.T) {
+		expectedErr := errors.New("first page failed")
+		seq := func(yield func(string, error) bool) {
+			yield("", expectedErr)
+		}
+		got, ok, err := First(seq)
+		if !errors.Is(err, expectedErr) || ok || got != "" {
+			t.Errorf("First() = %q, %v, %v, want \"\", false, %v", got, ok, err, expectedErr)
+		}
+	})
+
+	t.Run("fetches one page", func(t *testing.T) {
+		var calls int
+		fetch := func(ctx context.Context, opts *ListOptions) (*Page[string], error) {
+			calls++
+			return &Page[string]{Items: []string{"a", "b"}, PageInfo: PageInfo{NextCursor: ptr("next"), HasMore: true}}, nil
+		}
+		got, ok, err := First(paginate(context.Background(), nil, fetch))
+		if err != nil || !ok || got != "a" {
+			t.Errorf("First() = %q, %v, %v, want a, true, nil", got, ok, err)
+		}
+		if calls != 1 {
+			t.Errorf("fetched %d pages, want 1", calls)
+		}
+	})
+}
+
+func TestMapSeq(t *testing.T) {
+	t.Run("maps items", func(t *testing.T) {
+		got, err := Collect(MapSeq(paginate(context.Background(), &ListOptions{}, cursorFetch(2, 2, nil)), strings.ToUpper))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"P0-0", "P0-1", "P1-0", "P1-1"}; !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("passes errors through", func(t *testing.T) {
+		expectedErr := errors.New("mid-stream error")
+		seq := func(yield func(int, error) bool) {
+			yield(1, nil)
+			yield(2, nil)
+			yield(0, expectedErr)
+		}
+
+		var calls int
+		got, err := Collect(MapSeq(seq, func(n int) int {
+			calls++
+			return n * 10
+		}))
+		if !errors.Is(err, expectedErr) {
+			t.Fatalf("error = %v, want %v", err, expectedErr)
+		}
+		if !slices.Equal(got, []int{10, 20}) {
+			t.Errorf("got %v, want [10 20]", got)
+		}
+		if calls != 2 {
+			t.Errorf("f called %d times, want 2", calls)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package xbow
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// poll calls fetch, waiting interval between calls, and yields each result
+// until the consumer stops or an error is yielded. With backoff set the
+// interval grows by half each time, up to maxPollInterval or the client's
+// poll interval, whichever is larger.
+func poll[T any](ctx context.Context, c *Client, fetch func(context.Context) (T, error), interval time.Duration, backoff bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for {
+			v, err := fetch(ctx)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				yield(zero, ctx.Err())
+				return
+			case <-timer.C:
+			}
+
+			if backoff {
+				interval = min(interval*3/2, max(maxPollInterval, c.pollInterval))
+			}
+		}
+	}
+}
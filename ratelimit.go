@@ -3,8 +3,12 @@ package xbow
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
+// maxRateLimitWait caps the Retry-After wait of WithRespectRateLimit.
+const maxRateLimitWait = time.Minute
+
 // RateLimiter defines the interface for rate limiting API requests.
 // Implementations should block until the request is allowed to proceed,
 // or return an error (e.g., context cancellation).
@@ -18,11 +22,89 @@ type RateLimiter interface {
 type rateLimitTransport struct {
 	base    http.RoundTripper
 	limiter RateLimiter
+	onEvent TraceEventHandler
 }
 
 func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
 	if err := t.limiter.Wait(req.Context()); err != nil {
 		return nil, err
 	}
+	if t.onEvent != nil {
+		t.onEvent(req.Context(), TraceEvent{
+			Name:    TraceEventRateLimitWait,
+			Attempt: 1,
+			Delay:   time.Since(start),
+			Reason:  "rate limiter",
+		})
+	}
 	return t.base.RoundTrip(req)
 }
+
+// WithRespectRateLimit smooths over transient rate limiting without
+// enabling full retries. When a request gets a 429 response with a
+// Retry-After header of at most one minute, the client waits that long and
+// sends the request once more, returning whatever comes back. A 429 without
+// Retry-After, or asking for a longer wait, is returned as is. The wait ends
+// early with the context's error if the context is done. By default 429
+// responses are returned immediately.
+//
+// It sits below WithRetryPolicy, so when both are set a 429 that persists
+// after the polite wait is retried by the policy as usual.
+func WithRespectRateLimit() ClientOption {
+	return func(c *clientConfig) {
+		c.transport.respectRateLimit = true
+	}
+}
+
+// retryAfterTransport resends a request once after the wait a 429
+// response's Retry-After header asks for.
+type retryAfterTransport struct {
+	base    http.RoundTripper
+	maxWait time.Duration
+	onEvent TraceEventHandler
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	wait, ok := retryAfter(resp.Header, time.Now())
+	if !ok || wait > t.maxWait {
+		return resp, nil
+	}
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	if hasBody && req.GetBody == nil {
+		// The body has been consumed and cannot be sent again.
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	if t.onEvent != nil {
+		t.onEvent(req.Context(), TraceEvent{
+			Name:    TraceEventRateLimitWait,
+			Attempt: 2,
+			Delay:   wait,
+			Reason:  "Retry-After",
+		})
+	}
+
+	timer := time.NewTimer(wait)
+	select {
+	case <-req.Context().Done():
+		timer.Stop()
+		return nil, req.Context().Err()
+	case <-timer.C:
+	}
+
+	retry := req.Clone(req.Context())
+	if hasBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return t.base.RoundTrip(retry)
+}
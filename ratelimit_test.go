@@ -0,0 +1,74 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rateLimitedHandler answers the first request with a 429 carrying
+// Retry-After: 1 and later ones with a report summary.
+func rateLimitedHandler(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"code":"ERR_RATE_LIMITED","error":"Too Many Requests","message":"slow down"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+	})
+}
+
+func TestWithRespectRateLimit(t *testing.T) {
+	t.Run("waits for Retry-After", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, rateLimitedHandler(&calls), WithRespectRateLimit())
+
+		start := time.Now()
+		summary, err := client.Reports.GetSummary(context.Background(), "report-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if summary.Markdown != "ok" {
+			t.Errorf("Markdown = %q", summary.Markdown)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("took %v, want at least the Retry-After wait", elapsed)
+		}
+		if got := calls.Load(); got != 2 {
+			t.Errorf("server calls = %d, want 2", got)
+		}
+	})
+
+	t.Run("context cancels wait", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, rateLimitedHandler(&calls), WithRespectRateLimit())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := client.Reports.GetSummary(ctx, "report-1")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("server calls = %d, want 1", got)
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, rateLimitedHandler(&calls))
+
+		if _, err := client.Reports.GetSummary(context.Background(), "report-1"); !IsRateLimited(err) {
+			t.Fatalf("error = %v, want rate limited", err)
+		}
+		if got := calls.Load(); got != 1 {
+			t.Errorf("server calls = %d, want 1", got)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package xbow
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// LogValue implements slog.LogValuer so that logging an Asset never writes
+// its secrets: credential passwords and authenticator URIs are left out,
+// and the values of credential-bearing headers such as Authorization are
+// replaced with "REDACTED". Every other field is logged.
+//
+// Example:
+//
+//	slog.Info("updated asset", "asset", asset)
+func (a Asset) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", a.ID),
+		slog.String("name", a.Name),
+		slog.String("organizationId", a.OrganizationID),
+		slog.String("lifecycle", string(a.Lifecycle)),
+		slog.String("sku", a.Sku),
+	}
+	if a.StartURL != nil {
+		attrs = append(attrs, slog.String("startUrl", *a.StartURL))
+	}
+	if a.MaxRequestsPerSecond != nil {
+		attrs = append(attrs, slog.Int("maxRequestsPerSecond", *a.MaxRequestsPerSecond))
+	}
+	if a.ApprovedTimeWindows != nil {
+		attrs = append(attrs, slog.Any("approvedTimeWindows", a.ApprovedTimeWindows))
+	}
+
+	// Log credentials one by one so each goes through Credential.LogValue;
+	// slog.Any on the slice would hand the handler the raw values.
+	creds := make([]any, 0, len(a.Credentials))
+	for i, c := range a.Credentials {
+		creds = append(creds, slog.Any(strconv.Itoa(i), c))
+	}
+	attrs = append(attrs,
+		slog.Group("credentials", creds...),
+		slog.Any("dnsBoundaryRules", a.DNSBoundaryRules),
+		assetHeadersAttr(a.Headers),
+		slog.Any("httpBoundaryRules", a.HTTPBoundaryRules),
+	)
+
+	if a.Checks != nil {
+		attrs = append(attrs, slog.Any("checks", a.Checks))
+	}
+	if a.ArchiveAt != nil {
+		attrs = append(attrs, slog.Time("archiveAt", *a.ArchiveAt))
+	}
+	attrs = append(attrs,
+		slog.Time("createdAt", a.CreatedAt),
+		slog.Time("updatedAt", a.UpdatedAt),
+	)
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer so that logging a Credential never
+// writes its password or authenticator URI.
+func (c Credential) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", c.ID),
+		slog.String("name", c.Name),
+		slog.String("type", c.Type),
+		slog.String("username", c.Username),
+	}
+	if c.EmailAddress != nil {
+		attrs = append(attrs, slog.String("emailAddress", *c.EmailAddress))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// assetHeadersAttr returns an asset's headers as a "headers" group, sorted
+// by name, with the values of redactedHeaders replaced.
+func assetHeadersAttr(h map[string][]string) slog.Attr {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		if slices.Contains(redactedHeaders, http.CanonicalHeaderKey(name)) {
+			attrs = append(attrs, slog.String(name, "REDACTED"))
+			continue
+		}
+		attrs = append(attrs, slog.Any(name, h[name]))
+	}
+	return slog.Group("headers", attrs...)
+}
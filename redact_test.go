@@ -0,0 +1,127 @@
+package xbow
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSecretAsset() Asset {
+	startURL := "https://example.com"
+	email := "user@example.com"
+	totp := "otpauth://totp/xbow?secret=TOTPSECRET"
+	return Asset{
+		ID:             "asset-123",
+		Name:           "Example",
+		OrganizationID: "org-1",
+		Lifecycle:      AssetLifecycleActive,
+		Sku:            "standard-sku",
+		StartURL:       &startURL,
+		Credentials: []Credential{{
+			ID:               "cred-1",
+			Name:             "admin",
+			Type:             "basic",
+			Username:         "admin",
+			Password:         "hunter2",
+			EmailAddress:     &email,
+			AuthenticatorURI: &totp,
+		}},
+		Headers: map[string][]string{
+			"Authorization": {"Bearer SECRETTOKEN"},
+			"X-Custom":      {"visible"},
+		},
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// groupKeys returns the keys of a resolved group value.
+func groupKeys(v slog.Value) map[string]slog.Value {
+	keys := make(map[string]slog.Value)
+	for _, a := range v.Resolve().Group() {
+		keys[a.Key] = a.Value.Resolve()
+	}
+	return keys
+}
+
+func TestCredentialLogValue(t *testing.T) {
+	c := testSecretAsset().Credentials[0]
+	keys := groupKeys(c.LogValue())
+
+	for _, k := range []string{"password", "authenticatorUri"} {
+		if _, ok := keys[k]; ok {
+			t.Errorf("key %q present, want redacted", k)
+		}
+	}
+	for _, k := range []string{"id", "name", "type", "username", "emailAddress"} {
+		if _, ok := keys[k]; !ok {
+			t.Errorf("key %q missing", k)
+		}
+	}
+}
+
+func TestAssetLogValue(t *testing.T) {
+	a := testSecretAsset()
+	keys := groupKeys(a.LogValue())
+
+	for _, k := range []string{"id", "name", "organizationId", "lifecycle", "sku", "startUrl", "credentials", "headers", "createdAt", "updatedAt"} {
+		if _, ok := keys[k]; !ok {
+			t.Errorf("key %q missing", k)
+		}
+	}
+	if got := keys["name"].String(); got != "Example" {
+		t.Errorf("name = %q, want Example", got)
+	}
+
+	creds := keys["credentials"].Group()
+	if len(creds) != 1 {
+		t.Fatalf("credentials has %d entries, want 1", len(creds))
+	}
+	cred := groupKeys(creds[0].Value)
+	if _, ok := cred["password"]; ok {
+		t.Error("credential password present, want redacted")
+	}
+	if got := cred["username"].String(); got != "admin" {
+		t.Errorf("credential username = %q, want admin", got)
+	}
+
+	headers := groupKeys(keys["headers"])
+	if got := headers["Authorization"].String(); got != "REDACTED" {
+		t.Errorf("Authorization = %q, want REDACTED", got)
+	}
+	if _, ok := headers["X-Custom"]; !ok {
+		t.Error("X-Custom header missing")
+	}
+}
+
+func TestAssetLogValue_Handler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("asset", "a", testSecretAsset())
+
+	out := buf.String()
+	for _, secret := range []string{"hunter2", "TOTPSECRET", "SECRETTOKEN"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output leaks %q:\n%s", secret, out)
+		}
+	}
+
+	var record struct {
+		A struct {
+			ID          string                    `json:"id"`
+			Credentials map[string]map[string]any `json:"credentials"`
+		} `json:"a"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decoding log output: %v", err)
+	}
+	if record.A.ID != "asset-123" {
+		t.Errorf("id = %q, want asset-123", record.A.ID)
+	}
+	if got := record.A.Credentials["0"]["username"]; got != "admin" {
+		t.Errorf("credential username = %v, want admin", got)
+	}
+}
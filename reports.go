@@ -1,12 +1,16 @@
 package xbow
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"html/template"
+	"io"
 	"iter"
 	"net/http"
 
 	"github.com/rsclarke/xbow/internal/api"
+	"github.com/rsclarke/xbow/internal/markdown"
 )
 
 // ReportsService handles report-related API calls.
@@ -15,20 +19,42 @@ type ReportsService struct {
 }
 
 // Get downloads a report as PDF bytes by ID.
-// The returned bytes are the raw PDF file content.
+// The returned bytes are the raw PDF file content. Use Download to stream
+// large reports without holding them in memory.
 func (s *ReportsService) Get(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.Download(ctx, id, &buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Download streams a report's PDF content to w and returns the number of
+// bytes written. API errors are returned before anything is written to w.
+func (s *ReportsService) Download(ctx context.Context, id string, w io.Writer) (int64, error) {
+	if id == "" {
+		return 0, &Error{Code: "ERR_INVALID_PARAM", Message: "report id is required"}
+	}
+
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	path := fmt.Sprintf("/api/v1/reports/%s", id)
-	body, err := s.client.do(ctx, http.MethodGet, path, auth)
+	resp, err := s.client.doStream(withUnlimitedBody(ctx), http.MethodGet, path, nil, auth)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("streaming report: %w", err)
 	}
 
-	return body, nil
+	return n, nil
 }
 
 // GetSummary retrieves the markdown summary of a report by ID.
@@ -47,14 +73,31 @@ func (s *ReportsService) GetSummary(ctx context.Context, id string) (*ReportSumm
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1ReportsReportIDSummary(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return reportSummaryFromResponse(resp), nil
 }
 
+// RenderHTML writes the summary's Markdown to w as HTML that is safe to
+// embed in a page. Report text is untrusted: raw HTML in it is escaped, not
+// passed through, and links and images are kept only for http, https,
+// mailto and relative URLs. A summary too large to render cheaply is written
+// escaped in a <pre> block instead. Markdown is left unchanged.
+func (r *ReportSummary) RenderHTML(w io.Writer) error {
+	_, err := io.WriteString(w, markdown.Render(r.Markdown))
+	return err
+}
+
+// HTML returns the summary rendered as by RenderHTML, typed so that
+// html/template inserts it without escaping it again.
+func (r *ReportSummary) HTML() template.HTML {
+	return template.HTML(markdown.Render(r.Markdown)) //nolint:gosec // escaped by markdown.Render
+}
+
 // ListByAsset returns a page of reports for an asset.
 func (s *ReportsService) ListByAsset(ctx context.Context, assetID string, opts *ListOptions) (*Page[ReportListItem], error) {
 	auth, err := s.client.orgAuthEditor()
@@ -81,9 +124,10 @@ func (s *ReportsService) ListByAsset(ctx context.Context, assetID string, opts *
 		}
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1AssetsAssetIDReports(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return reportsPageFromResponse(resp), nil
@@ -1,6 +1,12 @@
 package xbow
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -141,3 +147,149 @@ func TestReportSummaryEmptyMarkdown(t *testing.T) {
 		t.Errorf("Markdown = %q, want empty string", got.Markdown)
 	}
 }
+
+func TestReportSummaryHTML(t *testing.T) {
+	summary := &ReportSummary{Markdown: "# Findings\n\n" +
+		"Payload:\n\n```html\n<script>alert(1)</script>\n```\n\n" +
+		"Reflected as <script>alert(document.cookie)</script> in [the page](javascript:alert(1)).\n"}
+
+	var buf strings.Builder
+	if err := summary.RenderHTML(&buf); err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	got := buf.String()
+	if string(summary.HTML()) != got {
+		t.Errorf("HTML() and RenderHTML() differ")
+	}
+
+	for _, want := range []string{
+		"<h1>Findings</h1>",
+		"<pre><code class=\"language-html\">&lt;script&gt;alert(1)&lt;/script&gt;\n</code></pre>",
+		"Reflected as &lt;script&gt;alert(document.cookie)&lt;/script&gt; in the page.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<script") || strings.Contains(got, "javascript:") {
+		t.Errorf("HTML was not neutralized:\n%s", got)
+	}
+	if !strings.HasPrefix(summary.Markdown, "# Findings") {
+		t.Errorf("Markdown changed to %q", summary.Markdown)
+	}
+}
+
+func TestReportsGet(t *testing.T) {
+	t.Run("returns body", func(t *testing.T) {
+		var gotPath, gotAuth, gotVersion string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			gotAuth = r.Header.Get("Authorization")
+			gotVersion = r.Header.Get("X-XBOW-API-Version")
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.7"))
+		}))
+
+		body, err := client.Reports.Get(context.Background(), "report-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(body) != "%PDF-1.7" {
+			t.Errorf("body = %q, want '%%PDF-1.7'", body)
+		}
+		if gotPath != "/api/v1/reports/report-1" {
+			t.Errorf("path = %q, want '/api/v1/reports/report-1'", gotPath)
+		}
+		if gotAuth != "Bearer test-org-key" {
+			t.Errorf("Authorization = %q, want 'Bearer test-org-key'", gotAuth)
+		}
+		if gotVersion != APIVersion {
+			t.Errorf("X-XBOW-API-Version = %q, want %q", gotVersion, APIVersion)
+		}
+	})
+
+	t.Run("404 matches ErrNotFound", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"Report not found"}`))
+		}))
+
+		_, err := client.Reports.Get(context.Background(), "missing")
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			t.Errorf("expected *Error with StatusCode 404, got %v", err)
+		}
+	})
+
+	t.Run("rejects empty id", func(t *testing.T) {
+		client, _ := NewClient(WithOrganizationKey("test-key"))
+
+		_, err := client.Reports.Get(context.Background(), "")
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_PARAM" {
+			t.Errorf("expected ERR_INVALID_PARAM, got %v", err)
+		}
+	})
+}
+
+func TestReportsDownload(t *testing.T) {
+	t.Run("streams body to writer", func(t *testing.T) {
+		pdf := bytes.Repeat([]byte("%PDF"), 4096)
+		var gotAuth, gotVersion string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotAuth = r.Header.Get("Authorization")
+			gotVersion = r.Header.Get("X-XBOW-API-Version")
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write(pdf)
+		}))
+
+		var buf bytes.Buffer
+		n, err := client.Reports.Download(context.Background(), "report-1", &buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != int64(len(pdf)) {
+			t.Errorf("n = %d, want %d", n, len(pdf))
+		}
+		if !bytes.Equal(buf.Bytes(), pdf) {
+			t.Error("written bytes do not match response body")
+		}
+		if gotAuth != "Bearer test-org-key" {
+			t.Errorf("Authorization = %q, want 'Bearer test-org-key'", gotAuth)
+		}
+		if gotVersion != APIVersion {
+			t.Errorf("X-XBOW-API-Version = %q, want %q", gotVersion, APIVersion)
+		}
+	})
+
+	t.Run("error writes nothing", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"Report not found"}`))
+		}))
+
+		var buf bytes.Buffer
+		n, err := client.Reports.Download(context.Background(), "missing", &buf)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+		if n != 0 || buf.Len() != 0 {
+			t.Errorf("wrote %d bytes (n=%d), want none", buf.Len(), n)
+		}
+	})
+
+	t.Run("rejects empty id", func(t *testing.T) {
+		client, _ := NewClient(WithOrganizationKey("test-key"))
+
+		_, err := client.Reports.Download(context.Background(), "", io.Discard)
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "ERR_INVALID_PARAM" {
+			t.Errorf("expected ERR_INVALID_PARAM, got %v", err)
+		}
+	})
+}
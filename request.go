@@ -0,0 +1,69 @@
+package xbow
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/doordash-oss/oapi-codegen-dd/v3/pkg/runtime"
+)
+
+// RequestOption customizes a single API call, leaving the client's
+// configuration unchanged.
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	header  http.Header
+	timeout time.Duration
+}
+
+// WithHeader sets a header on a single request, for example an idempotency
+// key or trace baggage. It replaces any value the SDK would otherwise send
+// for the same header.
+func WithHeader(key, value string) RequestOption {
+	return func(c *requestConfig) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Set(key, value)
+	}
+}
+
+// WithTimeout bounds a single request by d. A shorter deadline already on
+// the caller's context still applies.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(c *requestConfig) {
+		c.timeout = d
+	}
+}
+
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	c := &requestConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// context returns ctx bounded by the configured timeout, if any. The cancel
+// function must always be called.
+func (c *requestConfig) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return ctx, func() {}
+}
+
+// editors returns auth followed by an editor applying the configured
+// headers, so that per-request headers take precedence.
+func (c *requestConfig) editors(auth runtime.RequestEditorFn) []runtime.RequestEditorFn {
+	if len(c.header) == 0 {
+		return []runtime.RequestEditorFn{auth}
+	}
+	return []runtime.RequestEditorFn{auth, func(_ context.Context, req *http.Request) error {
+		for k, v := range c.header {
+			req.Header[k] = v
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,73 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRequestOptions(t *testing.T) {
+	t.Run("header reaches the server", func(t *testing.T) {
+		var got http.Header
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(testAssessmentJSON(AssessmentStateRunning)))
+		}))
+
+		_, err := client.Assessments.Get(context.Background(), "assess-123",
+			WithHeader("Idempotency-Key", "abc"),
+			WithHeader("Baggage", "tenant=acme"),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v := got.Get("Idempotency-Key"); v != "abc" {
+			t.Errorf("Idempotency-Key = %q, want 'abc'", v)
+		}
+		if v := got.Get("Baggage"); v != "tenant=acme" {
+			t.Errorf("Baggage = %q, want 'tenant=acme'", v)
+		}
+		if v := got.Get("Authorization"); v != "Bearer test-org-key" {
+			t.Errorf("Authorization = %q, want the org key", v)
+		}
+	})
+
+	t.Run("header applies to Findings.Get", func(t *testing.T) {
+		var got string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("X-Trace")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"finding-1","name":"XSS","severity":"high","state":"open","summary":"","impact":"","mitigations":"","recipe":"","evidence":"","createdAt":"2026-01-01T00:00:00Z","updatedAt":"2026-01-01T00:00:00Z"}`))
+		}))
+
+		if _, err := client.Findings.Get(context.Background(), "finding-1", WithHeader("X-Trace", "t-1")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "t-1" {
+			t.Errorf("X-Trace = %q, want 't-1'", got)
+		}
+	})
+
+	t.Run("timeout cancels a slow request", func(t *testing.T) {
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+
+		start := time.Now()
+		_, err := client.Assessments.Get(context.Background(), "assess-123", WithTimeout(20*time.Millisecond))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error = %v, want context.DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("request took %v, want it cut short by the timeout", elapsed)
+		}
+	})
+}
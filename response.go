@@ -0,0 +1,163 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rate limit response headers.
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimit describes the rate limit state reported by the API.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window.
+	Limit int
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+	// Reset is when the current window ends.
+	Reset time.Time
+}
+
+// ResponseMeta holds metadata about the most recent HTTP response received
+// for a context created by WithResponseMeta. It is overwritten by each
+// response, so for paginated calls it describes the last page. Share a
+// ResponseMeta only across sequential calls.
+type ResponseMeta struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// RateLimit is parsed from the X-RateLimit-* headers, or nil if the
+	// response carried none.
+	RateLimit *RateLimit
+	// RequestID is the server's id for the request, from the X-Request-Id
+	// header, or empty if the response carried none.
+	RequestID string
+	// Date is the server's clock when it sent the response, from the Date
+	// header, or zero if the response carried none. It has one-second
+	// resolution.
+	Date time.Time
+}
+
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a context that records response metadata for any
+// calls made with it, and the ResponseMeta those calls fill in.
+//
+// Example:
+//
+//	ctx, meta := xbow.WithResponseMeta(ctx)
+//	assessment, err := client.Assessments.Get(ctx, id)
+//	if meta.RateLimit != nil {
+//	    fmt.Println("remaining:", meta.RateLimit.Remaining)
+//	}
+func WithResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	meta := &ResponseMeta{}
+	return context.WithValue(ctx, responseMetaKey{}, meta), meta
+}
+
+type requestIDKey struct{}
+
+// requestIDRecorder receives the X-Request-Id of the responses to requests
+// made with the context it was recorded on, so errors from the generated
+// client, which does not expose response headers, can carry it.
+type requestIDRecorder struct {
+	id       string
+	status   int
+	attempts int
+}
+
+// recordRequestID returns a context whose responses' request ids are
+// recorded on the returned recorder.
+func recordRequestID(ctx context.Context) (context.Context, *requestIDRecorder) {
+	r := &requestIDRecorder{}
+	return context.WithValue(ctx, requestIDKey{}, r), r
+}
+
+// wrapError is wrapError with the recorded request id and attempt count set
+// on the *Error.
+func (r *requestIDRecorder) wrapError(err error) error {
+	err = wrapError(err)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	if r.id != "" && apiErr.RequestID == "" {
+		apiErr.RequestID = r.id
+	}
+	if r.attempts > 0 && apiErr.Attempts == 0 {
+		apiErr.Attempts = r.attempts
+	}
+	return err
+}
+
+// recordAttempts notes on the recorder in ctx, if any, that the request was
+// given up on after n attempts.
+func recordAttempts(ctx context.Context, n int) {
+	if r, ok := ctx.Value(requestIDKey{}).(*requestIDRecorder); ok {
+		r.attempts = n
+	}
+}
+
+// noContentError is wrapError for operations whose success response has no
+// body. The generated client accepts only the documented status, usually
+// 204, and decodes any other response as an error body, so a 200 or 202,
+// with or without a body, would otherwise fail confusingly. Any 2xx is
+// success; the body is never decoded.
+func (r *requestIDRecorder) noContentError(err error) error {
+	if err != nil && r.status >= 200 && r.status < 300 {
+		return nil
+	}
+	return r.wrapError(err)
+}
+
+// responseMetaTransport records response metadata on the ResponseMeta and
+// request id recorder stored in the request context, if any.
+type responseMetaTransport struct {
+	base http.RoundTripper
+}
+
+func (t *responseMetaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	requestID := resp.Header.Get(HeaderRequestID)
+	if meta, ok := req.Context().Value(responseMetaKey{}).(*ResponseMeta); ok {
+		meta.StatusCode = resp.StatusCode
+		meta.RateLimit = parseRateLimit(resp.Header)
+		meta.RequestID = requestID
+		meta.Date, _ = http.ParseTime(resp.Header.Get("Date"))
+	}
+	if r, ok := req.Context().Value(requestIDKey{}).(*requestIDRecorder); ok {
+		r.id = requestID
+		r.status = resp.StatusCode
+	}
+
+	return resp, nil
+}
+
+// parseRateLimit reads the X-RateLimit-* headers. It returns nil if none are
+// present; headers that are present but malformed are left as zero values.
+func parseRateLimit(h http.Header) *RateLimit {
+	limit := h.Get(HeaderRateLimitLimit)
+	remaining := h.Get(HeaderRateLimitRemaining)
+	reset := h.Get(HeaderRateLimitReset)
+	if limit == "" && remaining == "" && reset == "" {
+		return nil
+	}
+
+	rl := &RateLimit{}
+	rl.Limit, _ = strconv.Atoi(limit)
+	rl.Remaining, _ = strconv.Atoi(remaining)
+	if secs, err := strconv.ParseInt(reset, 10, 64); err == nil {
+		rl.Reset = time.Unix(secs, 0)
+	}
+	return rl
+}
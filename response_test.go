@@ -0,0 +1,100 @@
+package xbow
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestResponseMeta(t *testing.T) {
+	withHeaders := func(h map[string]string) roundTripFunc {
+		return func(*http.Request) (*http.Response, error) {
+			resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}
+			for k, v := range h {
+				resp.Header.Set(k, v)
+			}
+			return resp, nil
+		}
+	}
+
+	t.Run("captures rate limit headers", func(t *testing.T) {
+		rt := NewTransport(withHeaders(map[string]string{
+			HeaderRateLimitLimit:     "100",
+			HeaderRateLimitRemaining: "42",
+			HeaderRateLimitReset:     "1767225600",
+		}))
+
+		ctx, meta := WithResponseMeta(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if meta.StatusCode != http.StatusOK {
+			t.Errorf("StatusCode = %d, want 200", meta.StatusCode)
+		}
+		if meta.RateLimit == nil {
+			t.Fatal("expected RateLimit to be set")
+		}
+		if meta.RateLimit.Limit != 100 {
+			t.Errorf("Limit = %d, want 100", meta.RateLimit.Limit)
+		}
+		if meta.RateLimit.Remaining != 42 {
+			t.Errorf("Remaining = %d, want 42", meta.RateLimit.Remaining)
+		}
+		if want := time.Unix(1767225600, 0); !meta.RateLimit.Reset.Equal(want) {
+			t.Errorf("Reset = %v, want %v", meta.RateLimit.Reset, want)
+		}
+	})
+
+	t.Run("captures request id", func(t *testing.T) {
+		rt := NewTransport(withHeaders(map[string]string{HeaderRequestID: "req-123"}))
+
+		ctx, meta := WithResponseMeta(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if meta.RequestID != "req-123" {
+			t.Errorf("RequestID = %q, want req-123", meta.RequestID)
+		}
+	})
+
+	t.Run("nil rate limit without headers", func(t *testing.T) {
+		rt := NewTransport(withHeaders(nil))
+
+		ctx, meta := WithResponseMeta(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if meta.RateLimit != nil {
+			t.Errorf("RateLimit = %+v, want nil", meta.RateLimit)
+		}
+	})
+
+	t.Run("client calls fill meta", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(HeaderRateLimitRemaining, "7")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+		}))
+
+		ctx, meta := WithResponseMeta(context.Background())
+		if _, err := client.Reports.GetSummary(ctx, "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if meta.RateLimit == nil || meta.RateLimit.Remaining != 7 {
+			t.Errorf("RateLimit = %+v, want Remaining 7", meta.RateLimit)
+		}
+	})
+}
@@ -1,10 +1,15 @@
 package xbow
 
 import (
+	"bytes"
 	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -16,8 +21,67 @@ type RetryPolicy struct {
 	Jitter               bool
 	RetryableStatusCodes []int
 	RetryPOST            bool
+
+	// JitterMode selects how backoff delays are randomized. When unset,
+	// Jitter chooses between JitterFull (true) and JitterNone (false).
+	JitterMode JitterMode
+
+	// MaxBufferBytes caps how much of a request body is buffered so it can
+	// be re-sent on retry. Requests with larger bodies are sent once without
+	// retrying. Bodies that can already be replayed (http.Request.GetBody
+	// is set) are not buffered. Default is 1 MB.
+	MaxBufferBytes int64
+
+	// ShouldRetry, if set, decides whether to retry after each attempt in
+	// place of RetryableStatusCodes, which is then ignored. It receives
+	// either the response or the transport error (resp is nil when err is
+	// set) and the number of the attempt just made, starting at 1. Without
+	// it, transport errors are never retried. MaxAttempts and the method
+	// rules still apply, and a declined response is returned unchanged.
+	//
+	// Example, also retrying connection resets:
+	//
+	//	ShouldRetry: func(resp *http.Response, err error, attempt int) bool {
+	//	    if err != nil {
+	//	        return errors.Is(err, syscall.ECONNRESET)
+	//	    }
+	//	    return resp.StatusCode == 429 || resp.StatusCode >= 502
+	//	},
+	ShouldRetry func(resp *http.Response, err error, attempt int) bool
+
+	// OnRetry, if set, is called before each backoff sleep, so retries can
+	// be logged or counted. It receives the number of the attempt that
+	// failed, starting at 1, its response or transport error (resp is nil
+	// when err is set) and the delay before the next attempt. It is not
+	// called for the attempt whose result is returned. The response body is
+	// closed once OnRetry returns and should not be read.
+	OnRetry func(attempt int, resp *http.Response, err error, delay time.Duration)
+
+	// WrapExhausted, if set, records on the returned *Error how many
+	// attempts were made when a call still fails after MaxAttempts retryable
+	// responses, so "failed after N retries" can be told apart from "failed
+	// immediately". See Error.Attempts.
+	WrapExhausted bool
 }
 
+// JitterMode selects how retry backoff delays are randomized. In the
+// descriptions below, exp is InitialBackoff doubled for each prior retry and
+// capped at MaxBackoff.
+type JitterMode int
+
+const (
+	// JitterNone waits exactly exp.
+	JitterNone JitterMode = iota + 1
+	// JitterFull waits a random duration in [0, exp).
+	JitterFull
+	// JitterEqual waits exp/2 plus a random duration in [0, exp/2), which
+	// avoids very short waits.
+	JitterEqual
+	// JitterDecorrelated waits a random duration between InitialBackoff and
+	// three times the previous wait, capped at MaxBackoff. It ignores exp.
+	JitterDecorrelated
+)
+
 func (p *RetryPolicy) defaults() {
 	if p.MaxAttempts <= 0 {
 		p.MaxAttempts = 3
@@ -31,6 +95,9 @@ func (p *RetryPolicy) defaults() {
 	if p.RetryableStatusCodes == nil {
 		p.RetryableStatusCodes = []int{429, 500, 502, 503, 504}
 	}
+	if p.MaxBufferBytes <= 0 {
+		p.MaxBufferBytes = 1024 * 1024
+	}
 }
 
 // WithRetryPolicy enables automatic retries with exponential backoff for
@@ -40,6 +107,11 @@ func (p *RetryPolicy) defaults() {
 // Retries are performed with exponential backoff and optional jitter (enabled
 // by default) to avoid thundering herd problems.
 //
+// When combined with WithRateLimiter, the limiter wraps the retry transport:
+// it is consulted once per call, and the retries for that call happen
+// underneath it without waiting on the limiter again. Without a policy, no
+// retries are made.
+//
 //	client, err := xbow.NewClient(
 //	    xbow.WithOrganizationKey("key"),
 //	    xbow.WithRetryPolicy(&xbow.RetryPolicy{
@@ -49,14 +121,15 @@ func (p *RetryPolicy) defaults() {
 //	)
 func WithRetryPolicy(p *RetryPolicy) ClientOption {
 	return func(c *clientConfig) {
-		c.retryPolicy = p
+		c.transport.retryPolicy = p
 	}
 }
 
 // retryTransport wraps an http.RoundTripper with retry logic.
 type retryTransport struct {
-	base   http.RoundTripper
-	policy RetryPolicy
+	base    http.RoundTripper
+	policy  RetryPolicy
+	onEvent TraceEventHandler
 }
 
 func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -64,26 +137,74 @@ func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return t.base.RoundTrip(req)
 	}
 
+	getBody, unbuffered, err := t.replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if unbuffered != nil {
+		once := req.Clone(req.Context())
+		once.Body = unbuffered
+		return t.base.RoundTrip(once)
+	}
+
 	var resp *http.Response
-	var err error
+	var prev time.Duration
 
 	for attempt := range t.policy.MaxAttempts {
-		resp, err = t.base.RoundTrip(req)
-		if err != nil {
-			return nil, err
+		// A request that can replay its own body is sent as is first.
+		// Otherwise, and on every retry, a clone carries a fresh copy, so
+		// the caller's request is never modified.
+		attemptReq := req
+		if getBody != nil && (attempt > 0 || req.GetBody == nil) {
+			body, err := getBody()
+			if err != nil {
+				return nil, fmt.Errorf("resetting request body: %w", err)
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+			attemptReq.GetBody = getBody
 		}
 
-		if !t.isRetryableStatus(resp.StatusCode) {
-			return resp, nil
+		resp, err = t.base.RoundTrip(attemptReq)
+		if errors.Is(err, ErrCircuitOpen) {
+			return resp, err
+		}
+		if !t.shouldRetry(resp, err, attempt+1) {
+			return resp, err
 		}
-
 		if attempt == t.policy.MaxAttempts-1 {
-			return resp, nil
+			if t.policy.WrapExhausted {
+				recordAttempts(req.Context(), attempt+1)
+			}
+			return resp, err
 		}
 
-		_ = resp.Body.Close()
+		var backoff time.Duration
+		var reason string
+		if err != nil {
+			backoff = t.backoff(attempt, prev)
+			reason = err.Error()
+		} else {
+			backoff = t.delay(attempt, prev, resp)
+			reason = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		if t.policy.OnRetry != nil {
+			t.policy.OnRetry(attempt+1, resp, err, backoff)
+		}
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		if t.onEvent != nil {
+			t.onEvent(req.Context(), TraceEvent{
+				Name:    TraceEventRetry,
+				Attempt: attempt + 2,
+				Delay:   backoff,
+				Reason:  reason,
+			})
+		}
+		prev = backoff
 
-		backoff := t.backoff(attempt)
 		timer := time.NewTimer(backoff)
 		select {
 		case <-req.Context().Done():
@@ -96,6 +217,37 @@ func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
+// replayableBody returns a function yielding a fresh copy of req's body for
+// each attempt, buffering the body when req cannot replay it itself. It
+// returns a nil function when there is no body. If the body is larger than
+// MaxBufferBytes, it instead returns the full body as unbuffered, to be sent
+// once. req is not modified, though its body may have been read.
+func (t *retryTransport) replayableBody(req *http.Request) (getBody func() (io.ReadCloser, error), unbuffered io.ReadCloser, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil, nil
+	}
+	if req.GetBody != nil {
+		return req.GetBody, nil, nil
+	}
+
+	buf, err := io.ReadAll(io.LimitReader(req.Body, t.policy.MaxBufferBytes+1))
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, nil, fmt.Errorf("buffering request body: %w", err)
+	}
+	if int64(len(buf)) > t.policy.MaxBufferBytes {
+		return nil, struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}, nil
+	}
+	_ = req.Body.Close()
+
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}, nil, nil
+}
+
 func (t *retryTransport) isRetryableMethod(method string) bool {
 	switch method {
 	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
@@ -106,6 +258,15 @@ func (t *retryTransport) isRetryableMethod(method string) bool {
 	return false
 }
 
+// shouldRetry reports whether to retry after the given attempt, consulting
+// the policy's ShouldRetry hook when set.
+func (t *retryTransport) shouldRetry(resp *http.Response, err error, attempt int) bool {
+	if t.policy.ShouldRetry != nil {
+		return t.policy.ShouldRetry(resp, err, attempt)
+	}
+	return err == nil && t.isRetryableStatus(resp.StatusCode)
+}
+
 func (t *retryTransport) isRetryableStatus(status int) bool {
 	for _, s := range t.policy.RetryableStatusCodes {
 		if s == status {
@@ -115,14 +276,80 @@ func (t *retryTransport) isRetryableStatus(status int) bool {
 	return false
 }
 
-func (t *retryTransport) backoff(attempt int) time.Duration {
-	backoff := float64(t.policy.InitialBackoff) * math.Pow(2, float64(attempt))
-	if backoff > float64(t.policy.MaxBackoff) {
-		backoff = float64(t.policy.MaxBackoff)
+// delay returns how long to wait before retrying after resp. A Retry-After
+// header takes precedence over the exponential backoff, capped at MaxBackoff.
+func (t *retryTransport) delay(attempt int, prev time.Duration, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp.Header, time.Now()); ok {
+		return min(d, t.policy.MaxBackoff)
+	}
+	return t.backoff(attempt, prev)
+}
+
+// retryAfter parses a Retry-After header given either as delta-seconds or as
+// an HTTP-date relative to now. It reports false if the header is absent or
+// unparseable.
+func retryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// backoff returns the wait before the retry following attempt (0-based),
+// given the previous wait (zero before the first retry).
+func (t *retryTransport) backoff(attempt int, prev time.Duration) time.Duration {
+	exp := float64(t.policy.InitialBackoff) * math.Pow(2, float64(attempt))
+	if exp > float64(t.policy.MaxBackoff) {
+		exp = float64(t.policy.MaxBackoff)
+	}
+	d := time.Duration(exp)
+
+	switch t.jitterMode() {
+	case JitterFull:
+		return randDuration(d)
+	case JitterEqual:
+		return d/2 + randDuration(d-d/2)
+	case JitterDecorrelated:
+		base := t.policy.InitialBackoff
+		upper := min(max(prev, base)*3, t.policy.MaxBackoff)
+		if upper <= base {
+			return upper
+		}
+		return base + randDuration(upper-base)
+	default:
+		return d
+	}
+}
+
+func (t *retryTransport) jitterMode() JitterMode {
+	if t.policy.JitterMode != 0 {
+		return t.policy.JitterMode
 	}
 	if t.policy.Jitter {
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(backoff)))
-		backoff = float64(n.Int64())
+		return JitterFull
+	}
+	return JitterNone
+}
+
+// randDuration returns a uniformly random duration in [0, n), or 0 if n is
+// not positive.
+func randDuration(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	r, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0
 	}
-	return time.Duration(backoff)
+	return time.Duration(r.Int64())
 }
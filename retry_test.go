@@ -3,7 +3,10 @@ package xbow
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
+	"slices"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -103,6 +106,59 @@ func TestRetryTransport_ReturnsOnEventualSuccess(t *testing.T) {
 	}
 }
 
+func TestRetryTransport_OnRetry(t *testing.T) {
+	type call struct {
+		attempt int
+		status  int
+		delay   time.Duration
+	}
+	var calls []call
+	var sends atomic.Int32
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sends.Add(1)
+		return &http.Response{StatusCode: 503, Body: http.NoBody}, nil
+	}), &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		OnRetry: func(attempt int, resp *http.Response, err error, delay time.Duration) {
+			if err != nil {
+				t.Errorf("attempt %d: unexpected error %v", attempt, err)
+			}
+			calls = append(calls, call{attempt, resp.StatusCode, delay})
+		},
+	})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := sends.Load(); got != 4 {
+		t.Fatalf("sends = %d, want 4", got)
+	}
+	// The final, returned attempt is not reported.
+	if len(calls) != 3 {
+		t.Fatalf("OnRetry calls = %d, want 3", len(calls))
+	}
+	for i, c := range calls {
+		if c.attempt != i+1 {
+			t.Errorf("call %d: attempt = %d, want %d", i, c.attempt, i+1)
+		}
+		if c.status != 503 {
+			t.Errorf("call %d: status = %d, want 503", i, c.status)
+		}
+		if c.delay <= 0 || c.delay > 10*time.Millisecond {
+			t.Errorf("call %d: delay = %v, want within (0, MaxBackoff]", i, c.delay)
+		}
+		if i > 0 && c.delay < calls[i-1].delay {
+			t.Errorf("call %d: delay %v shorter than previous %v", i, c.delay, calls[i-1].delay)
+		}
+	}
+}
+
 func TestRetryTransport_SkipsPOSTByDefault(t *testing.T) {
 	var calls atomic.Int32
 	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
@@ -204,6 +260,93 @@ func TestRetryTransport_NoRetryOnTransportError(t *testing.T) {
 	}
 }
 
+func TestRetryTransport_ShouldRetryTransportError(t *testing.T) {
+	var calls atomic.Int32
+	transportErr := &netError{msg: "connection reset by peer"}
+	var attempts []int
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) < 3 {
+			return nil, transportErr
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		ShouldRetry: func(resp *http.Response, err error, attempt int) bool {
+			attempts = append(attempts, attempt)
+			return errors.Is(err, transportErr)
+		},
+	})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(attempts, want) {
+		t.Errorf("attempts = %v, want %v", attempts, want)
+	}
+}
+
+func TestRetryTransport_ShouldRetryDeclines503(t *testing.T) {
+	var calls atomic.Int32
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+	}), &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		ShouldRetry: func(resp *http.Response, err error, attempt int) bool {
+			return resp != nil && resp.StatusCode != http.StatusServiceUnavailable && resp.StatusCode >= 500
+		},
+	})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1 (hook declined the retry)", got)
+	}
+}
+
+func TestRetryTransport_ShouldRetryExhausted(t *testing.T) {
+	var calls atomic.Int32
+	transportErr := &netError{msg: "TLS handshake timeout"}
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return nil, transportErr
+	}), &RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		ShouldRetry:    func(*http.Response, error, int) bool { return true },
+	})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	resp, err := rt.RoundTrip(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	if !errors.Is(err, transportErr) {
+		t.Errorf("err = %v, want %v", err, transportErr)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
 type netError struct {
 	msg string
 }
@@ -264,6 +407,124 @@ func TestRetryTransport_NonRetryableStatusNotRetried(t *testing.T) {
 	}
 }
 
+func TestRetryTransport_ResendsBufferedPOSTBody(t *testing.T) {
+	var bodies []string
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			return &http.Response{StatusCode: 503, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	}), &RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		RetryPOST:      true,
+	})
+
+	// A plain io.Reader leaves GetBody nil, so the transport must buffer.
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "https://example.com",
+		io.NopCloser(strings.NewReader(`{"name":"asset"}`)))
+	if req.GetBody != nil {
+		t.Fatal("GetBody should be nil for this test")
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if len(bodies) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(bodies))
+	}
+	for i, b := range bodies {
+		if b != `{"name":"asset"}` {
+			t.Errorf("attempt %d body = %q, want %q", i+1, b, `{"name":"asset"}`)
+		}
+	}
+	if req.GetBody != nil {
+		t.Error("RoundTrip set GetBody on the caller's request")
+	}
+}
+
+func TestRetryTransport_BufferedBodyReplayableUnderneath(t *testing.T) {
+	// retryAfterTransport resends only requests that can replay their body,
+	// so every attempt, the first included, must carry GetBody.
+	var bodies []string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"0"}}, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	})
+	rt := newRetryTransport(&retryAfterTransport{base: base, maxWait: time.Second}, &RetryPolicy{
+		MaxAttempts:          2,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           10 * time.Millisecond,
+		RetryPOST:            true,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	})
+
+	body := io.NopCloser(strings.NewReader(`{"name":"asset"}`))
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "https://example.com", body)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 || len(bodies) != 2 || bodies[1] != `{"name":"asset"}` {
+		t.Errorf("status %d after bodies %q, want 200 after the body sent twice", resp.StatusCode, bodies)
+	}
+	if req.Body != body || req.GetBody != nil {
+		t.Error("RoundTrip modified the caller's request")
+	}
+}
+
+func TestRetryTransport_OversizedBodyNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	var got string
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		got = string(b)
+		return &http.Response{StatusCode: 503, Body: http.NoBody}, nil
+	}), &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		RetryPOST:      true,
+		MaxBufferBytes: 4,
+	})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, "https://example.com",
+		io.NopCloser(strings.NewReader("0123456789")))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1", n)
+	}
+	if got != "0123456789" {
+		t.Errorf("body = %q, want the full body", got)
+	}
+}
+
 func TestRetryPolicyDefaults(t *testing.T) {
 	p := &RetryPolicy{}
 	p.defaults()
@@ -277,6 +538,9 @@ func TestRetryPolicyDefaults(t *testing.T) {
 	if p.MaxBackoff != 30*time.Second {
 		t.Errorf("MaxBackoff = %v, want 30s", p.MaxBackoff)
 	}
+	if p.MaxBufferBytes != 1024*1024 {
+		t.Errorf("MaxBufferBytes = %d, want 1048576", p.MaxBufferBytes)
+	}
 	if p.RetryableStatusCodes == nil {
 		t.Fatal("RetryableStatusCodes should not be nil")
 	}
@@ -336,7 +600,7 @@ func TestBackoff(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := rt.backoff(tt.attempt)
+		got := rt.backoff(tt.attempt, 0)
 		if got != tt.want {
 			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
 		}
@@ -353,9 +617,247 @@ func TestBackoffWithJitter(t *testing.T) {
 	}
 
 	for range 100 {
-		got := rt.backoff(0)
+		got := rt.backoff(0, 0)
 		if got < 0 || got > 100*time.Millisecond {
 			t.Errorf("backoff(0) with jitter = %v, want [0, 100ms]", got)
 		}
 	}
 }
+
+func TestBackoffJitterModes(t *testing.T) {
+	const (
+		initial = 100 * time.Millisecond
+		maxWait = time.Second
+	)
+
+	tests := []struct {
+		name     string
+		mode     JitterMode
+		attempt  int
+		prev     time.Duration
+		min, max time.Duration
+	}{
+		{"none", JitterNone, 2, 0, 400 * time.Millisecond, 400 * time.Millisecond},
+		{"none capped", JitterNone, 5, 0, maxWait, maxWait},
+		{"full", JitterFull, 2, 0, 0, 400 * time.Millisecond},
+		{"equal", JitterEqual, 2, 0, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"equal capped", JitterEqual, 5, 0, 500 * time.Millisecond, maxWait},
+		{"decorrelated first", JitterDecorrelated, 0, 0, initial, 300 * time.Millisecond},
+		{"decorrelated", JitterDecorrelated, 3, 200 * time.Millisecond, initial, 600 * time.Millisecond},
+		{"decorrelated capped", JitterDecorrelated, 4, 800 * time.Millisecond, initial, maxWait},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &retryTransport{
+				policy: RetryPolicy{
+					InitialBackoff: initial,
+					MaxBackoff:     maxWait,
+					JitterMode:     tt.mode,
+				},
+			}
+			for range 1000 {
+				got := rt.backoff(tt.attempt, tt.prev)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%d, %v) = %v, want [%v, %v]", tt.attempt, tt.prev, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestJitterModeDefault(t *testing.T) {
+	tests := []struct {
+		policy RetryPolicy
+		want   JitterMode
+	}{
+		{RetryPolicy{}, JitterNone},
+		{RetryPolicy{Jitter: true}, JitterFull},
+		{RetryPolicy{Jitter: true, JitterMode: JitterEqual}, JitterEqual},
+		{RetryPolicy{Jitter: false, JitterMode: JitterDecorrelated}, JitterDecorrelated},
+	}
+	for _, tt := range tests {
+		rt := &retryTransport{policy: tt.policy}
+		if got := rt.jitterMode(); got != tt.want {
+			t.Errorf("jitterMode() for %+v = %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestWithRetryPolicy_RetriesAgainstServer(t *testing.T) {
+	var calls atomic.Int32
+	limiter := &countingLimiter{}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"ERR_UNAVAILABLE","error":"Service Unavailable","message":"try again"}`))
+	}),
+		WithRateLimiter(limiter),
+		WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:    4,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		}),
+	)
+
+	_, err := client.Reports.GetSummary(context.Background(), "report-1")
+	if !errors.Is(err, ErrInternalServer) {
+		t.Errorf("err = %v, want ErrInternalServer", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("server calls = %d, want 4", got)
+	}
+	if got := limiter.calls.Load(); got != 1 {
+		t.Errorf("limiter calls = %d, want 1", got)
+	}
+}
+
+func TestRetryPolicy_WrapExhausted(t *testing.T) {
+	newClient := func(t *testing.T, wrap bool, status *atomic.Int32) *Client {
+		return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(int(status.Load()))
+			_, _ = w.Write([]byte(`{"code":"ERR_UNAVAILABLE","error":"Service Unavailable","message":"try again"}`))
+		}), WithRetryPolicy(&RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			WrapExhausted:  wrap,
+		}))
+	}
+
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+
+	t.Run("generated client", func(t *testing.T) {
+		_, err := newClient(t, true, &status).Reports.GetSummary(context.Background(), "report-1")
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *Error", err)
+		}
+		if apiErr.Attempts != 3 {
+			t.Errorf("Attempts = %d, want 3", apiErr.Attempts)
+		}
+		if !strings.Contains(err.Error(), "attempts=3") {
+			t.Errorf("Error() = %q, want attempt count", err.Error())
+		}
+	})
+
+	t.Run("raw request", func(t *testing.T) {
+		_, err := newClient(t, true, &status).Meta.GetOpenAPISpec(context.Background())
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Attempts != 3 {
+			t.Errorf("err = %v, want *Error with Attempts 3", err)
+		}
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		_, err := newClient(t, false, &status).Reports.GetSummary(context.Background(), "report-1")
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Attempts != 0 {
+			t.Errorf("err = %v, want *Error with Attempts 0", err)
+		}
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		var badRequest atomic.Int32
+		badRequest.Store(http.StatusBadRequest)
+		_, err := newClient(t, true, &badRequest).Reports.GetSummary(context.Background(), "report-1")
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Attempts != 0 {
+			t.Errorf("err = %v, want *Error with Attempts 0 after failing immediately", err)
+		}
+	})
+}
+
+func TestNewClient_NoRetryPolicyDoesNotRetry(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server calls = %d, want 1", got)
+	}
+}
+
+func TestDelayRespectsRetryAfter(t *testing.T) {
+	rt := &retryTransport{
+		policy: RetryPolicy{
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     10 * time.Second,
+			Jitter:         false,
+		},
+	}
+
+	withRetryAfter := func(v string) *http.Response {
+		resp := &http.Response{Header: http.Header{}}
+		if v != "" {
+			resp.Header.Set("Retry-After", v)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		check func(time.Duration) bool
+		want  string
+	}{
+		{"delta-seconds", "3", func(d time.Duration) bool { return d == 3*time.Second }, "3s"},
+		{"http-date", time.Now().Add(5 * time.Second).UTC().Format(http.TimeFormat), func(d time.Duration) bool {
+			return d > 3*time.Second && d <= 5*time.Second
+		}, "about 5s"},
+		{"clamped to MaxBackoff", "120", func(d time.Duration) bool { return d == 10*time.Second }, "10s"},
+		{"date in the past", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), func(d time.Duration) bool { return d == 0 }, "0s"},
+		{"absent falls back to backoff", "", func(d time.Duration) bool { return d == 200*time.Millisecond }, "200ms"},
+		{"unparseable falls back to backoff", "soon", func(d time.Duration) bool { return d == 200*time.Millisecond }, "200ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rt.delay(1, 0, withRetryAfter(tt.value))
+			if !tt.check(got) {
+				t.Errorf("delay() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_WaitsForRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			resp := &http.Response{StatusCode: 429, Header: http.Header{}, Body: http.NoBody}
+			resp.Header.Set("Retry-After", "1")
+			return resp, nil
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+	}), &RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Jitter:         false,
+	})
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+	start := time.Now()
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Retry-After: 1 is clamped to MaxBackoff (50ms) but exceeds InitialBackoff (1ms).
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 50ms", elapsed)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
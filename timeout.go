@@ -0,0 +1,60 @@
+package xbow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithDefaultTimeout bounds each API call whose context has no deadline by
+// d, covering retries and reading the response body, so a hung server
+// cannot block a caller that passed context.Background(). Deadlines the
+// caller already set, including those from the WithTimeout request option,
+// are left unchanged. By default there is no timeout.
+//
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("key"),
+//	    xbow.WithDefaultTimeout(30*time.Second),
+//	)
+func WithDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.defaultTimeout = d
+	}
+}
+
+// timeoutTransport applies a timeout to requests whose context has no
+// deadline. The timeout stays in force until the response body is closed.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := req.Context().Deadline(); ok {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil || resp.Body == nil {
+		cancel()
+		return resp, err
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases a request's timeout context when its response
+// body is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,57 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// slowHandler answers with a report summary after delay, or gives up when
+// the client goes away.
+func slowHandler(delay time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"markdown":"ok"}`))
+	})
+}
+
+func TestWithDefaultTimeout(t *testing.T) {
+	t.Run("fires without caller deadline", func(t *testing.T) {
+		client := newTestClient(t, slowHandler(5*time.Second), WithDefaultTimeout(50*time.Millisecond))
+
+		start := time.Now()
+		_, err := client.Reports.GetSummary(context.Background(), "report-1")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("took %v, want the default timeout to fire", elapsed)
+		}
+	})
+
+	t.Run("caller deadline preserved", func(t *testing.T) {
+		client := newTestClient(t, slowHandler(200*time.Millisecond), WithDefaultTimeout(50*time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := client.Reports.GetSummary(ctx, "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("covers streamed body", func(t *testing.T) {
+		client := newTestClient(t, slowHandler(10*time.Millisecond), WithDefaultTimeout(time.Second))
+
+		if _, err := client.Reports.Download(context.Background(), "report-1", io.Discard); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package xbow
+
+import (
+	"context"
+	"time"
+)
+
+// Trace event names.
+const (
+	// TraceEventRetry is emitted before each retry attempt.
+	TraceEventRetry = "xbow.retry"
+	// TraceEventRateLimitWait is emitted after each rate limiter wait.
+	TraceEventRateLimitWait = "xbow.rate_limit.wait"
+)
+
+// TraceEvent describes a retry or rate-limit wait within a single request.
+type TraceEvent struct {
+	// Name is TraceEventRetry or TraceEventRateLimitWait.
+	Name string
+	// Attempt is the number of the attempt about to be made, starting at 1.
+	// It is always 1 for rate-limit waits.
+	Attempt int
+	// Delay is how long the transport waited, or is about to wait.
+	Delay time.Duration
+	// Reason explains the event, such as "status 503".
+	Reason string
+}
+
+// TraceEventHandler receives trace events. ctx is the request context, so it
+// carries the caller's active span.
+//
+// The handler makes it straightforward to record events on an OpenTelemetry
+// span without the SDK depending on OpenTelemetry:
+//
+//	func(ctx context.Context, ev xbow.TraceEvent) {
+//	    trace.SpanFromContext(ctx).AddEvent(ev.Name, trace.WithAttributes(
+//	        attribute.Int("attempt", ev.Attempt),
+//	        attribute.String("delay", ev.Delay.String()),
+//	        attribute.String("reason", ev.Reason),
+//	    ))
+//	}
+type TraceEventHandler func(ctx context.Context, ev TraceEvent)
+
+// WithTraceEventHandler sets a handler that is called for each retry attempt
+// and rate-limit wait. Without one, no events are produced.
+func WithTraceEventHandler(h TraceEventHandler) ClientOption {
+	return func(c *clientConfig) {
+		c.transport.onEvent = h
+	}
+}
+
+// WithTransportTraceEventHandler sets a trace event handler on the transport
+// stack. It behaves like WithTraceEventHandler on the client.
+func WithTransportTraceEventHandler(h TraceEventHandler) TransportOption {
+	return func(c *transportConfig) {
+		c.onEvent = h
+	}
+}
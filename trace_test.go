@@ -0,0 +1,99 @@
+package xbow
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingSpan stands in for a tracing span carried on the request context.
+type recordingSpan struct {
+	mu     sync.Mutex
+	events []TraceEvent
+}
+
+type recordingSpanKey struct{}
+
+// spanRecorder is a TraceEventHandler that adds events to the span in ctx.
+func spanRecorder(ctx context.Context, ev TraceEvent) {
+	if span, ok := ctx.Value(recordingSpanKey{}).(*recordingSpan); ok {
+		span.mu.Lock()
+		span.events = append(span.events, ev)
+		span.mu.Unlock()
+	}
+}
+
+func TestTraceEvents(t *testing.T) {
+	t.Run("retried call records timeline on request span", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, flakyHandler(2, `{"markdown":"ok"}`, &calls),
+			WithRateLimiter(&countingLimiter{}),
+			WithRetryPolicy(&RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+			WithTraceEventHandler(spanRecorder),
+		)
+
+		span := &recordingSpan{}
+		ctx := context.WithValue(context.Background(), recordingSpanKey{}, span)
+		if _, err := client.Reports.GetSummary(ctx, "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []TraceEvent{
+			{Name: TraceEventRateLimitWait, Attempt: 1, Reason: "rate limiter"},
+			{Name: TraceEventRetry, Attempt: 2, Reason: "status 503"},
+			{Name: TraceEventRetry, Attempt: 3, Reason: "status 503"},
+		}
+		if len(span.events) != len(want) {
+			t.Fatalf("got %d events %+v, want %d", len(span.events), span.events, len(want))
+		}
+		for i, w := range want {
+			got := span.events[i]
+			if got.Name != w.Name || got.Attempt != w.Attempt || got.Reason != w.Reason {
+				t.Errorf("events[%d] = %+v, want %+v", i, got, w)
+			}
+		}
+		if d := span.events[1].Delay; d != time.Millisecond {
+			t.Errorf("retry Delay = %v, want 1ms", d)
+		}
+	})
+
+	t.Run("no handler is a no-op", func(t *testing.T) {
+		var calls atomic.Int32
+		client := newTestClient(t, flakyHandler(1, `{"markdown":"ok"}`, &calls),
+			WithRetryPolicy(&RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		)
+
+		span := &recordingSpan{}
+		ctx := context.WithValue(context.Background(), recordingSpanKey{}, span)
+		if _, err := client.Reports.GetSummary(ctx, "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(span.events) != 0 {
+			t.Errorf("got %d events, want 0", len(span.events))
+		}
+	})
+
+	t.Run("standalone transport emits events", func(t *testing.T) {
+		var events []TraceEvent
+		rt := NewTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+			WithTransportRateLimiter(&countingLimiter{}),
+			WithTransportTraceEventHandler(func(_ context.Context, ev TraceEvent) { events = append(events, ev) }),
+		)
+
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if len(events) != 1 || events[0].Name != TraceEventRateLimitWait {
+			t.Errorf("events = %+v, want one rate-limit wait", events)
+		}
+	})
+}
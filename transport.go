@@ -0,0 +1,235 @@
+package xbow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// TransportOption configures the transport stack built by NewTransport.
+type TransportOption func(*transportConfig)
+
+type transportConfig struct {
+	rateLimiter RateLimiter
+	retryPolicy *RetryPolicy
+	breaker     *CircuitBreakerSettings
+	onEvent     TraceEventHandler
+	logger      *slog.Logger
+	metrics     Metrics
+
+	// maxResponseBytes caps response bodies; zero or less means no cap.
+	maxResponseBytes int64
+
+	// defaultTimeout bounds requests without a deadline; zero means none.
+	defaultTimeout time.Duration
+
+	// debug receives a wire dump of every attempt when set.
+	debug io.Writer
+
+	// respectRateLimit resends a request once after a 429's Retry-After.
+	respectRateLimit bool
+
+	// dryRun holds back mutating requests instead of sending them.
+	dryRun bool
+
+	// maxConcurrent caps requests in flight; zero or less means no cap.
+	maxConcurrent int
+}
+
+// WithTransportRateLimiter adds a rate limiter to the transport stack.
+// It behaves like WithRateLimiter on the client.
+func WithTransportRateLimiter(limiter RateLimiter) TransportOption {
+	return func(c *transportConfig) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithTransportRetryPolicy adds retries to the transport stack.
+// It behaves like WithRetryPolicy on the client.
+func WithTransportRetryPolicy(p *RetryPolicy) TransportOption {
+	return func(c *transportConfig) {
+		c.retryPolicy = p
+	}
+}
+
+// NewTransport wraps base with the same transport stack that NewClient
+// installs (retries, rate limiting, logging and response metadata capture
+// for WithResponseMeta), so it can be shared with an *http.Client used outside
+// the SDK. If base is nil, http.DefaultTransport is used. The rate limiter
+// runs once per request while retries happen underneath it, and every
+// attempt is logged; see wrap for the full order of the layers.
+//
+// Example:
+//
+//	httpClient := &http.Client{
+//	    Transport: xbow.NewTransport(myTransport,
+//	        xbow.WithTransportRetryPolicy(&xbow.RetryPolicy{}),
+//	        xbow.WithTransportRateLimiter(limiter),
+//	    ),
+//	}
+func NewTransport(base http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	cfg := &transportConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg.wrap(base)
+}
+
+// wrap builds the transport stack around base. Only the layers c enables
+// are added, outermost first:
+//
+//	responseMetaTransport → dryRunTransport → metricsTransport →
+//	maxBytesTransport → timeoutTransport → rateLimitTransport →
+//	retryTransport → retryAfterTransport → concurrencyTransport →
+//	circuitBreakerTransport → loggingTransport → debugTransport → base
+//
+// This is the one place the order is written down; keep it in step with
+// the code below.
+func (c *transportConfig) wrap(base http.RoundTripper) http.RoundTripper {
+	transport := base
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if c.debug != nil {
+		transport = &debugTransport{base: transport, w: c.debug}
+	}
+
+	if c.logger != nil {
+		transport = &loggingTransport{base: transport, logger: c.logger}
+	}
+
+	if c.breaker != nil {
+		transport = newCircuitBreakerTransport(transport, *c.breaker)
+	}
+
+	if c.maxConcurrent > 0 {
+		transport = newConcurrencyTransport(transport, c.maxConcurrent)
+	}
+
+	if c.respectRateLimit {
+		transport = &retryAfterTransport{base: transport, maxWait: maxRateLimitWait, onEvent: c.onEvent}
+	}
+
+	if c.retryPolicy != nil {
+		policy := *c.retryPolicy
+		policy.defaults()
+		transport = &retryTransport{base: transport, policy: policy, onEvent: c.onEvent}
+	}
+
+	if c.rateLimiter != nil {
+		transport = &rateLimitTransport{base: transport, limiter: c.rateLimiter, onEvent: c.onEvent}
+	}
+
+	if c.defaultTimeout > 0 {
+		transport = &timeoutTransport{base: transport, timeout: c.defaultTimeout}
+	}
+
+	if c.maxResponseBytes > 0 {
+		transport = &maxBytesTransport{base: transport, limit: c.maxResponseBytes}
+	}
+
+	if c.metrics != nil {
+		transport = &metricsTransport{base: transport, metrics: c.metrics}
+	}
+
+	if c.dryRun {
+		transport = &dryRunTransport{base: transport}
+	}
+
+	return &responseMetaTransport{base: transport}
+}
+
+// NewHandlerTransport returns a transport that serves every request by
+// calling h in memory, as a server would, without opening a connection. It
+// is meant for tests and local mocks:
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("GET /api/v1/assessments/{id}", func(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set("Content-Type", "application/json")
+//	    _, _ = w.Write(cannedAssessment)
+//	})
+//	client, err := xbow.NewClient(
+//	    xbow.WithOrganizationKey("test-key"),
+//	    xbow.WithTransport(xbow.NewHandlerTransport(mux)),
+//	)
+//
+// The handler's response is buffered in full before it is returned.
+func NewHandlerTransport(h http.Handler) http.RoundTripper {
+	return &handlerTransport{handler: h}
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t *handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer func() { _ = req.Body.Close() }()
+	}
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	// Give the handler the request as a server would see it.
+	serverReq := req.Clone(req.Context())
+	serverReq.RequestURI = req.URL.RequestURI()
+	serverReq.RemoteAddr = "192.0.2.1:1234"
+	if serverReq.Host == "" {
+		serverReq.Host = req.URL.Host
+	}
+	if serverReq.Body == nil {
+		serverReq.Body = http.NoBody
+	}
+
+	w := &bufferedResponseWriter{header: make(http.Header)}
+	t.handler.ServeHTTP(w, serverReq)
+	return w.response(req), nil
+}
+
+// bufferedResponseWriter collects a handler's response in memory.
+type bufferedResponseWriter struct {
+	header      http.Header
+	wroteHeader http.Header
+	status      int
+	body        bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	w.wroteHeader = w.header.Clone()
+}
+
+func (w *bufferedResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+func (w *bufferedResponseWriter) response(req *http.Request) *http.Response {
+	w.WriteHeader(http.StatusOK)
+	header := w.wroteHeader
+	if header.Get("Content-Type") == "" && w.body.Len() > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.body.Bytes()))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", w.status, http.StatusText(w.status)),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(w.body.Bytes())),
+		ContentLength: int64(w.body.Len()),
+		Request:       req,
+	}
+}
@@ -0,0 +1,241 @@
+package xbow
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingLimiter is a RateLimiter that records how often Wait is called.
+type countingLimiter struct {
+	calls atomic.Int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.calls.Add(1)
+	return ctx.Err()
+}
+
+// flakyHandler fails the first n requests with 503 and then serves body.
+func flakyHandler(n int32, body string, calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func TestNewTransport(t *testing.T) {
+	policy := func() *RetryPolicy {
+		return &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	}
+
+	t.Run("standalone transport retries and rate-limits", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(flakyHandler(2, `{}`, &calls))
+		t.Cleanup(srv.Close)
+
+		limiter := &countingLimiter{}
+		httpClient := &http.Client{Transport: NewTransport(nil,
+			WithTransportRetryPolicy(policy()),
+			WithTransportRateLimiter(limiter),
+		)}
+
+		resp, err := httpClient.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+		}
+		if got := calls.Load(); got != 3 {
+			t.Errorf("server calls = %d, want 3", got)
+		}
+		if got := limiter.calls.Load(); got != 1 {
+			t.Errorf("limiter calls = %d, want 1", got)
+		}
+	})
+
+	t.Run("client behaves identically", func(t *testing.T) {
+		var calls atomic.Int32
+		limiter := &countingLimiter{}
+		client := newTestClient(t, flakyHandler(2, `{"summary":"ok"}`, &calls),
+			WithRetryPolicy(policy()),
+			WithRateLimiter(limiter),
+		)
+
+		if _, err := client.Reports.GetSummary(context.Background(), "report-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := calls.Load(); got != 3 {
+			t.Errorf("server calls = %d, want 3", got)
+		}
+		if got := limiter.calls.Load(); got != 1 {
+			t.Errorf("limiter calls = %d, want 1", got)
+		}
+	})
+
+	t.Run("no options only adds response metadata", func(t *testing.T) {
+		base := roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+		rt, ok := NewTransport(base).(*responseMetaTransport)
+		if !ok {
+			t.Fatalf("NewTransport() = %T, want *responseMetaTransport", rt)
+		}
+		if _, ok := rt.base.(roundTripFunc); !ok {
+			t.Errorf("base = %T, want the given base unchanged", rt.base)
+		}
+	})
+
+	t.Run("does not mutate caller policy", func(t *testing.T) {
+		p := &RetryPolicy{}
+		NewTransport(nil, WithTransportRetryPolicy(p))
+		if p.MaxAttempts != 0 {
+			t.Errorf("MaxAttempts = %d, want 0", p.MaxAttempts)
+		}
+	})
+}
+
+func TestWithTransport_HandlerTransport(t *testing.T) {
+	var auth string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/assessments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		if r.PathValue("id") != "assess-123" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"no such assessment"}`))
+			return
+		}
+		_, _ = w.Write([]byte(testAssessmentJSON(AssessmentStateRunning)))
+	})
+
+	unused := roundTripFunc(func(*http.Request) (*http.Response, error) {
+		t.Error("request reached the HTTP client's transport")
+		return nil, http.ErrNotSupported
+	})
+	client, err := NewClient(
+		WithOrganizationKey("test-key"),
+		WithHTTPClient(&http.Client{Transport: unused}),
+		WithTransport(NewHandlerTransport(mux)),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	assessment, err := client.Assessments.Get(context.Background(), "assess-123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assessment.ID != "assess-123" || assessment.State != AssessmentStateRunning {
+		t.Errorf("got %+v", assessment)
+	}
+	if auth != "Bearer test-key" {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	if _, err := client.Assessments.Get(context.Background(), "missing"); !IsNotFound(err) {
+		t.Errorf("error = %v, want not found", err)
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testAssetJSON))
+	}))
+	// The untrusted case fails the handshake; keep that out of the output.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	t.Run("root CAs on a clone of the default transport", func(t *testing.T) {
+		client, err := NewClient(
+			WithOrganizationKey("key"),
+			WithBaseURL(srv.URL),
+			WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}),
+		)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		base, ok := client.baseTransport.(*http.Transport)
+		if !ok || base == http.DefaultTransport {
+			t.Fatalf("base transport = %T, want a clone of http.DefaultTransport", client.baseTransport)
+		}
+		if base.TLSClientConfig == nil || base.TLSClientConfig.RootCAs != pool {
+			t.Errorf("TLSClientConfig = %+v, want the given RootCAs", base.TLSClientConfig)
+		}
+		// Clone sets up HTTP/2 on the original, which may give it a
+		// TLSClientConfig of its own, but never ours.
+		if c := http.DefaultTransport.(*http.Transport).TLSClientConfig; c != nil && c.RootCAs == pool {
+			t.Error("http.DefaultTransport was modified")
+		}
+
+		if _, err := client.Assets.Get(context.Background(), "asset-123"); err != nil {
+			t.Errorf("request with trusted CA failed: %v", err)
+		}
+	})
+
+	t.Run("insecure skip verify on the HTTP client's transport", func(t *testing.T) {
+		own := &http.Transport{MaxIdleConns: 7}
+		client, err := NewClient(
+			WithOrganizationKey("key"),
+			WithBaseURL(srv.URL),
+			WithHTTPClient(&http.Client{Transport: own}),
+			WithTLSConfig(&tls.Config{InsecureSkipVerify: true}), //nolint:gosec // test server has a self-signed certificate.
+		)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		base := client.baseTransport.(*http.Transport)
+		if base == own || base.MaxIdleConns != 7 {
+			t.Errorf("base transport is not a clone of the client's transport")
+		}
+		if !base.TLSClientConfig.InsecureSkipVerify {
+			t.Error("InsecureSkipVerify not set on the effective transport")
+		}
+		if own.TLSClientConfig != nil && own.TLSClientConfig.InsecureSkipVerify {
+			t.Error("caller's transport was modified")
+		}
+
+		if _, err := client.Assets.Get(context.Background(), "asset-123"); err != nil {
+			t.Errorf("request failed: %v", err)
+		}
+	})
+
+	t.Run("untrusted without config", func(t *testing.T) {
+		client, err := NewClient(WithOrganizationKey("key"), WithBaseURL(srv.URL))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		if _, err := client.Assets.Get(context.Background(), "asset-123"); err == nil {
+			t.Error("expected certificate error")
+		}
+	})
+
+	t.Run("non-http.Transport base", func(t *testing.T) {
+		_, err := NewClient(
+			WithOrganizationKey("key"),
+			WithTransport(NewHandlerTransport(http.NotFoundHandler())),
+			WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+		)
+		if err == nil || !strings.Contains(err.Error(), "WithTLSConfig") {
+			t.Errorf("NewClient() error = %v, want WithTLSConfig error", err)
+		}
+	})
+}
@@ -179,11 +179,31 @@ type AssessmentListItem struct {
 	UpdatedAt time.Time       `json:"updatedAt"`
 }
 
+// AssessmentEventType identifies the kind of an AssessmentEvent.
+type AssessmentEventType string
+
+// Possible values for AssessmentEventType.
+const (
+	AssessmentEventTypePaused     AssessmentEventType = "paused"
+	AssessmentEventTypeAutoPaused AssessmentEventType = "auto-paused"
+	AssessmentEventTypeResumed    AssessmentEventType = "resumed"
+)
+
 // AssessmentEvent represents an event in an assessment's history.
 type AssessmentEvent struct {
-	Name      string    `json:"name"`
+	// Name is the event name as sent by the API. It is kept as a string so
+	// events added to the API later are not lost.
+	Name string `json:"name"`
+
+	// Type is Name as an AssessmentEventType. Check Type.IsValid before
+	// relying on it for events the SDK may not know.
+	Type AssessmentEventType `json:"-"`
+
 	Timestamp time.Time `json:"timestamp"`
-	Reason    string    `json:"reason,omitempty"`
+
+	// Reason says why the assessment was paused. It is set only on
+	// auto-paused events.
+	Reason string `json:"reason,omitempty"`
 }
 
 // FindingSeverity represents the severity level of a finding.
@@ -280,6 +300,27 @@ type OrganizationAPIKey struct {
 	UpdatedAt time.Time  `json:"updatedAt"`
 }
 
+// OrganizationAPIKeyListItem represents an API key without its secret, for
+// use wherever keys are listed or reported rather than just created.
+type OrganizationAPIKeyListItem struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// ListItem returns k without its secret key.
+func (k OrganizationAPIKey) ListItem() OrganizationAPIKeyListItem {
+	return OrganizationAPIKeyListItem{
+		ID:        k.ID,
+		Name:      k.Name,
+		ExpiresAt: k.ExpiresAt,
+		CreatedAt: k.CreatedAt,
+		UpdatedAt: k.UpdatedAt,
+	}
+}
+
 // ReportListItem represents a report in list responses.
 type ReportListItem struct {
 	ID        string    `json:"id"`
@@ -0,0 +1,254 @@
+package xbow
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rsclarke/xbow/internal/api"
+)
+
+// WebhookEvent is a webhook payload delivered to a subscription's target URL.
+type WebhookEvent interface {
+	// EventType returns the type discriminator of the event.
+	EventType() WebhookEventType
+}
+
+// RawWebhookEvent is a webhook event whose type the SDK does not know, so it
+// has not been decoded into a more specific type. Raw holds the complete JSON
+// payload as received.
+type RawWebhookEvent struct {
+	EventID    string            `json:"eventId"`
+	Type       WebhookEventType  `json:"type"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+	Raw        json.RawMessage   `json:"-"`
+}
+
+// EventType implements WebhookEvent.
+func (e *RawWebhookEvent) EventType() WebhookEventType {
+	return e.Type
+}
+
+// webhookEnvelope holds the fields shared by every webhook payload.
+// APIVersion is optional; payloads that omit it are decoded as the
+// current API version.
+type webhookEnvelope struct {
+	EventID    string            `json:"eventId"`
+	Type       WebhookEventType  `json:"type"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+}
+
+// webhookDecoder decodes a payload for a specific webhook API version.
+type webhookDecoder func(env webhookEnvelope, body []byte) (WebhookEvent, error)
+
+// webhookDecoders maps each supported webhook API version to its decoder.
+var webhookDecoders = map[WebhookAPIVersion]webhookDecoder{
+	WebhookAPIVersionN20251101: decodeUndocumentedWebhookEvent,
+	WebhookAPIVersionN20260201: decodeWebhookEventV20260201,
+	WebhookAPIVersionNext:      decodeUndocumentedWebhookEvent,
+	WebhookAPIVersionUnstable:  decodeUndocumentedWebhookEvent,
+}
+
+// ParseWebhookEvent decodes a webhook request body into a WebhookEvent.
+//
+// The payload's apiVersion selects the decoder. Payloads without an
+// apiVersion are decoded as the current version (APIVersion). Versions the
+// SDK does not understand return an error matching ErrUnsupportedWebhookVersion.
+//
+// Known event types are returned as their concrete types, such as
+// *AssessmentChangedEvent; unknown types are returned as *RawWebhookEvent.
+//
+// Call this only after the request has been verified with a WebhookVerifier.
+func ParseWebhookEvent(body []byte) (WebhookEvent, error) {
+	var env webhookEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, &Error{Code: "ERR_INVALID_PAYLOAD", Message: "failed to decode webhook payload: " + err.Error()}
+	}
+	if env.Type == "" {
+		return nil, &Error{Code: "ERR_INVALID_PAYLOAD", Message: "webhook payload is missing type"}
+	}
+
+	version := env.APIVersion
+	if version == "" {
+		version = WebhookAPIVersion(APIVersion)
+	}
+
+	decode, ok := webhookDecoders[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedWebhookVersion, version)
+	}
+
+	env.APIVersion = version
+	return decode(env, body)
+}
+
+// PingEvent is sent to check that a webhook URL is reachable and that
+// signature verification works.
+type PingEvent struct {
+	EventID    string            `json:"eventId"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+}
+
+// EventType implements WebhookEvent.
+func (e *PingEvent) EventType() WebhookEventType {
+	return WebhookEventTypePing
+}
+
+// AssetChangedEvent is sent when the check state of an asset field changes.
+type AssetChangedEvent struct {
+	EventID    string            `json:"eventId"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+	Asset      *Asset            `json:"asset"`
+}
+
+// EventType implements WebhookEvent.
+func (e *AssetChangedEvent) EventType() WebhookEventType {
+	return WebhookEventTypeAssetChanged
+}
+
+// AssessmentChangedEvent is sent when the state of an assessment changes.
+type AssessmentChangedEvent struct {
+	EventID    string            `json:"eventId"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+	Assessment *Assessment       `json:"assessment"`
+}
+
+// EventType implements WebhookEvent.
+func (e *AssessmentChangedEvent) EventType() WebhookEventType {
+	return WebhookEventTypeAssessmentChanged
+}
+
+// FindingChangedEvent is sent when the state of a finding changes.
+type FindingChangedEvent struct {
+	EventID    string            `json:"eventId"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+	Finding    *Finding          `json:"finding"`
+}
+
+// EventType implements WebhookEvent.
+func (e *FindingChangedEvent) EventType() WebhookEventType {
+	return WebhookEventTypeFindingChanged
+}
+
+// TargetChangedEvent is sent when a target changes. The API does not yet
+// document the target payload, so it is kept as raw JSON.
+type TargetChangedEvent struct {
+	EventID    string            `json:"eventId"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+	Target     json.RawMessage   `json:"target"`
+}
+
+// EventType implements WebhookEvent.
+func (e *TargetChangedEvent) EventType() WebhookEventType {
+	return WebhookEventTypeTargetChanged
+}
+
+// ChallengeChangedEvent is sent when a finding challenge changes. The API
+// does not yet document the challenge payload, so it is kept as raw JSON.
+type ChallengeChangedEvent struct {
+	EventID    string            `json:"eventId"`
+	APIVersion WebhookAPIVersion `json:"apiVersion"`
+	Challenge  json.RawMessage   `json:"challenge"`
+}
+
+// EventType implements WebhookEvent.
+func (e *ChallengeChangedEvent) EventType() WebhookEventType {
+	return WebhookEventTypeChallengeChanged
+}
+
+// decodeWebhookEventV20260201 decodes payloads sent with the 2026-02-01 API
+// version. Resource payloads have the same shape as the matching Get
+// response, so they are decoded through the generated types and converted
+// like any other response. Unknown event types are returned as
+// *RawWebhookEvent.
+func decodeWebhookEventV20260201(env webhookEnvelope, body []byte) (WebhookEvent, error) {
+	switch env.Type {
+	case WebhookEventTypePing:
+		return &PingEvent{EventID: env.EventID, APIVersion: env.APIVersion}, nil
+
+	case WebhookEventTypeAssetChanged:
+		var p struct {
+			Asset *api.GetAPIV1AssetsAssetIDResponse `json:"asset"`
+		}
+		if err := decodeWebhookPayload(body, &p); err != nil {
+			return nil, err
+		}
+		ev := &AssetChangedEvent{EventID: env.EventID, APIVersion: env.APIVersion}
+		if p.Asset != nil {
+			ev.Asset = assetFromGetResponse(p.Asset)
+		}
+		return ev, nil
+
+	case WebhookEventTypeAssessmentChanged:
+		var p struct {
+			Assessment *api.GetAPIV1AssessmentsAssessmentIDResponse `json:"assessment"`
+		}
+		if err := decodeWebhookPayload(body, &p); err != nil {
+			return nil, err
+		}
+		ev := &AssessmentChangedEvent{EventID: env.EventID, APIVersion: env.APIVersion}
+		if p.Assessment != nil {
+			ev.Assessment = assessmentFromGetResponse(p.Assessment)
+		}
+		return ev, nil
+
+	case WebhookEventTypeFindingChanged:
+		var p struct {
+			Finding *api.GetAPIV1FindingsFindingIDResponse `json:"finding"`
+		}
+		if err := decodeWebhookPayload(body, &p); err != nil {
+			return nil, err
+		}
+		ev := &FindingChangedEvent{EventID: env.EventID, APIVersion: env.APIVersion}
+		if p.Finding != nil {
+			ev.Finding = findingFromGetResponse(p.Finding)
+		}
+		return ev, nil
+
+	case WebhookEventTypeTargetChanged:
+		ev := &TargetChangedEvent{}
+		if err := decodeWebhookPayload(body, ev); err != nil {
+			return nil, err
+		}
+		ev.APIVersion = env.APIVersion
+		return ev, nil
+
+	case WebhookEventTypeChallengeChanged:
+		ev := &ChallengeChangedEvent{}
+		if err := decodeWebhookPayload(body, ev); err != nil {
+			return nil, err
+		}
+		ev.APIVersion = env.APIVersion
+		return ev, nil
+	}
+
+	return &RawWebhookEvent{
+		EventID:    env.EventID,
+		Type:       env.Type,
+		APIVersion: env.APIVersion,
+		Raw:        json.RawMessage(body),
+	}, nil
+}
+
+// decodeUndocumentedWebhookEvent decodes payloads sent with API versions
+// that webhooks can be created with but whose resource payloads the spec
+// does not describe: 2025-11-01, next and unstable. Only ping events are
+// decoded; every other event is returned as *RawWebhookEvent rather than
+// guessed at.
+func decodeUndocumentedWebhookEvent(env webhookEnvelope, body []byte) (WebhookEvent, error) {
+	if env.Type == WebhookEventTypePing {
+		return &PingEvent{EventID: env.EventID, APIVersion: env.APIVersion}, nil
+	}
+	return &RawWebhookEvent{
+		EventID:    env.EventID,
+		Type:       env.Type,
+		APIVersion: env.APIVersion,
+		Raw:        json.RawMessage(body),
+	}, nil
+}
+
+func decodeWebhookPayload(body []byte, v any) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return &Error{Code: "ERR_INVALID_PAYLOAD", Message: "failed to decode webhook payload: " + err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package xbow
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	t.Run("decodes current version payload", func(t *testing.T) {
+		body := []byte(`{"eventId":"evt-1","type":"ping","apiVersion":"2026-02-01"}`)
+
+		ev, err := ParseWebhookEvent(body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ev.EventType() != WebhookEventTypePing {
+			t.Errorf("EventType() = %q, want %q", ev.EventType(), WebhookEventTypePing)
+		}
+		ping, ok := ev.(*PingEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *PingEvent", ev)
+		}
+		if ping.EventID != "evt-1" {
+			t.Errorf("EventID = %q, want 'evt-1'", ping.EventID)
+		}
+		if ping.APIVersion != WebhookAPIVersionN20260201 {
+			t.Errorf("APIVersion = %q, want %q", ping.APIVersion, WebhookAPIVersionN20260201)
+		}
+	})
+
+	t.Run("defaults to current version when absent", func(t *testing.T) {
+		ev, err := ParseWebhookEvent([]byte(`{"eventId":"evt-2","type":"ping"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ping, ok := ev.(*PingEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *PingEvent", ev)
+		}
+		if ping.APIVersion != WebhookAPIVersion(APIVersion) {
+			t.Errorf("APIVersion = %q, want %q", ping.APIVersion, APIVersion)
+		}
+	})
+
+	t.Run("2025-11-01 payloads are kept raw", func(t *testing.T) {
+		ev, err := ParseWebhookEvent([]byte(`{"eventId":"evt-5","type":"ping","apiVersion":"2025-11-01"}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := ev.(*PingEvent); !ok {
+			t.Errorf("event type = %T, want *PingEvent", ev)
+		}
+
+		body := []byte(`{"eventId":"evt-6","type":"asset.changed","apiVersion":"2025-11-01","asset":{"id":"a"}}`)
+		ev, err = ParseWebhookEvent(body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		raw, ok := ev.(*RawWebhookEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *RawWebhookEvent", ev)
+		}
+		if raw.Type != WebhookEventTypeAssetChanged || string(raw.Raw) != string(body) {
+			t.Errorf("got type %q, raw %s", raw.Type, raw.Raw)
+		}
+	})
+
+	t.Run("every creatable version has a decoder", func(t *testing.T) {
+		for _, v := range WebhookAPIVersionValues() {
+			if _, ok := webhookDecoders[v]; !ok {
+				t.Errorf("no decoder for %q", v)
+			}
+		}
+	})
+
+	t.Run("rejects unknown version", func(t *testing.T) {
+		_, err := ParseWebhookEvent([]byte(`{"eventId":"evt-3","type":"ping","apiVersion":"1999-01-01"}`))
+		if !errors.Is(err, ErrUnsupportedWebhookVersion) {
+			t.Errorf("err = %v, want ErrUnsupportedWebhookVersion", err)
+		}
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		_, err := ParseWebhookEvent([]byte(`not json`))
+		var xerr *Error
+		if !errors.As(err, &xerr) || xerr.Code != "ERR_INVALID_PAYLOAD" {
+			t.Errorf("expected ERR_INVALID_PAYLOAD, got %v", err)
+		}
+	})
+
+	t.Run("rejects missing type", func(t *testing.T) {
+		_, err := ParseWebhookEvent([]byte(`{"eventId":"evt-4"}`))
+		var xerr *Error
+		if !errors.As(err, &xerr) || xerr.Code != "ERR_INVALID_PAYLOAD" {
+			t.Errorf("expected ERR_INVALID_PAYLOAD, got %v", err)
+		}
+	})
+}
+
+func TestParseWebhookEvent_Types(t *testing.T) {
+	t.Run("asset.changed", func(t *testing.T) {
+		body := []byte(`{"eventId":"evt-a","type":"asset.changed","asset":` + testAssetJSON + `}`)
+		ev, err := ParseWebhookEvent(body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, ok := ev.(*AssetChangedEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *AssetChangedEvent", ev)
+		}
+		if got.EventID != "evt-a" {
+			t.Errorf("EventID = %q, want 'evt-a'", got.EventID)
+		}
+		if got.Asset == nil {
+			t.Fatal("Asset is nil")
+		}
+		if got.Asset.ID != "asset-123" {
+			t.Errorf("Asset.ID = %q, want 'asset-123'", got.Asset.ID)
+		}
+		if got.Asset.MaxRequestsPerSecond == nil || *got.Asset.MaxRequestsPerSecond != 5 {
+			t.Errorf("Asset.MaxRequestsPerSecond = %v, want 5", got.Asset.MaxRequestsPerSecond)
+		}
+	})
+
+	t.Run("assessment.changed", func(t *testing.T) {
+		body := []byte(`{"eventId":"evt-b","type":"assessment.changed","assessment":` + testAssessmentJSON(AssessmentStateRunning) + `}`)
+		ev, err := ParseWebhookEvent(body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, ok := ev.(*AssessmentChangedEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *AssessmentChangedEvent", ev)
+		}
+		if got.Assessment == nil {
+			t.Fatal("Assessment is nil")
+		}
+		if got.Assessment.ID != "assess-123" {
+			t.Errorf("Assessment.ID = %q, want 'assess-123'", got.Assessment.ID)
+		}
+		if got.Assessment.State != AssessmentStateRunning {
+			t.Errorf("Assessment.State = %q, want %q", got.Assessment.State, AssessmentStateRunning)
+		}
+	})
+
+	t.Run("finding.changed", func(t *testing.T) {
+		body := []byte(`{"eventId":"evt-c","type":"finding.changed","finding":{
+			"id": "finding-123",
+			"name": "SQL Injection",
+			"severity": "critical",
+			"state": "confirmed",
+			"summary": "s",
+			"impact": "i",
+			"mitigations": "m",
+			"recipe": "r",
+			"evidence": "e",
+			"createdAt": "2026-01-01T00:00:00Z",
+			"updatedAt": "2026-01-01T00:00:00Z"
+		}}`)
+		ev, err := ParseWebhookEvent(body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, ok := ev.(*FindingChangedEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *FindingChangedEvent", ev)
+		}
+		if got.Finding == nil {
+			t.Fatal("Finding is nil")
+		}
+		if got.Finding.Severity != FindingSeverityCritical {
+			t.Errorf("Finding.Severity = %q, want %q", got.Finding.Severity, FindingSeverityCritical)
+		}
+		if got.Finding.State != FindingStateConfirmed {
+			t.Errorf("Finding.State = %q, want %q", got.Finding.State, FindingStateConfirmed)
+		}
+	})
+
+	t.Run("target.changed", func(t *testing.T) {
+		ev, err := ParseWebhookEvent([]byte(`{"eventId":"evt-d","type":"target.changed","target":{"id":"t-1"}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, ok := ev.(*TargetChangedEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *TargetChangedEvent", ev)
+		}
+		if string(got.Target) != `{"id":"t-1"}` {
+			t.Errorf("Target = %s, want {\"id\":\"t-1\"}", got.Target)
+		}
+	})
+
+	t.Run("challenge.changed", func(t *testing.T) {
+		ev, err := ParseWebhookEvent([]byte(`{"eventId":"evt-e","type":"challenge.changed","challenge":{"id":"c-1"}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, ok := ev.(*ChallengeChangedEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *ChallengeChangedEvent", ev)
+		}
+		if got.EventID != "evt-e" {
+			t.Errorf("EventID = %q, want 'evt-e'", got.EventID)
+		}
+		var challenge map[string]string
+		if err := json.Unmarshal(got.Challenge, &challenge); err != nil || challenge["id"] != "c-1" {
+			t.Errorf("Challenge = %s, want id c-1", got.Challenge)
+		}
+	})
+
+	t.Run("unknown type is returned raw", func(t *testing.T) {
+		body := []byte(`{"eventId":"evt-f","type":"widget.changed","widget":{}}`)
+		ev, err := ParseWebhookEvent(body)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		raw, ok := ev.(*RawWebhookEvent)
+		if !ok {
+			t.Fatalf("event type = %T, want *RawWebhookEvent", ev)
+		}
+		if raw.EventType() != "widget.changed" {
+			t.Errorf("EventType() = %q, want 'widget.changed'", raw.EventType())
+		}
+		if string(raw.Raw) != string(body) {
+			t.Errorf("Raw = %s, want %s", raw.Raw, body)
+		}
+	})
+
+	t.Run("malformed resource", func(t *testing.T) {
+		_, err := ParseWebhookEvent([]byte(`{"type":"finding.changed","finding":"nope"}`))
+		var xerr *Error
+		if !errors.As(err, &xerr) || xerr.Code != "ERR_INVALID_PAYLOAD" {
+			t.Errorf("expected ERR_INVALID_PAYLOAD, got %v", err)
+		}
+	})
+}
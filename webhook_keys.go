@@ -0,0 +1,153 @@
+package xbow
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultKeyRefreshInterval = time.Hour
+
+	// minForcedKeyRefresh bounds how often a failed verification may
+	// refetch keys, so a stream of forged requests cannot turn into a
+	// stream of API calls.
+	minForcedKeyRefresh = time.Minute
+
+	// keyRefreshTimeout bounds each fetch of the signing keys.
+	keyRefreshTimeout = 30 * time.Second
+)
+
+// WithKeyRefreshInterval sets how often a verifier created with
+// NewWebhookVerifierFromClient refetches its signing keys. Default is one
+// hour; zero or less disables periodic refresh, leaving only the refresh on
+// a failed verification. It has no effect on NewWebhookVerifier.
+func WithKeyRefreshInterval(d time.Duration) WebhookVerifierOption {
+	return func(v *WebhookVerifier) {
+		v.refreshInterval = d
+	}
+}
+
+// NewWebhookVerifierFromClient creates a WebhookVerifier that fetches its
+// keys with c.Meta.GetWebhookSigningKeys and keeps them current as XBOW
+// rotates them.
+//
+// Keys are refetched when they are older than the refresh interval (see
+// WithKeyRefreshInterval), and when a signature fails to verify against
+// every cached key. In the second case the request is checked once more
+// against the fresh keys before it is rejected. Failure-driven refreshes
+// happen at most once a minute. If a refresh fails, the cached keys stay in
+// use and the failure is logged to the WithVerifierLogger logger. Refreshing
+// stops, and the cached keys stay in use, once c is closed.
+//
+// Example:
+//
+//	verifier, err := xbow.NewWebhookVerifierFromClient(ctx, client)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	http.Handle("/webhook", verifier.Middleware(myHandler))
+func NewWebhookVerifierFromClient(ctx context.Context, c *Client, opts ...WebhookVerifierOption) (*WebhookVerifier, error) {
+	v, err := newRefreshingVerifier(ctx, c.Meta.GetWebhookSigningKeys, opts...)
+	if err != nil {
+		return nil, err
+	}
+	v.refreshCtx = c.closed
+	return v, nil
+}
+
+// newRefreshingVerifier creates a verifier whose keys come from source.
+func newRefreshingVerifier(ctx context.Context, source func(context.Context) ([]WebhookSigningKey, error), opts ...WebhookVerifierOption) (*WebhookVerifier, error) {
+	keys, err := source(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := NewWebhookVerifier(keys, opts...)
+	if err != nil {
+		return nil, err
+	}
+	v.keySource = source
+	v.refreshCtx = context.Background()
+	v.lastRefresh.Store(v.now().UnixNano())
+	return v, nil
+}
+
+// currentKeys returns the keys to verify with, first refreshing them if
+// they are older than the refresh interval. While another goroutine is
+// refreshing, the cached keys are returned rather than waiting.
+func (v *WebhookVerifier) currentKeys() *verifierKeys {
+	keys := v.keys.Load()
+	if !v.refreshes() || v.refreshInterval <= 0 || !v.olderThan(v.refreshInterval) {
+		return keys
+	}
+	if !v.refreshMu.TryLock() {
+		return keys
+	}
+	defer v.refreshMu.Unlock()
+
+	if v.olderThan(v.refreshInterval) {
+		if fresh := v.refresh(); fresh != nil {
+			return fresh
+		}
+	}
+	return v.keys.Load()
+}
+
+// forceRefresh refetches the keys after stale failed to verify a
+// signature. It returns the keys to retry with, or nil if there is nothing
+// new to try: the verifier has no key source or has been stopped, a refresh
+// happened too recently, or the fetch failed.
+func (v *WebhookVerifier) forceRefresh(stale *verifierKeys) *verifierKeys {
+	if !v.refreshes() {
+		return nil
+	}
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+
+	// Another request already replaced the keys while we waited.
+	if keys := v.keys.Load(); keys != stale {
+		return keys
+	}
+	if !v.olderThan(minForcedKeyRefresh) {
+		return nil
+	}
+	return v.refresh()
+}
+
+// refreshes reports whether v fetches its own keys and has not been
+// stopped by closing its client.
+func (v *WebhookVerifier) refreshes() bool {
+	return v.keySource != nil && v.refreshCtx.Err() == nil
+}
+
+// olderThan reports whether the last fetch attempt was at least d ago.
+func (v *WebhookVerifier) olderThan(d time.Duration) bool {
+	return v.now().Sub(time.Unix(0, v.lastRefresh.Load())) >= d
+}
+
+// refresh fetches and installs new keys. It must be called with refreshMu
+// held, and returns nil if the fetch fails.
+func (v *WebhookVerifier) refresh() *verifierKeys {
+	v.lastRefresh.Store(v.now().UnixNano())
+
+	ctx, cancel := context.WithTimeout(v.refreshCtx, keyRefreshTimeout)
+	defer cancel()
+
+	keys, err := v.keySource(ctx)
+	var fresh *verifierKeys
+	if err == nil {
+		fresh, err = newVerifierKeys(keys)
+	}
+	if err != nil {
+		if v.logger != nil && v.refreshCtx.Err() == nil {
+			v.logger.LogAttrs(ctx, slog.LevelWarn, "webhook signing key refresh failed",
+				slog.String("error", err.Error()),
+			)
+		}
+		return nil
+	}
+
+	v.keys.Store(fresh)
+	return fresh
+}
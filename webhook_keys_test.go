@@ -0,0 +1,366 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeKeySource serves a mutable key set and counts fetches, simulating
+// XBOW rotating its signing keys.
+type fakeKeySource struct {
+	mu    sync.Mutex
+	keys  []WebhookSigningKey
+	err   error
+	calls int
+}
+
+func (f *fakeKeySource) fetch(context.Context) ([]WebhookSigningKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.keys, nil
+}
+
+func (f *fakeKeySource) rotate(keys ...WebhookSigningKey) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = keys
+}
+
+func (f *fakeKeySource) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeKeySource) fetches() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// fakeClock is a manually advanced time source for verifier refresh tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newFakeRefreshingVerifier(t *testing.T, src *fakeKeySource, opts ...WebhookVerifierOption) (*WebhookVerifier, *fakeClock) {
+	t.Helper()
+
+	clock := &fakeClock{now: time.Now()}
+	opts = append(opts, func(v *WebhookVerifier) { v.now = clock.Now })
+	v, err := newRefreshingVerifier(context.Background(), src.fetch, opts...)
+	if err != nil {
+		t.Fatalf("newRefreshingVerifier() error = %v", err)
+	}
+	return v, clock
+}
+
+// verifySigned signs body with priv at the verifier's current time and
+// verifies it.
+func verifySigned(v *WebhookVerifier, priv []byte, body []byte) error {
+	ts := strconv.FormatInt(v.now().Unix(), 10)
+	return v.VerifyBytes(ts, signRequest(priv, ts, body), body)
+}
+
+func TestWebhookVerifier_ForcedRefreshOnRotation(t *testing.T) {
+	oldPriv, oldB64 := generateTestKey(t)
+	newPriv, newB64 := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	src := &fakeKeySource{keys: []WebhookSigningKey{{PublicKey: oldB64}}}
+	v, clock := newFakeRefreshingVerifier(t, src)
+
+	if err := verifySigned(v, oldPriv, body); err != nil {
+		t.Fatalf("old key: unexpected error: %v", err)
+	}
+	if got := src.fetches(); got != 1 {
+		t.Fatalf("fetches = %d, want 1", got)
+	}
+
+	src.rotate(WebhookSigningKey{PublicKey: newB64})
+	clock.Advance(minForcedKeyRefresh)
+
+	if err := verifySigned(v, newPriv, body); err != nil {
+		t.Fatalf("rotated key: unexpected error: %v", err)
+	}
+	if got := src.fetches(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+
+	// The old key was retired by the rotation.
+	clock.Advance(minForcedKeyRefresh)
+	var apiErr *Error
+	if err := verifySigned(v, oldPriv, body); !errors.As(err, &apiErr) || apiErr.Code != "ERR_SIGNATURE_INVALID" {
+		t.Errorf("retired key: error = %v, want ERR_SIGNATURE_INVALID", err)
+	}
+}
+
+func TestWebhookVerifier_ForcedRefreshThrottled(t *testing.T) {
+	_, b64 := generateTestKey(t)
+	forger, _ := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	src := &fakeKeySource{keys: []WebhookSigningKey{{PublicKey: b64}}}
+	v, clock := newFakeRefreshingVerifier(t, src)
+
+	// Immediately after construction the keys are fresh, so a bad
+	// signature is rejected without a fetch.
+	for range 5 {
+		if err := verifySigned(v, forger, body); err == nil {
+			t.Fatal("expected error for forged signature")
+		}
+	}
+	if got := src.fetches(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+
+	clock.Advance(minForcedKeyRefresh)
+	for range 5 {
+		if err := verifySigned(v, forger, body); err == nil {
+			t.Fatal("expected error for forged signature")
+		}
+	}
+	if got := src.fetches(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+}
+
+func TestWebhookVerifier_IntervalRefresh(t *testing.T) {
+	_, oldB64 := generateTestKey(t)
+	newPriv, newB64 := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	src := &fakeKeySource{keys: []WebhookSigningKey{{PublicKey: oldB64}}}
+	v, clock := newFakeRefreshingVerifier(t, src, WithKeyRefreshInterval(10*time.Minute))
+
+	src.rotate(WebhookSigningKey{PublicKey: newB64})
+	clock.Advance(10 * time.Minute)
+
+	// The stale keys are replaced before verifying, so no forced refresh
+	// is needed.
+	if err := verifySigned(v, newPriv, body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := src.fetches(); got != 2 {
+		t.Errorf("fetches = %d, want 2", got)
+	}
+	if got := v.keys.Load().publicKeys; len(got) != 1 {
+		t.Errorf("len(publicKeys) = %d, want 1", len(got))
+	}
+}
+
+func TestWebhookVerifier_IntervalRefreshDisabled(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	src := &fakeKeySource{keys: []WebhookSigningKey{{PublicKey: b64}}}
+	v, clock := newFakeRefreshingVerifier(t, src, WithKeyRefreshInterval(0))
+
+	clock.Advance(24 * time.Hour)
+	if err := verifySigned(v, priv, body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := src.fetches(); got != 1 {
+		t.Errorf("fetches = %d, want 1", got)
+	}
+}
+
+func TestWebhookVerifier_RefreshErrorKeepsKeys(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	src := &fakeKeySource{keys: []WebhookSigningKey{{PublicKey: b64}}}
+	v, clock := newFakeRefreshingVerifier(t, src)
+
+	t.Run("fetch error", func(t *testing.T) {
+		src.fail(errors.New("network down"))
+		clock.Advance(defaultKeyRefreshInterval)
+
+		if err := verifySigned(v, priv, body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("empty key set", func(t *testing.T) {
+		src.fail(nil)
+		src.rotate()
+		clock.Advance(defaultKeyRefreshInterval)
+
+		if err := verifySigned(v, priv, body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if got := src.fetches(); got != 3 {
+		t.Errorf("fetches = %d, want 3", got)
+	}
+}
+
+func TestNewWebhookVerifierFromClient(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	t.Run("fetches keys", func(t *testing.T) {
+		var calls int
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/meta/webhooks-signing-keys" {
+				t.Errorf("path = %q", r.URL.Path)
+			}
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"publicKey":"` + b64 + `"}]`))
+		}))
+
+		v, err := NewWebhookVerifierFromClient(context.Background(), c)
+		if err != nil {
+			t.Fatalf("NewWebhookVerifierFromClient() error = %v", err)
+		}
+		if err := verifySigned(v, priv, body); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"code":"ERR_INTERNAL","message":"boom"}`))
+		}))
+
+		if _, err := NewWebhookVerifierFromClient(context.Background(), c); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[]`))
+		}))
+
+		_, err := NewWebhookVerifierFromClient(context.Background(), c)
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "ERR_NO_KEYS" {
+			t.Errorf("error = %v, want ERR_NO_KEYS", err)
+		}
+	})
+}
+
+func TestNewWebhookVerifierFromClient_StopsOnClose(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	var (
+		mu      sync.Mutex
+		fetches int
+		block   bool
+	)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches++
+		wait := block
+		mu.Unlock()
+		if wait {
+			<-r.Context().Done()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"publicKey":"` + b64 + `"}]`))
+	}))
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return fetches
+	}
+
+	v, err := NewWebhookVerifierFromClient(context.Background(), client, WithKeyRefreshInterval(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("NewWebhookVerifierFromClient() error = %v", err)
+	}
+	if err := verifySigned(v, priv, body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := count(); got != 2 {
+		t.Fatalf("fetches = %d, want 2 (initial and periodic refresh)", got)
+	}
+
+	// A refresh that hangs is abandoned when the client is closed.
+	mu.Lock()
+	block = true
+	mu.Unlock()
+	done := make(chan error, 1)
+	go func() { done <- verifySigned(v, priv, body) }()
+	for count() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("verify during close: unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresh still running after Close")
+	}
+
+	// Closed: the cached keys keep working without further fetches.
+	if err := verifySigned(v, priv, body); err != nil {
+		t.Fatalf("after close: unexpected error: %v", err)
+	}
+	if got := count(); got != 3 {
+		t.Errorf("fetches after close = %d, want 3", got)
+	}
+}
+
+func TestWebhookVerifier_ClockSkewUsesClock(t *testing.T) {
+	priv, pubB64 := generateTestKey(t)
+	body := []byte(`{"event":"ping"}`)
+
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: pubB64}},
+		WithMaxClockSkew(time.Minute),
+		func(v *WebhookVerifier) { v.now = clock.Now },
+	)
+	if err != nil {
+		t.Fatalf("NewWebhookVerifier() error = %v", err)
+	}
+
+	ts := strconv.FormatInt(clock.Now().Unix(), 10)
+	sig := signRequest(priv, ts, body)
+	if err := v.VerifyBytes(ts, sig, body); err != nil {
+		t.Fatalf("at the clock's time: unexpected error: %v", err)
+	}
+
+	clock.Advance(time.Minute + time.Second)
+	var apiErr *Error
+	if err := v.VerifyBytes(ts, sig, body); !errors.As(err, &apiErr) || apiErr.Code != "ERR_TIMESTAMP_EXPIRED" {
+		t.Errorf("after the skew: error = %v, want ERR_TIMESTAMP_EXPIRED", err)
+	}
+}
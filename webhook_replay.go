@@ -0,0 +1,64 @@
+package xbow
+
+import (
+	"sync"
+	"time"
+)
+
+// ReplayCache records webhook deliveries that have already been verified.
+// Implementations must be safe for concurrent use. A shared store such as
+// Redis can be used to detect replays across several servers.
+type ReplayCache interface {
+	// Seen records id until exp and reports whether it was already recorded
+	// and not yet expired.
+	Seen(id string, exp time.Time) bool
+}
+
+// replaySweepInterval is how often MemoryReplayCache drops expired entries.
+const replaySweepInterval = time.Minute
+
+// MemoryReplayCache is an in-memory ReplayCache. Entries are kept until they
+// expire, so its size is bounded by the number of deliveries received within
+// the verifier's clock skew window.
+type MemoryReplayCache struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryReplayCache creates an empty MemoryReplayCache.
+//
+// Example:
+//
+//	verifier, err := xbow.NewWebhookVerifier(keys,
+//	    xbow.WithReplayCache(xbow.NewMemoryReplayCache()),
+//	)
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Seen implements ReplayCache.
+func (c *MemoryReplayCache) Seen(id string, exp time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.After(c.nextSweep) {
+		for k, e := range c.entries {
+			if now.After(e) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = now.Add(replaySweepInterval)
+	}
+
+	if e, ok := c.entries[id]; ok && !now.After(e) {
+		return true
+	}
+	c.entries[id] = exp
+	return false
+}
@@ -0,0 +1,138 @@
+package xbow
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMemoryReplayCache(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := NewMemoryReplayCache()
+	c.now = func() time.Time { return now }
+
+	if c.Seen("a", now.Add(time.Minute)) {
+		t.Error("first Seen(a) = true, want false")
+	}
+	if !c.Seen("a", now.Add(time.Minute)) {
+		t.Error("second Seen(a) = false, want true")
+	}
+	if c.Seen("b", now.Add(time.Minute)) {
+		t.Error("first Seen(b) = true, want false")
+	}
+
+	// After expiry the id is treated as new and expired entries are swept.
+	now = now.Add(2 * time.Minute)
+	if c.Seen("a", now.Add(time.Minute)) {
+		t.Error("Seen(a) after expiry = true, want false")
+	}
+	if got := len(c.entries); got != 1 {
+		t.Errorf("entries = %d after sweep, want 1", got)
+	}
+}
+
+func TestWebhookVerifier_ReplayCache(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}},
+		WithReplayCache(NewMemoryReplayCache()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	newRequest := func(body []byte) *http.Request {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+		req.Header.Set(HeaderSignatureTimestamp, timestamp)
+		req.Header.Set(HeaderSignatureEd25519, signRequest(priv, timestamp, body))
+		return req
+	}
+
+	assertReplay := func(t *testing.T, err error) {
+		t.Helper()
+		var xerr *Error
+		if !errors.As(err, &xerr) || xerr.Code != "ERR_REPLAY_DETECTED" {
+			t.Errorf("error = %v, want ERR_REPLAY_DETECTED", err)
+		}
+	}
+
+	t.Run("duplicate delivery is rejected", func(t *testing.T) {
+		req := newRequest([]byte(`{"type":"ping","n":1}`))
+		dup := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(`{"type":"ping","n":1}`)))
+		dup.Header = req.Header.Clone()
+
+		if err := v.Verify(req); err != nil {
+			t.Fatalf("first delivery: unexpected error: %v", err)
+		}
+		assertReplay(t, v.Verify(dup))
+	})
+
+	t.Run("fresh delivery passes", func(t *testing.T) {
+		if err := v.Verify(newRequest([]byte(`{"type":"ping","n":2}`))); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unsigned headers do not change the replay key", func(t *testing.T) {
+		body := []byte(`{"type":"ping","n":3}`)
+		req := newRequest(body)
+		req.Header.Set("X-Delivery-Id", "delivery-1")
+		if err := v.Verify(req); err != nil {
+			t.Fatalf("first delivery: unexpected error: %v", err)
+		}
+		dup := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+		dup.Header = req.Header.Clone()
+		dup.Header.Set("X-Delivery-Id", "delivery-2")
+		assertReplay(t, v.Verify(dup))
+	})
+
+	t.Run("invalid signature is not recorded", func(t *testing.T) {
+		body := []byte(`{"type":"ping","n":5}`)
+		req := newRequest(body)
+		req.Header.Set(HeaderSignatureEd25519, signRequest(priv, "0", body))
+		if err := v.Verify(req); err == nil {
+			t.Fatal("expected error for invalid signature")
+		}
+		if err := v.Verify(newRequest(body)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("VerifyBytes", func(t *testing.T) {
+		body := []byte(`{"type":"ping","n":6}`)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		sig := signRequest(priv, timestamp, body)
+		if err := v.VerifyBytes(timestamp, sig, body); err != nil {
+			t.Fatalf("first delivery: unexpected error: %v", err)
+		}
+		assertReplay(t, v.VerifyBytes(timestamp, sig, body))
+	})
+}
+
+func TestWebhookVerifier_ReplayCacheAcrossKeys(t *testing.T) {
+	oldPriv, oldB64 := generateTestKey(t)
+	newPriv, newB64 := generateTestKey(t)
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: oldB64}, {PublicKey: newB64}},
+		WithReplayCache(NewMemoryReplayCache()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	// During rotation a delivery may carry a signature from each key. Either
+	// one alone must not let the message through a second time.
+	body := []byte(`{"type":"ping"}`)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := v.VerifyBytes(timestamp, signRequest(oldPriv, timestamp, body), body); err != nil {
+		t.Fatalf("first delivery: unexpected error: %v", err)
+	}
+	err = v.VerifyBytes(timestamp, signRequest(newPriv, timestamp, body), body)
+	var xerr *Error
+	if !errors.As(err, &xerr) || xerr.Code != "ERR_REPLAY_DETECTED" {
+		t.Errorf("error = %v, want ERR_REPLAY_DETECTED", err)
+	}
+}
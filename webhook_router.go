@@ -0,0 +1,188 @@
+package xbow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// WebhookHandlerFunc handles one verified, decoded webhook event. The
+// context is the request's, and carries its correlation id (see
+// CorrelationIDFromContext).
+type WebhookHandlerFunc func(ctx context.Context, event WebhookEvent) error
+
+// WebhookRouter is an http.Handler that verifies webhook requests, decodes
+// them with ParseWebhookEvent and calls the handler registered for the
+// event's type.
+//
+// Responses are:
+//   - 204 No Content when the handler succeeds, or when no handler matches
+//     and no default is set, so the event is acknowledged and not redelivered.
+//   - 401 Unauthorized when verification fails.
+//   - 400 Bad Request when the payload cannot be decoded, or the handler
+//     returns an error matching ErrInvalidWebhookPayload or
+//     ErrUnsupportedWebhookVersion, so the delivery is not retried.
+//   - 405 Method Not Allowed for anything but POST.
+//   - 500 Internal Server Error when the handler returns any other error, so
+//     the sender retries the delivery.
+//
+// Example:
+//
+//	router := xbow.NewWebhookRouter(verifier)
+//	router.On(xbow.WebhookEventTypeFindingChanged, func(ctx context.Context, ev xbow.WebhookEvent) error {
+//	    finding := ev.(*xbow.FindingChangedEvent).Finding
+//	    return notify(ctx, finding)
+//	})
+//	http.Handle("/webhook", router)
+type WebhookRouter struct {
+	verifier *WebhookVerifier
+	handler  http.Handler
+
+	// okStatus is the status written when an event is handled.
+	okStatus int
+
+	mu       sync.RWMutex
+	handlers map[WebhookEventType]WebhookHandlerFunc
+	fallback WebhookHandlerFunc
+}
+
+// NewWebhookRouter returns a router that verifies requests with verifier.
+// It panics if verifier is nil.
+func NewWebhookRouter(verifier *WebhookVerifier) *WebhookRouter {
+	if verifier == nil {
+		panic("xbow: NewWebhookRouter requires a verifier")
+	}
+	r := &WebhookRouter{
+		verifier: verifier,
+		handlers: make(map[WebhookEventType]WebhookHandlerFunc),
+		okStatus: http.StatusNoContent,
+	}
+	r.handler = verifier.Middleware(http.HandlerFunc(r.dispatch))
+	return r
+}
+
+// On registers h for events of type t, replacing any handler already
+// registered for it. A handler for WebhookEventTypeAll receives every event
+// that has no handler for its own type.
+func (r *WebhookRouter) On(t WebhookEventType, h WebhookHandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[t] = h
+}
+
+// Default registers h for events with no handler of their own, including
+// types the SDK does not know (delivered as *RawWebhookEvent). A handler
+// registered with On for WebhookEventTypeAll takes precedence over it.
+func (r *WebhookRouter) Default(h WebhookHandlerFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = h
+}
+
+// ServeHTTP implements http.Handler.
+func (r *WebhookRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	r.handler.ServeHTTP(w, req)
+}
+
+// handlerFor returns the handler for t, preferring an exact registration
+// to the wildcard, or the default handler.
+func (r *WebhookRouter) handlerFor(t WebhookEventType) WebhookHandlerFunc {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if h, ok := r.handlers[t]; ok {
+		return h
+	}
+	for registered, h := range r.handlers {
+		if registered.Matches(t) {
+			return h
+		}
+	}
+	return r.fallback
+}
+
+// dispatch runs after Middleware has verified the request.
+func (r *WebhookRouter) dispatch(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	event, err := ParseWebhookEvent(body)
+	if err != nil {
+		r.log(req, slog.LevelWarn, "webhook payload rejected", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h := r.handlerFor(event.EventType())
+	if h == nil {
+		w.WriteHeader(r.okStatus)
+		return
+	}
+
+	if err := h(req.Context(), event); err != nil {
+		typ := slog.String("type", string(event.EventType()))
+		if errors.Is(err, ErrInvalidWebhookPayload) || errors.Is(err, ErrUnsupportedWebhookVersion) {
+			r.log(req, slog.LevelWarn, "webhook payload rejected by handler", err, typ)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.log(req, slog.LevelError, "webhook handler failed", err, typ)
+		http.Error(w, "webhook handler failed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(r.okStatus)
+}
+
+// HandleFunc returns an http.Handler that verifies webhook requests,
+// decodes them with ParseWebhookEvent and passes every event to h, so h
+// need not read or parse the body itself. It responds like a WebhookRouter
+// with h as its default handler, except that success is 200 OK:
+//   - 200 OK when h succeeds.
+//   - 400 Bad Request when the payload cannot be decoded, or h returns an
+//     error matching ErrInvalidWebhookPayload, so the delivery is not
+//     retried.
+//   - 500 Internal Server Error when h returns any other error, so the
+//     sender retries the delivery.
+//
+// Example:
+//
+//	http.Handle("/webhook", verifier.HandleFunc(func(ctx context.Context, ev xbow.WebhookEvent) error {
+//	    if ev, ok := ev.(*xbow.FindingChangedEvent); ok {
+//	        return notify(ctx, ev.Finding)
+//	    }
+//	    return nil
+//	}))
+func (v *WebhookVerifier) HandleFunc(h WebhookHandlerFunc) http.Handler {
+	r := NewWebhookRouter(v)
+	r.Default(h)
+	r.okStatus = http.StatusOK
+	return r
+}
+
+// log records err with the verifier's logger, if it has one.
+func (r *WebhookRouter) log(req *http.Request, level slog.Level, msg string, err error, attrs ...slog.Attr) {
+	if r.verifier.logger == nil {
+		return
+	}
+	code := ""
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		code = apiErr.Code
+	}
+	attrs = append(attrs,
+		slog.String("code", code),
+		slog.String("error", err.Error()),
+		slog.String("correlation_id", CorrelationIDFromContext(req.Context())),
+	)
+	r.verifier.logger.LogAttrs(req.Context(), level, msg, attrs...)
+}
@@ -0,0 +1,263 @@
+package xbow
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testFindingChangedPayload = `{"type":"finding.changed","finding":{"id":"finding-123","name":"SQL Injection","severity":"critical","state":"confirmed","summary":"s","impact":"i","mitigations":"m","recipe":"r","evidence":"e","createdAt":"2026-01-01T00:00:00Z","updatedAt":"2026-01-01T00:00:00Z"}}`
+
+// signedWebhookRequest builds a POST carrying body signed with priv.
+func signedWebhookRequest(priv ed25519.PrivateKey, body string) *http.Request {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(body)))
+	req.Header.Set(HeaderSignatureTimestamp, timestamp)
+	req.Header.Set(HeaderSignatureEd25519, signRequest(priv, timestamp, []byte(body)))
+	return req
+}
+
+func TestWebhookRouter(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	var got []WebhookEvent
+	router := NewWebhookRouter(v)
+	router.On(WebhookEventTypePing, func(ctx context.Context, ev WebhookEvent) error {
+		got = append(got, ev)
+		return nil
+	})
+	router.On(WebhookEventTypeFindingChanged, func(ctx context.Context, ev WebhookEvent) error {
+		got = append(got, ev)
+		if ev.(*FindingChangedEvent).Finding.ID == "fail" {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name     string
+		req      func() *http.Request
+		wantCode int
+		wantType WebhookEventType
+	}{
+		{
+			name:     "ping",
+			req:      func() *http.Request { return signedWebhookRequest(priv, `{"type":"ping"}`) },
+			wantCode: http.StatusNoContent,
+			wantType: WebhookEventTypePing,
+		},
+		{
+			name:     "finding.changed",
+			req:      func() *http.Request { return signedWebhookRequest(priv, testFindingChangedPayload) },
+			wantCode: http.StatusNoContent,
+			wantType: WebhookEventTypeFindingChanged,
+		},
+		{
+			name: "handler error",
+			req: func() *http.Request {
+				return signedWebhookRequest(priv, `{"type":"finding.changed","finding":{"id":"fail","name":"n","severity":"low","state":"open","summary":"","impact":"","mitigations":"","recipe":"","evidence":"","createdAt":"2026-01-01T00:00:00Z","updatedAt":"2026-01-01T00:00:00Z"}}`)
+			},
+			wantCode: http.StatusInternalServerError,
+			wantType: WebhookEventTypeFindingChanged,
+		},
+		{
+			name:     "unregistered type is acknowledged",
+			req:      func() *http.Request { return signedWebhookRequest(priv, `{"type":"target.changed","target":{}}`) },
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "malformed payload",
+			req:      func() *http.Request { return signedWebhookRequest(priv, `{"type":"finding.changed","finding":"nope"}`) },
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "bad signature",
+			req: func() *http.Request {
+				req := signedWebhookRequest(priv, `{"type":"ping"}`)
+				req.Header.Set(HeaderSignatureEd25519, signRequest(priv, "0", []byte(`{"type":"ping"}`)))
+				return req
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "wrong method",
+			req:      func() *http.Request { return httptest.NewRequest(http.MethodGet, "/webhook", nil) },
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, tt.req())
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (body %q)", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.wantType == "" {
+				if len(got) != 0 {
+					t.Errorf("handlers called with %v, want none", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].EventType() != tt.wantType {
+				t.Errorf("dispatched %v, want one %s event", got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestWebhookRouter_Default(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	var gotType WebhookEventType
+	var gotCorrelation string
+	router := NewWebhookRouter(v)
+	router.On(WebhookEventTypePing, func(ctx context.Context, ev WebhookEvent) error {
+		t.Error("ping handler should not be called")
+		return nil
+	})
+	router.Default(func(ctx context.Context, ev WebhookEvent) error {
+		gotType = ev.EventType()
+		gotCorrelation = CorrelationIDFromContext(ctx)
+		return nil
+	})
+
+	req := signedWebhookRequest(priv, `{"type":"something.new","data":1}`)
+	req.Header.Set(HeaderCorrelationID, "corr-1")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rr.Code)
+	}
+	if gotType != "something.new" {
+		t.Errorf("default handler got type %q, want something.new", gotType)
+	}
+	if gotCorrelation != "corr-1" {
+		t.Errorf("correlation id = %q, want corr-1", gotCorrelation)
+	}
+}
+
+func TestWebhookRouter_Wildcard(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	var exact, wildcard []WebhookEventType
+	router := NewWebhookRouter(v)
+	router.On(WebhookEventTypePing, func(ctx context.Context, ev WebhookEvent) error {
+		exact = append(exact, ev.EventType())
+		return nil
+	})
+	router.On(WebhookEventTypeAll, func(ctx context.Context, ev WebhookEvent) error {
+		wildcard = append(wildcard, ev.EventType())
+		return nil
+	})
+	router.Default(func(ctx context.Context, ev WebhookEvent) error {
+		t.Error("default handler should not be called")
+		return nil
+	})
+
+	for _, body := range []string{`{"type":"ping"}`, testFindingChangedPayload, `{"type":"something.new"}`} {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, signedWebhookRequest(priv, body))
+		if rr.Code != http.StatusNoContent {
+			t.Errorf("status = %d, want 204", rr.Code)
+		}
+	}
+
+	if len(exact) != 1 || exact[0] != WebhookEventTypePing {
+		t.Errorf("exact handler got %v, want [ping]", exact)
+	}
+	if len(wildcard) != 2 || wildcard[0] != WebhookEventTypeFindingChanged || wildcard[1] != "something.new" {
+		t.Errorf("wildcard handler got %v, want [finding.changed something.new]", wildcard)
+	}
+}
+
+func TestWebhookVerifier_HandleFunc(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	var got []WebhookEvent
+	handler := v.HandleFunc(func(ctx context.Context, ev WebhookEvent) error {
+		got = append(got, ev)
+		raw, ok := ev.(*RawWebhookEvent)
+		if !ok {
+			return nil
+		}
+		switch raw.Type {
+		case "custom.invalid":
+			return fmt.Errorf("%w: missing data", ErrInvalidWebhookPayload)
+		case "custom.failing":
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantType WebhookEventType
+	}{
+		{name: "ping", body: `{"type":"ping"}`, wantCode: http.StatusOK, wantType: WebhookEventTypePing},
+		{name: "parse failure", body: `{"type":"finding.changed","finding":"nope"}`, wantCode: http.StatusBadRequest},
+		{name: "handler rejects payload", body: `{"type":"custom.invalid"}`, wantCode: http.StatusBadRequest, wantType: "custom.invalid"},
+		{name: "handler error", body: `{"type":"custom.failing"}`, wantCode: http.StatusInternalServerError, wantType: "custom.failing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, signedWebhookRequest(priv, tt.body))
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (body %q)", rr.Code, tt.wantCode, rr.Body.String())
+			}
+			if tt.wantType == "" {
+				if len(got) != 0 {
+					t.Errorf("handler called with %v, want no call", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].EventType() != tt.wantType {
+				t.Errorf("dispatched %v, want one %s event", got, tt.wantType)
+			}
+		})
+	}
+
+	if _, err := ParseWebhookEvent([]byte("nope")); !errors.Is(err, ErrInvalidWebhookPayload) {
+		t.Errorf("ParseWebhookEvent() error = %v, want ErrInvalidWebhookPayload", err)
+	}
+}
+
+func TestNewWebhookRouter_NilVerifier(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for nil verifier")
+		}
+	}()
+	NewWebhookRouter(nil)
+}
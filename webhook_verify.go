@@ -2,13 +2,23 @@ package xbow
 
 import (
 	"bytes"
+	"context"
 	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,15 +27,75 @@ const (
 	HeaderSignatureTimestamp = "X-Signature-Timestamp"
 	// HeaderSignatureEd25519 is the header containing the hex-encoded Ed25519 signature.
 	HeaderSignatureEd25519 = "X-Signature-Ed25519"
+	// HeaderSignatureKeyID is an optional header naming the signing key. Its
+	// value is the key's base64 PublicKey; when it matches a configured key,
+	// that key is tried first.
+	HeaderSignatureKeyID = "X-Signature-Key-Id"
+	// HeaderCorrelationID is the header carrying the correlation id for a
+	// webhook request. Middleware reads it from the request when present and
+	// always echoes it on the response.
+	HeaderCorrelationID = "X-Correlation-ID"
 )
 
 const defaultMaxBodyBytes = 5 * 1024 * 1024 // 5 MB
 
 // WebhookVerifier verifies webhook signatures from XBOW.
 type WebhookVerifier struct {
-	publicKeys   []ed25519.PublicKey
+	keys         atomic.Pointer[verifierKeys]
 	maxClockSkew time.Duration
 	maxBodyBytes int64
+	logger       *slog.Logger
+	replayCache  ReplayCache
+
+	timestampHeader string
+	signatureHeader string
+
+	// keySource, when set, fetches the current signing keys. It is set by
+	// NewWebhookVerifierFromClient.
+	keySource       func(ctx context.Context) ([]WebhookSigningKey, error)
+	refreshInterval time.Duration
+	refreshCtx      context.Context // cancelled to stop refreshing
+	refreshMu       sync.Mutex
+	lastRefresh     atomic.Int64 // UnixNano of the last fetch attempt
+	now             func() time.Time
+
+	// verifyKey reports whether sig is a valid signature of message by pub.
+	// It is ed25519.Verify outside tests.
+	verifyKey func(pub ed25519.PublicKey, message, sig []byte) bool
+}
+
+// verifierKeys is one set of signing keys. A WebhookVerifier replaces the
+// whole set when it refreshes its keys.
+type verifierKeys struct {
+	publicKeys []ed25519.PublicKey
+	index      map[string]int
+
+	// lastKey is the index of the key that most recently verified a
+	// signature. It is tried before the others.
+	lastKey atomic.Int32
+}
+
+// newVerifierKeys parses keys into a verifierKeys.
+func newVerifierKeys(keys []WebhookSigningKey) (*verifierKeys, error) {
+	if len(keys) == 0 {
+		return nil, &Error{Code: "ERR_NO_KEYS", Message: "at least one signing key is required"}
+	}
+
+	ks := &verifierKeys{
+		publicKeys: make([]ed25519.PublicKey, 0, len(keys)),
+		index:      make(map[string]int, len(keys)),
+	}
+	for _, k := range keys {
+		pub, err := parsePublicKey(k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := ks.index[k.PublicKey]; !dup {
+			ks.index[k.PublicKey] = len(ks.publicKeys)
+		}
+		ks.publicKeys = append(ks.publicKeys, pub)
+	}
+	return ks, nil
 }
 
 // WebhookVerifierOption configures the WebhookVerifier.
@@ -47,6 +117,53 @@ func WithMaxBodyBytes(n int64) WebhookVerifierOption {
 	}
 }
 
+// WithSignatureHeaders overrides the names of the timestamp and signature
+// headers, for example when a proxy rewrites them. Empty names keep the
+// defaults, HeaderSignatureTimestamp and HeaderSignatureEd25519.
+func WithSignatureHeaders(timestampHeader, signatureHeader string) WebhookVerifierOption {
+	return func(v *WebhookVerifier) {
+		if timestampHeader != "" {
+			v.timestampHeader = timestampHeader
+		}
+		if signatureHeader != "" {
+			v.signatureHeader = signatureHeader
+		}
+	}
+}
+
+// WithSignatureHeader overrides the name of the signature header. It is
+// shorthand for WithSignatureHeaders("", name).
+func WithSignatureHeader(name string) WebhookVerifierOption {
+	return WithSignatureHeaders("", name)
+}
+
+// WithTimestampHeader overrides the name of the timestamp header. It is
+// shorthand for WithSignatureHeaders(name, "").
+func WithTimestampHeader(name string) WebhookVerifierOption {
+	return WithSignatureHeaders(name, "")
+}
+
+// WithReplayCache rejects webhooks that have already been verified within the
+// clock skew window, with error code ERR_REPLAY_DETECTED. A delivery is
+// identified by a digest of its signed timestamp and body, so resending it
+// with different unsigned headers, or with another valid signature over the
+// same message, is still caught. NewMemoryReplayCache provides an in-memory
+// implementation.
+func WithReplayCache(c ReplayCache) WebhookVerifierOption {
+	return func(v *WebhookVerifier) {
+		v.replayCache = c
+	}
+}
+
+// WithVerifierLogger sets a logger that Middleware uses to record rejected
+// requests. Each entry carries the failure code and correlation id; the body
+// and signature are never logged. By default nothing is logged.
+func WithVerifierLogger(l *slog.Logger) WebhookVerifierOption {
+	return func(v *WebhookVerifier) {
+		v.logger = l
+	}
+}
+
 // NewWebhookVerifier creates a new WebhookVerifier from the signing keys
 // returned by MetaService.GetWebhookSigningKeys.
 //
@@ -62,41 +179,64 @@ func WithMaxBodyBytes(n int64) WebhookVerifierOption {
 //	}
 //	http.Handle("/webhook", verifier.Middleware(myHandler))
 func NewWebhookVerifier(keys []WebhookSigningKey, opts ...WebhookVerifierOption) (*WebhookVerifier, error) {
-	if len(keys) == 0 {
-		return nil, &Error{Code: "ERR_NO_KEYS", Message: "at least one signing key is required"}
+	ks, err := newVerifierKeys(keys)
+	if err != nil {
+		return nil, err
 	}
 
 	v := &WebhookVerifier{
-		publicKeys:   make([]ed25519.PublicKey, 0, len(keys)),
-		maxClockSkew: 5 * time.Minute,
-		maxBodyBytes: defaultMaxBodyBytes,
+		maxClockSkew:    5 * time.Minute,
+		maxBodyBytes:    defaultMaxBodyBytes,
+		refreshInterval: defaultKeyRefreshInterval,
+		now:             time.Now,
+
+		timestampHeader: HeaderSignatureTimestamp,
+		signatureHeader: HeaderSignatureEd25519,
+		verifyKey:       ed25519.Verify,
 	}
 
 	for _, opt := range opts {
 		opt(v)
 	}
 
-	for _, k := range keys {
-		pub, err := parsePublicKey(k.PublicKey)
-		if err != nil {
-			return nil, err
-		}
-		v.publicKeys = append(v.publicKeys, pub)
-	}
-
+	v.keys.Store(ks)
 	return v, nil
 }
 
-// parsePublicKey decodes a base64-encoded SPKI public key.
-func parsePublicKey(b64 string) (ed25519.PublicKey, error) {
-	der, err := base64.StdEncoding.DecodeString(b64)
-	if err != nil {
-		return nil, &Error{Code: "ERR_INVALID_KEY", Message: "failed to decode base64 public key: " + err.Error()}
+// parsePublicKey decodes an Ed25519 public key given as a PEM "PUBLIC KEY"
+// block, base64-encoded SPKI DER (the format returned by the API), or a
+// base64-encoded raw 32-byte key.
+func parsePublicKey(s string) (ed25519.PublicKey, error) {
+	s = strings.TrimSpace(s)
+
+	// format names the detected encoding for error messages.
+	var format string
+	var der []byte
+	if strings.HasPrefix(s, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(s))
+		if block == nil {
+			return nil, &Error{Code: "ERR_INVALID_KEY", Message: "failed to decode PEM public key: no PEM block found"}
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, &Error{Code: "ERR_INVALID_KEY", Message: "failed to decode PEM public key: unexpected block type " + strconv.Quote(block.Type)}
+		}
+		format = "PEM"
+		der = block.Bytes
+	} else {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, &Error{Code: "ERR_INVALID_KEY", Message: "failed to decode public key as PEM or base64: " + err.Error()}
+		}
+		if len(b) == ed25519.PublicKeySize {
+			return ed25519.PublicKey(b), nil
+		}
+		format = fmt.Sprintf("base64 SPKI (decoded to %d bytes, not a raw %d-byte key)", len(b), ed25519.PublicKeySize)
+		der = b
 	}
 
 	pub, err := x509.ParsePKIXPublicKey(der)
 	if err != nil {
-		return nil, &Error{Code: "ERR_INVALID_KEY", Message: "failed to parse SPKI public key: " + err.Error()}
+		return nil, &Error{Code: "ERR_INVALID_KEY", Message: "failed to parse " + format + " public key: " + err.Error()}
 	}
 
 	edPub, ok := pub.(ed25519.PublicKey)
@@ -107,30 +247,129 @@ func parsePublicKey(b64 string) (ed25519.PublicKey, error) {
 	return edPub, nil
 }
 
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying the given
+// correlation id.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation id stored in ctx by
+// Middleware or ContextWithCorrelationID, or "" if none is set.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
 // Middleware returns an http.Handler that verifies webhook signatures.
 // Requests with valid signatures are passed to the next handler.
 // Invalid requests receive a 401 Unauthorized response.
+//
+// Every request is assigned a correlation id, taken from the
+// X-Correlation-ID request header, an id already in the request context,
+// or generated. It is returned in the X-Correlation-ID response header and
+// is available to the next handler via CorrelationIDFromContext.
 func (v *WebhookVerifier) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestCorrelationID(r)
+		w.Header().Set(HeaderCorrelationID, id)
+
 		if err := v.Verify(r); err != nil {
+			if v.logger != nil {
+				code := ""
+				var apiErr *Error
+				if errors.As(err, &apiErr) {
+					code = apiErr.Code
+				}
+				v.logger.LogAttrs(r.Context(), slog.LevelWarn, "webhook verification failed",
+					slog.String("code", code),
+					slog.String("correlation_id", id),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+				)
+			}
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(ContextWithCorrelationID(r.Context(), id)))
 	})
 }
 
+// maxCorrelationIDLen bounds correlation ids accepted from request headers.
+const maxCorrelationIDLen = 128
+
+// requestCorrelationID returns the correlation id for r, generating one when
+// neither the request header nor the context supplies it.
+func requestCorrelationID(r *http.Request) string {
+	if id := r.Header.Get(HeaderCorrelationID); id != "" && len(id) <= maxCorrelationIDLen {
+		return id
+	}
+	if id := CorrelationIDFromContext(r.Context()); id != "" {
+		return id
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 // Verify checks the signature and timestamp of a webhook request.
 // Returns nil if valid, or an error describing the failure.
+//
+// The signature header may carry a comma-separated list of signatures, as
+// during a change of signing scheme; the request is accepted if any of
+// them verifies.
+//
+// The body is read in full and replaced, so it can be read again by the
+// caller.
 func (v *WebhookVerifier) Verify(r *http.Request) error {
-	timestamp := r.Header.Get(HeaderSignatureTimestamp)
+	timestamp := r.Header.Get(v.timestampHeader)
 	if timestamp == "" {
-		return &Error{Code: "ERR_MISSING_TIMESTAMP", Message: "missing X-Signature-Timestamp header"}
+		return &Error{Code: "ERR_MISSING_TIMESTAMP", Message: "missing " + v.timestampHeader + " header"}
+	}
+
+	signature := r.Header.Get(v.signatureHeader)
+	if signature == "" {
+		return &Error{Code: "ERR_MISSING_SIGNATURE", Message: "missing " + v.signatureHeader + " header"}
+	}
+
+	lr := io.LimitReader(r.Body, v.maxBodyBytes+1)
+	body, err := io.ReadAll(lr)
+	if err != nil {
+		return &Error{Code: "ERR_READ_BODY", Message: "failed to read request body"}
 	}
+	if int64(len(body)) > v.maxBodyBytes {
+		return &Error{Code: "ERR_BODY_TOO_LARGE", Message: "request body exceeds maximum allowed size"}
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return v.verifyBytes(r.Header.Get(HeaderSignatureKeyID), timestamp, signature, body)
+}
+
+// VerifyBytes checks a webhook signature given the values of the timestamp
+// and signature headers and the raw request body. It performs the same checks
+// as Verify, for servers that do not use net/http, such as Lambda proxy
+// events or messages taken from a queue.
+//
+// Example:
+//
+//	err := verifier.VerifyBytes(
+//	    event.Headers["x-signature-timestamp"],
+//	    event.Headers["x-signature-ed25519"],
+//	    []byte(event.Body),
+//	)
+func (v *WebhookVerifier) VerifyBytes(timestamp, signature string, body []byte) error {
+	return v.verifyBytes("", timestamp, signature, body)
+}
 
-	signature := r.Header.Get(HeaderSignatureEd25519)
+// verifyBytes implements VerifyBytes. keyID is the optional key hint passed
+// to verifyAny.
+func (v *WebhookVerifier) verifyBytes(keyID, timestamp, signature string, body []byte) error {
+	if timestamp == "" {
+		return &Error{Code: "ERR_MISSING_TIMESTAMP", Message: "missing " + v.timestampHeader + " header"}
+	}
 	if signature == "" {
-		return &Error{Code: "ERR_MISSING_SIGNATURE", Message: "missing X-Signature-Ed25519 header"}
+		return &Error{Code: "ERR_MISSING_SIGNATURE", Message: "missing " + v.signatureHeader + " header"}
 	}
 
 	ts, err := strconv.ParseInt(timestamp, 10, 64)
@@ -138,7 +377,7 @@ func (v *WebhookVerifier) Verify(r *http.Request) error {
 		return &Error{Code: "ERR_INVALID_TIMESTAMP", Message: "invalid timestamp format"}
 	}
 
-	now := time.Now().Unix()
+	now := v.now().Unix()
 	diff := now - ts
 	if diff < 0 {
 		diff = -diff
@@ -147,31 +386,122 @@ func (v *WebhookVerifier) Verify(r *http.Request) error {
 		return &Error{Code: "ERR_TIMESTAMP_EXPIRED", Message: "timestamp outside valid range"}
 	}
 
-	sig, err := hex.DecodeString(signature)
+	sigs, err := parseSignatures(signature)
 	if err != nil {
-		return &Error{Code: "ERR_INVALID_SIGNATURE", Message: "invalid signature hex encoding"}
-	}
-	if len(sig) != ed25519.SignatureSize {
-		return &Error{Code: "ERR_INVALID_SIGNATURE", Message: "invalid signature length"}
+		return err
 	}
 
-	lr := io.LimitReader(r.Body, v.maxBodyBytes+1)
-	body, err := io.ReadAll(lr)
-	if err != nil {
-		return &Error{Code: "ERR_READ_BODY", Message: "failed to read request body"}
-	}
 	if int64(len(body)) > v.maxBodyBytes {
 		return &Error{Code: "ERR_BODY_TOO_LARGE", Message: "request body exceeds maximum allowed size"}
 	}
-	r.Body = io.NopCloser(bytes.NewReader(body))
 
 	message := append([]byte(timestamp), body...)
 
-	for _, pub := range v.publicKeys {
-		if ed25519.Verify(pub, message, sig) {
-			return nil
+	keys := v.currentKeys()
+	sig := keys.verifyAnyOf(v.verifyKey, keyID, message, sigs)
+	if sig == nil {
+		// The key may have been rotated since the last refresh.
+		if keys = v.forceRefresh(keys); keys != nil {
+			sig = keys.verifyAnyOf(v.verifyKey, keyID, message, sigs)
+		}
+		if sig == nil {
+			return &Error{Code: "ERR_SIGNATURE_INVALID", Message: "signature verification failed"}
+		}
+	}
+
+	// Only record verified requests, so forged ones cannot fill the cache.
+	// Once the timestamp leaves the skew window the request is rejected
+	// anyway, so the entry need not outlive it. The key covers only signed
+	// data: the message rather than sig, since a header may carry one valid
+	// signature per key during rotation.
+	if v.replayCache != nil {
+		digest := sha256.Sum256(message)
+		exp := time.Unix(ts, 0).Add(v.maxClockSkew)
+		if v.replayCache.Seen(hex.EncodeToString(digest[:]), exp) {
+			return &Error{Code: "ERR_REPLAY_DETECTED", Message: "webhook has already been received"}
 		}
 	}
 
-	return &Error{Code: "ERR_SIGNATURE_INVALID", Message: "signature verification failed"}
+	return nil
+}
+
+// maxSignatures bounds how many signatures one header may carry, so a
+// forged request cannot demand unbounded verification work.
+const maxSignatures = 8
+
+// parseSignatures decodes a signature header holding one hex-encoded
+// signature or a comma-separated list of them. Entries that are not valid
+// Ed25519 signatures are skipped, so a list may mix in values for schemes
+// this verifier does not know; if no entry is valid, the error describes
+// the first.
+func parseSignatures(header string) ([][]byte, error) {
+	values := strings.Split(header, ",")
+	if len(values) > maxSignatures {
+		return nil, &Error{Code: "ERR_INVALID_SIGNATURE", Message: "too many signatures"}
+	}
+
+	var sigs [][]byte
+	var firstErr error
+	for _, value := range values {
+		sig, err := hex.DecodeString(strings.TrimSpace(value))
+		switch {
+		case err != nil:
+			err = &Error{Code: "ERR_INVALID_SIGNATURE", Message: "invalid signature hex encoding"}
+		case len(sig) != ed25519.SignatureSize:
+			err = &Error{Code: "ERR_INVALID_SIGNATURE", Message: "invalid signature length"}
+		default:
+			sigs = append(sigs, sig)
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(sigs) == 0 {
+		return nil, firstErr
+	}
+	return sigs, nil
+}
+
+// verifyAnyOf returns the first of sigs that verifies against one of the
+// keys, or nil if none does.
+func (ks *verifierKeys) verifyAnyOf(verify func(pub ed25519.PublicKey, message, sig []byte) bool, keyID string, message []byte, sigs [][]byte) []byte {
+	for _, sig := range sigs {
+		if ks.verifyAny(verify, keyID, message, sig) {
+			return sig
+		}
+	}
+	return nil
+}
+
+// verifyAny checks sig against every key using verify. The key named by
+// keyID, if any, is tried first, then the key that last succeeded, then the
+// rest in order, so the common case costs a single Ed25519 verification.
+func (ks *verifierKeys) verifyAny(verify func(pub ed25519.PublicKey, message, sig []byte) bool, keyID string, message, sig []byte) bool {
+	hinted := -1
+	if keyID != "" {
+		if i, ok := ks.index[keyID]; ok {
+			hinted = i
+			if verify(ks.publicKeys[i], message, sig) {
+				ks.lastKey.Store(int32(i))
+				return true
+			}
+		}
+	}
+
+	last := int(ks.lastKey.Load())
+	if last != hinted && verify(ks.publicKeys[last], message, sig) {
+		return true
+	}
+
+	for i, pub := range ks.publicKeys {
+		if i == hinted || i == last {
+			continue
+		}
+		if verify(pub, message, sig) {
+			ks.lastKey.Store(int32(i))
+			return true
+		}
+	}
+	return false
 }
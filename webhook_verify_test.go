@@ -6,11 +6,15 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -50,8 +54,8 @@ func TestNewWebhookVerifier(t *testing.T) {
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
-		if len(v.publicKeys) != 1 {
-			t.Errorf("expected 1 public key, got %d", len(v.publicKeys))
+		if len(v.keys.Load().publicKeys) != 1 {
+			t.Errorf("expected 1 public key, got %d", len(v.keys.Load().publicKeys))
 		}
 	})
 
@@ -62,6 +66,62 @@ func TestNewWebhookVerifier(t *testing.T) {
 		}
 	})
 
+	t.Run("accepts key formats", func(t *testing.T) {
+		priv, b64 := generateTestKey(t)
+		der, _ := base64.StdEncoding.DecodeString(b64)
+		pub := priv.Public().(ed25519.PublicKey)
+
+		tests := []struct {
+			name string
+			key  string
+		}{
+			{"base64 SPKI", b64},
+			{"PEM", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))},
+			{"PEM with surrounding whitespace", "\n  " + string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))},
+			{"raw 32-byte", base64.StdEncoding.EncodeToString(pub)},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: tt.key}})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !v.keys.Load().publicKeys[0].Equal(pub) {
+					t.Error("parsed key does not match")
+				}
+			})
+		}
+	})
+
+	t.Run("rejects unparseable keys", func(t *testing.T) {
+		_, b64 := generateTestKey(t)
+		der, _ := base64.StdEncoding.DecodeString(b64)
+
+		tests := []struct {
+			name    string
+			key     string
+			wantMsg string
+		}{
+			{"invalid base64", "not-valid-base64!!!", "PEM or base64"},
+			{"wrong length", base64.StdEncoding.EncodeToString(make([]byte, 16)), "base64 SPKI"},
+			{"PEM without block", "-----BEGIN PUBLIC KEY-----\nnope", "PEM"},
+			{"PEM private key", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), "unexpected block type"},
+			{"PEM garbage", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("garbage")})), "failed to parse PEM"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: tt.key}})
+				var xerr *Error
+				if !errors.As(err, &xerr) || xerr.Code != "ERR_INVALID_KEY" {
+					t.Fatalf("error = %v, want ERR_INVALID_KEY", err)
+				}
+				if !strings.Contains(xerr.Message, tt.wantMsg) {
+					t.Errorf("Message = %q, want it to mention %q", xerr.Message, tt.wantMsg)
+				}
+			})
+		}
+	})
+
 	t.Run("applies max clock skew option", func(t *testing.T) {
 		_, b64 := generateTestKey(t)
 		v, err := NewWebhookVerifier(
@@ -235,6 +295,118 @@ func TestWebhookVerifier_Verify(t *testing.T) {
 	})
 }
 
+func TestWebhookVerifier_VerifyBytes(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}}, WithMaxBodyBytes(64))
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	body := []byte(`{"event":"ping"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+		wantCode  string
+	}{
+		{
+			name:      "valid signature",
+			timestamp: now,
+			signature: signRequest(priv, now, body),
+			body:      body,
+		},
+		{
+			name:      "missing timestamp",
+			signature: "abc123",
+			body:      body,
+			wantCode:  "ERR_MISSING_TIMESTAMP",
+		},
+		{
+			name:      "missing signature",
+			timestamp: now,
+			body:      body,
+			wantCode:  "ERR_MISSING_SIGNATURE",
+		},
+		{
+			name:      "invalid timestamp format",
+			timestamp: "not-a-number",
+			signature: "abc123",
+			body:      body,
+			wantCode:  "ERR_INVALID_TIMESTAMP",
+		},
+		{
+			name:      "expired timestamp",
+			timestamp: old,
+			signature: signRequest(priv, old, body),
+			body:      body,
+			wantCode:  "ERR_TIMESTAMP_EXPIRED",
+		},
+		{
+			name:      "future timestamp",
+			timestamp: future,
+			signature: signRequest(priv, future, body),
+			body:      body,
+			wantCode:  "ERR_TIMESTAMP_EXPIRED",
+		},
+		{
+			name:      "invalid signature hex",
+			timestamp: now,
+			signature: "not-hex!!!",
+			body:      body,
+			wantCode:  "ERR_INVALID_SIGNATURE",
+		},
+		{
+			name:      "invalid signature length",
+			timestamp: now,
+			signature: hex.EncodeToString(make([]byte, 32)),
+			body:      body,
+			wantCode:  "ERR_INVALID_SIGNATURE",
+		},
+		{
+			name:      "wrong signature",
+			timestamp: now,
+			signature: hex.EncodeToString(make([]byte, ed25519.SignatureSize)),
+			body:      body,
+			wantCode:  "ERR_SIGNATURE_INVALID",
+		},
+		{
+			name:      "tampered body",
+			timestamp: now,
+			signature: signRequest(priv, now, body),
+			body:      []byte(`{"event":"pong"}`),
+			wantCode:  "ERR_SIGNATURE_INVALID",
+		},
+		{
+			name:      "body too large",
+			timestamp: now,
+			signature: signRequest(priv, now, bytes.Repeat([]byte("x"), 65)),
+			body:      bytes.Repeat([]byte("x"), 65),
+			wantCode:  "ERR_BODY_TOO_LARGE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.VerifyBytes(tt.timestamp, tt.signature, tt.body)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var xerr *Error
+			if !errors.As(err, &xerr) || xerr.Code != tt.wantCode {
+				t.Errorf("error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
 func TestWebhookVerifier_MultipleKeys(t *testing.T) {
 	priv1, b64_1 := generateTestKey(t)
 	_, b64_2 := generateTestKey(t)
@@ -260,6 +432,153 @@ func TestWebhookVerifier_MultipleKeys(t *testing.T) {
 	}
 }
 
+func TestWebhookVerifier_KeyOrdering(t *testing.T) {
+	privs := make([]ed25519.PrivateKey, 3)
+	keys := make([]WebhookSigningKey, 3)
+	for i := range keys {
+		privs[i], keys[i].PublicKey = generateTestKey(t)
+	}
+
+	newVerifier := func(t *testing.T) (*WebhookVerifier, *[]int) {
+		t.Helper()
+		v, err := NewWebhookVerifier(keys)
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+		var tried []int
+		v.verifyKey = func(pub ed25519.PublicKey, message, sig []byte) bool {
+			for i, p := range v.keys.Load().publicKeys {
+				if p.Equal(pub) {
+					tried = append(tried, i)
+				}
+			}
+			return ed25519.Verify(pub, message, sig)
+		}
+		return v, &tried
+	}
+
+	newRequest := func(priv ed25519.PrivateKey, keyID string) *http.Request {
+		body := []byte(`{"event":"ping"}`)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+		req.Header.Set(HeaderSignatureTimestamp, timestamp)
+		req.Header.Set(HeaderSignatureEd25519, signRequest(priv, timestamp, body))
+		if keyID != "" {
+			req.Header.Set(HeaderSignatureKeyID, keyID)
+		}
+		return req
+	}
+
+	t.Run("hinted key is tried first", func(t *testing.T) {
+		v, tried := newVerifier(t)
+		if err := v.Verify(newRequest(privs[2], keys[2].PublicKey)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*tried) != 1 || (*tried)[0] != 2 {
+			t.Errorf("tried keys = %v, want [2]", *tried)
+		}
+	})
+
+	t.Run("last successful key is tried first", func(t *testing.T) {
+		v, tried := newVerifier(t)
+		if err := v.Verify(newRequest(privs[1], "")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		*tried = nil
+		if err := v.Verify(newRequest(privs[1], "")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*tried) != 1 || (*tried)[0] != 1 {
+			t.Errorf("tried keys = %v, want [1]", *tried)
+		}
+	})
+
+	t.Run("wrong hint still tries every key", func(t *testing.T) {
+		v, tried := newVerifier(t)
+		if err := v.Verify(newRequest(privs[2], keys[1].PublicKey)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []int{1, 0, 2}
+		if len(*tried) != len(want) {
+			t.Fatalf("tried keys = %v, want %v", *tried, want)
+		}
+		for i := range want {
+			if (*tried)[i] != want[i] {
+				t.Errorf("tried keys = %v, want %v", *tried, want)
+				break
+			}
+		}
+	})
+
+	t.Run("unknown hint is ignored", func(t *testing.T) {
+		v, _ := newVerifier(t)
+		if err := v.Verify(newRequest(privs[0], "not-a-key")); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("no key matches", func(t *testing.T) {
+		v, tried := newVerifier(t)
+		other, _ := generateTestKey(t)
+		if err := v.Verify(newRequest(other, keys[0].PublicKey)); err == nil {
+			t.Error("expected error for unknown signer")
+		}
+		if len(*tried) != len(keys) {
+			t.Errorf("tried %d keys, want %d", len(*tried), len(keys))
+		}
+	})
+}
+
+func BenchmarkWebhookVerifier_Verify(b *testing.B) {
+	const numKeys = 10
+	keys := make([]WebhookSigningKey, numKeys)
+	var priv ed25519.PrivateKey
+	for i := range keys {
+		pub, p, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			b.Fatal(err)
+		}
+		keys[i].PublicKey = base64.StdEncoding.EncodeToString(der)
+		priv = p
+	}
+	// priv signs with the last key, the worst case for a linear scan.
+
+	body := []byte(`{"event":"ping"}`)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	sig := signRequest(priv, timestamp, body)
+
+	for _, bc := range []struct {
+		name  string
+		keyID string
+	}{
+		{"no hint", ""},
+		{"hinted", keys[numKeys-1].PublicKey},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			v, err := NewWebhookVerifier(keys)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+				req.Header.Set(HeaderSignatureTimestamp, timestamp)
+				req.Header.Set(HeaderSignatureEd25519, sig)
+				if bc.keyID != "" {
+					req.Header.Set(HeaderSignatureKeyID, bc.keyID)
+				}
+				if err := v.Verify(req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func TestWebhookVerifier_BodySizeLimit(t *testing.T) {
 	priv, b64 := generateTestKey(t)
 	maxBytes := int64(64)
@@ -366,3 +685,212 @@ func TestWebhookVerifier_Middleware(t *testing.T) {
 		}
 	})
 }
+
+func TestWebhookVerifier_MiddlewareLogging(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}}, WithVerifierLogger(logger))
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	var gotID string
+	mw := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = CorrelationIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("rejection logs code and echoes header correlation id", func(t *testing.T) {
+		logs.Reset()
+		body := []byte(`{"eventId":"evt-1","type":"ping"}`)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+		req.Header.Set(HeaderSignatureTimestamp, timestamp)
+		req.Header.Set(HeaderSignatureEd25519, hex.EncodeToString(make([]byte, ed25519.SignatureSize)))
+		req.Header.Set(HeaderCorrelationID, "corr-123")
+
+		rr := httptest.NewRecorder()
+		mw.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rr.Code)
+		}
+		if got := rr.Header().Get(HeaderCorrelationID); got != "corr-123" {
+			t.Errorf("%s = %q, want 'corr-123'", HeaderCorrelationID, got)
+		}
+
+		var entry map[string]any
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("decoding log entry %q: %v", logs.String(), err)
+		}
+		if entry["code"] != "ERR_SIGNATURE_INVALID" {
+			t.Errorf("code = %v, want 'ERR_SIGNATURE_INVALID'", entry["code"])
+		}
+		if entry["correlation_id"] != "corr-123" {
+			t.Errorf("correlation_id = %v, want 'corr-123'", entry["correlation_id"])
+		}
+		if strings.Contains(logs.String(), "evt-1") || strings.Contains(logs.String(), req.Header.Get(HeaderSignatureEd25519)) {
+			t.Errorf("log leaked body or signature: %s", logs.String())
+		}
+	})
+
+	t.Run("rejection generates correlation id when absent", func(t *testing.T) {
+		logs.Reset()
+		req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+
+		rr := httptest.NewRecorder()
+		mw.ServeHTTP(rr, req)
+
+		id := rr.Header().Get(HeaderCorrelationID)
+		if id == "" {
+			t.Fatal("expected generated correlation id header")
+		}
+		if !strings.Contains(logs.String(), `"code":"ERR_MISSING_TIMESTAMP"`) {
+			t.Errorf("log missing code: %s", logs.String())
+		}
+		if !strings.Contains(logs.String(), id) {
+			t.Errorf("log missing correlation id %q: %s", id, logs.String())
+		}
+	})
+
+	t.Run("valid request exposes correlation id in context", func(t *testing.T) {
+		logs.Reset()
+		body := []byte(`{"eventId":"evt-2","type":"ping"}`)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+		req.Header.Set(HeaderSignatureTimestamp, timestamp)
+		req.Header.Set(HeaderSignatureEd25519, signRequest(priv, timestamp, body))
+		req.Header.Set(HeaderCorrelationID, "corr-456")
+
+		rr := httptest.NewRecorder()
+		mw.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+		if gotID != "corr-456" {
+			t.Errorf("CorrelationIDFromContext() = %q, want 'corr-456'", gotID)
+		}
+		if logs.Len() != 0 {
+			t.Errorf("expected no log output, got %s", logs.String())
+		}
+	})
+}
+
+func TestWebhookVerifier_SignatureHeaders(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	body := []byte(`{"eventId":"evt-1","type":"ping"}`)
+
+	newRequest := func(tsHeader, sigHeader string) *http.Request {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+		req.Header.Set(tsHeader, timestamp)
+		req.Header.Set(sigHeader, signRequest(priv, timestamp, body))
+		return req
+	}
+
+	t.Run("custom header names", func(t *testing.T) {
+		v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}},
+			WithSignatureHeaders("X-Proxy-Timestamp", "X-Proxy-Signature"))
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+
+		if err := v.Verify(newRequest("X-Proxy-Timestamp", "X-Proxy-Signature")); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		err = v.Verify(newRequest(HeaderSignatureTimestamp, HeaderSignatureEd25519))
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Code != "ERR_MISSING_TIMESTAMP" {
+			t.Errorf("expected ERR_MISSING_TIMESTAMP with default headers, got %v", err)
+		}
+	})
+
+	t.Run("single header options", func(t *testing.T) {
+		v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}},
+			WithTimestampHeader("X-Proxy-Timestamp"), WithSignatureHeader("X-Proxy-Signature"))
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+
+		if err := v.Verify(newRequest("X-Proxy-Timestamp", "X-Proxy-Signature")); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("defaults still work", func(t *testing.T) {
+		v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}})
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+
+		if err := v.Verify(newRequest(HeaderSignatureTimestamp, HeaderSignatureEd25519)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("empty names keep defaults", func(t *testing.T) {
+		v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}}, WithSignatureHeaders("", ""))
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+
+		if err := v.Verify(newRequest(HeaderSignatureTimestamp, HeaderSignatureEd25519)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestWebhookVerifier_MultipleSignatures(t *testing.T) {
+	priv, b64 := generateTestKey(t)
+	other, _ := generateTestKey(t)
+	body := []byte(`{"eventId":"evt-1","type":"ping"}`)
+
+	v, err := NewWebhookVerifier([]WebhookSigningKey{{PublicKey: b64}})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	valid := signRequest(priv, timestamp, body)
+	wrong := signRequest(other, timestamp, body)
+
+	tests := []struct {
+		name     string
+		header   string
+		wantCode string
+	}{
+		{name: "second of two valid", header: wrong + "," + valid},
+		{name: "spaces around values", header: wrong + " , " + valid},
+		{name: "unknown scheme skipped", header: "v2=opaque," + valid},
+		{name: "none valid", header: wrong + "," + wrong, wantCode: "ERR_SIGNATURE_INVALID"},
+		{name: "all malformed", header: "zz,abcd", wantCode: "ERR_INVALID_SIGNATURE"},
+		{name: "too many", header: strings.Repeat(wrong+",", maxSignatures) + valid, wantCode: "ERR_INVALID_SIGNATURE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+			req.Header.Set(HeaderSignatureTimestamp, timestamp)
+			req.Header.Set(HeaderSignatureEd25519, tt.header)
+
+			err := v.Verify(req)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.wantCode {
+				t.Errorf("error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -3,7 +3,9 @@ package xbow
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"iter"
+	"net/url"
 
 	"github.com/rsclarke/xbow/internal/api"
 )
@@ -28,6 +30,54 @@ type UpdateWebhookRequest struct {
 	Events     []WebhookEventType `json:"events,omitempty"`
 }
 
+// Validate checks r the way the API would, so a bad subscription is
+// reported before any request is made: TargetURL must be an absolute https
+// URL, and Events must name at least one documented event type. The error
+// is an *Error with code ERR_INVALID_REQUEST naming the offending field.
+func (r *CreateWebhookRequest) Validate() error {
+	if err := validateWebhookTargetURL(r.TargetURL); err != nil {
+		return err
+	}
+	if len(r.Events) == 0 {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: "events must name at least one event type"}
+	}
+	return validateWebhookEvents(r.Events)
+}
+
+// Validate checks the fields set in r as CreateWebhookRequest.Validate
+// does. Unset fields are not checked.
+func (r *UpdateWebhookRequest) Validate() error {
+	if r.TargetURL != nil {
+		if err := validateWebhookTargetURL(*r.TargetURL); err != nil {
+			return err
+		}
+	}
+	return validateWebhookEvents(r.Events)
+}
+
+func validateWebhookTargetURL(target string) error {
+	if target == "" {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: "targetUrl is required"}
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return &Error{Code: "ERR_INVALID_REQUEST", Message: fmt.Sprintf("targetUrl must be an absolute https URL, got %q", target)}
+	}
+	return nil
+}
+
+func validateWebhookEvents(events []WebhookEventType) error {
+	for i, e := range events {
+		if !e.IsValid() {
+			return &Error{
+				Code:    "ERR_INVALID_REQUEST",
+				Message: fmt.Sprintf("events[%d]: unknown webhook event type %q (valid: %s)", i, e, joinValues(WebhookEventTypeValues())),
+			}
+		}
+	}
+	return nil
+}
+
 // Get retrieves a webhook subscription by ID.
 func (s *WebhooksService) Get(ctx context.Context, id string) (*Webhook, error) {
 	if id == "" {
@@ -48,9 +98,10 @@ func (s *WebhooksService) Get(ctx context.Context, id string) (*Webhook, error)
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1WebhooksWebhookID(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return webhookFromGetResponse(resp), nil
@@ -61,6 +112,11 @@ func (s *WebhooksService) Update(ctx context.Context, id string, req *UpdateWebh
 	if id == "" {
 		return nil, &Error{Code: "ERR_INVALID_PARAM", Message: "webhook id is required"}
 	}
+	if req != nil {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
@@ -77,13 +133,7 @@ func (s *WebhooksService) Update(ctx context.Context, id string, req *UpdateWebh
 			body.TargetURL = req.TargetURL
 		}
 		if len(req.Events) > 0 {
-			events := make(api.PatchAPIV1WebhooksWebhookIDBody_Events, 0, len(req.Events))
-			for _, e := range req.Events {
-				item := api.PatchAPIV1WebhooksWebhookIDBody_Events_Item{}
-				item.PatchAPIV1WebhooksWebhookIDBody_Events_AnyOf = &api.PatchAPIV1WebhooksWebhookIDBody_Events_AnyOf{}
-				_ = item.PatchAPIV1WebhooksWebhookIDBody_Events_AnyOf.FromString(string(e))
-				events = append(events, item)
-			}
+			events := convertWebhookEventsToPatchBody(req.Events)
 			body.Events = &events
 		}
 	}
@@ -98,15 +148,17 @@ func (s *WebhooksService) Update(ctx context.Context, id string, req *UpdateWebh
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PatchAPIV1WebhooksWebhookID(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return webhookFromPatchResponse(resp), nil
 }
 
-// Delete deletes a webhook subscription.
+// Delete deletes a webhook subscription. Any 2xx response is success,
+// whatever its body.
 func (s *WebhooksService) Delete(ctx context.Context, id string) error {
 	if id == "" {
 		return &Error{Code: "ERR_INVALID_PARAM", Message: "webhook id is required"}
@@ -126,15 +178,13 @@ func (s *WebhooksService) Delete(ctx context.Context, id string) error {
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	_, err = s.client.raw.DeleteAPIV1WebhooksWebhookID(ctx, opts, auth)
-	if err != nil {
-		return wrapError(err)
-	}
-
-	return nil
+	return rid.noContentError(err)
 }
 
 // Ping sends a ping event to a webhook subscription to test connectivity.
+// Any 2xx response is success, whatever its body.
 func (s *WebhooksService) Ping(ctx context.Context, id string) error {
 	if id == "" {
 		return &Error{Code: "ERR_INVALID_PARAM", Message: "webhook id is required"}
@@ -154,12 +204,9 @@ func (s *WebhooksService) Ping(ctx context.Context, id string) error {
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	_, err = s.client.raw.PostAPIV1WebhooksWebhookIDPing(ctx, opts, auth)
-	if err != nil {
-		return wrapError(err)
-	}
-
-	return nil
+	return rid.noContentError(err)
 }
 
 // ListByOrganization returns a page of webhook subscriptions for an organization.
@@ -192,9 +239,10 @@ func (s *WebhooksService) ListByOrganization(ctx context.Context, organizationID
 		}
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1OrganizationsOrganizationIDWebhooks(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return webhooksPageFromResponse(resp), nil
@@ -223,20 +271,15 @@ func (s *WebhooksService) Create(ctx context.Context, organizationID string, req
 	if req == nil {
 		return nil, &Error{Code: "ERR_INVALID_PARAM", Message: "request is required"}
 	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
 
 	auth, err := s.client.orgAuthEditor()
 	if err != nil {
 		return nil, err
 	}
 
-	events := make(api.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events, 0, len(req.Events))
-	for _, e := range req.Events {
-		item := api.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_Item{}
-		item.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_AnyOf = &api.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_AnyOf{}
-		_ = item.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_AnyOf.FromString(string(e))
-		events = append(events, item)
-	}
-
 	opts := &api.PostAPIV1OrganizationsOrganizationIDWebhooksRequestOptions{
 		PathParams: &api.PostAPIV1OrganizationsOrganizationIDWebhooksPath{
 			OrganizationID: organizationID,
@@ -244,16 +287,17 @@ func (s *WebhooksService) Create(ctx context.Context, organizationID string, req
 		Body: &api.PostAPIV1OrganizationsOrganizationIDWebhooksBody{
 			APIVersion: api.PostAPIV1OrganizationsOrganizationIDWebhooksBodyAPIVersion(req.APIVersion),
 			TargetURL:  req.TargetURL,
-			Events:     events,
+			Events:     convertWebhookEventsToCreateBody(req.Events),
 		},
 		Header: &api.PostAPIV1OrganizationsOrganizationIDWebhooksHeaders{
 			XXBOWAPIVersion: api.PostAPIV1OrganizationsOrganizationIDWebhooksHeaderXXBOWAPIVersionN20260201,
 		},
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.PostAPIV1OrganizationsOrganizationIDWebhooks(ctx, opts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return webhookFromCreateResponse(resp), nil
@@ -289,9 +333,10 @@ func (s *WebhooksService) ListDeliveries(ctx context.Context, webhookID string,
 		}
 	}
 
+	ctx, rid := recordRequestID(ctx)
 	resp, err := s.client.raw.GetAPIV1WebhooksWebhookIDDeliveries(ctx, reqOpts, auth)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, rid.wrapError(err)
 	}
 
 	return deliveriesPageFromResponse(resp), nil
@@ -312,6 +357,20 @@ func (s *WebhooksService) AllDeliveries(ctx context.Context, webhookID string, o
 	})
 }
 
+// FilterWebhooksByEvent returns the webhooks that receive the given event,
+// either because they subscribe to it directly or because they subscribe to
+// all events with the "*" wildcard. The API has no server-side event filter,
+// so this is applied to already-fetched items.
+func FilterWebhooksByEvent(items []WebhookListItem, event WebhookEventType) []WebhookListItem {
+	var result []WebhookListItem
+	for _, item := range items {
+		if WebhookEventSet(item.Events).Contains(event) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // Conversion functions from generated types to domain types
 
 func webhookFromGetResponse(r *api.GetAPIV1WebhooksWebhookIDResponse) *Webhook {
@@ -419,6 +478,8 @@ func deliveriesPageFromResponse(r *api.GetAPIV1WebhooksWebhookIDDeliveriesRespon
 
 // convertWebhookEvents is a generic adapter that converts webhook event items
 // from any generated response type into domain WebhookEventType values.
+// Types the SDK does not know are kept as they are; only items with no
+// string value are skipped.
 func convertWebhookEvents[Item any](items []Item, getAnyOf func(Item) rawUnion) []WebhookEventType {
 	result := make([]WebhookEventType, 0, len(items))
 	for _, item := range items {
@@ -434,3 +495,29 @@ func convertWebhookEvents[Item any](items []Item, getAnyOf func(Item) rawUnion)
 	}
 	return result
 }
+
+// convertWebhookEventsToCreateBody converts event types to the generated
+// create body's items. Every value is sent as is, known or not.
+func convertWebhookEventsToCreateBody(events []WebhookEventType) api.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events {
+	result := make(api.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events, 0, len(events))
+	for _, e := range events {
+		item := api.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_Item{}
+		item.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_AnyOf = &api.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_AnyOf{}
+		_ = item.PostAPIV1OrganizationsOrganizationIDWebhooksBody_Events_AnyOf.FromString(string(e))
+		result = append(result, item)
+	}
+	return result
+}
+
+// convertWebhookEventsToPatchBody converts event types to the generated
+// update body's items. Every value is sent as is, known or not.
+func convertWebhookEventsToPatchBody(events []WebhookEventType) api.PatchAPIV1WebhooksWebhookIDBody_Events {
+	result := make(api.PatchAPIV1WebhooksWebhookIDBody_Events, 0, len(events))
+	for _, e := range events {
+		item := api.PatchAPIV1WebhooksWebhookIDBody_Events_Item{}
+		item.PatchAPIV1WebhooksWebhookIDBody_Events_AnyOf = &api.PatchAPIV1WebhooksWebhookIDBody_Events_AnyOf{}
+		_ = item.PatchAPIV1WebhooksWebhookIDBody_Events_AnyOf.FromString(string(e))
+		result = append(result, item)
+	}
+	return result
+}
@@ -1,7 +1,10 @@
 package xbow
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"net/http"
 	"testing"
 	"time"
 
@@ -471,3 +474,183 @@ func TestWebhookDeliveryPayloadIsAny(t *testing.T) {
 		t.Errorf("Payload['type'] = %v, want 'ping'", payloadMap["type"])
 	}
 }
+
+func TestFilterWebhooksByEvent(t *testing.T) {
+	items := []WebhookListItem{
+		{ID: "wh-finding", Events: []WebhookEventType{WebhookEventTypeFindingChanged}},
+		{ID: "wh-asset", Events: []WebhookEventType{WebhookEventTypeAssetChanged, WebhookEventTypePing}},
+		{ID: "wh-all", Events: []WebhookEventType{WebhookEventTypeAll}},
+		{ID: "wh-none"},
+	}
+
+	tests := []struct {
+		name  string
+		event WebhookEventType
+		want  []string
+	}{
+		{"exact match plus wildcard", WebhookEventTypeFindingChanged, []string{"wh-finding", "wh-all"}},
+		{"match among several events", WebhookEventTypePing, []string{"wh-asset", "wh-all"}},
+		{"wildcard only", WebhookEventTypeTargetChanged, []string{"wh-all"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterWebhooksByEvent(items, tt.event)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d webhooks, want %d", len(got), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if got[i].ID != id {
+					t.Errorf("got[%d].ID = %q, want %q", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+
+	t.Run("empty input", func(t *testing.T) {
+		if got := FilterWebhooksByEvent(nil, WebhookEventTypePing); len(got) != 0 {
+			t.Errorf("got %d webhooks, want 0", len(got))
+		}
+	})
+}
+
+func TestNoContentResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"204", http.StatusNoContent, ""},
+		{"200 empty", http.StatusOK, ""},
+		{"200 with body", http.StatusOK, `{"deleted":true}`},
+		{"202 with body", http.StatusAccepted, `{"status":"queued"}`},
+	}
+	calls := map[string]func(*Client) error{
+		"Webhooks.Delete": func(c *Client) error {
+			return c.Webhooks.Delete(context.Background(), "wh-1")
+		},
+		"Webhooks.Ping": func(c *Client) error {
+			return c.Webhooks.Ping(context.Background(), "wh-1")
+		},
+		"Organizations.RevokeKey": func(c *Client) error {
+			return c.Organizations.RevokeKey(context.Background(), "key-1")
+		},
+	}
+
+	for method, call := range calls {
+		for _, tt := range tests {
+			t.Run(method+" "+tt.name, func(t *testing.T) {
+				client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tt.status)
+					_, _ = w.Write([]byte(tt.body))
+				}))
+				if err := call(client); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			})
+		}
+
+		t.Run(method+" 404", func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(HeaderRequestID, "req-1")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"code":"ERR_NOT_FOUND","error":"Not Found","message":"no such resource"}`))
+			}))
+			err := call(client)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *Error", err)
+			}
+			if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "ERR_NOT_FOUND" || apiErr.RequestID != "req-1" {
+				t.Errorf("error = %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestWebhookRequestValidation(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	events := []WebhookEventType{WebhookEventTypeFindingChanged}
+
+	createTests := []struct {
+		name string
+		req  CreateWebhookRequest
+		want string
+	}{
+		{"http URL", CreateWebhookRequest{TargetURL: "http://example.com/hook", Events: events}, `targetUrl must be an absolute https URL, got "http://example.com/hook"`},
+		{"relative URL", CreateWebhookRequest{TargetURL: "/hook", Events: events}, "targetUrl must be an absolute https URL"},
+		{"unparseable URL", CreateWebhookRequest{TargetURL: "https://exa mple.com/%zz", Events: events}, "targetUrl must be an absolute https URL"},
+		{"missing URL", CreateWebhookRequest{Events: events}, "targetUrl is required"},
+		{"no events", CreateWebhookRequest{TargetURL: "https://example.com/hook"}, "events must name at least one event type"},
+		{"unknown event", CreateWebhookRequest{TargetURL: "https://example.com/hook", Events: []WebhookEventType{WebhookEventTypePing, "finding.change"}}, `events[1]: unknown webhook event type "finding.change"`},
+	}
+	for _, tt := range createTests {
+		t.Run("create "+tt.name, func(t *testing.T) {
+			_, err := client.Webhooks.Create(context.Background(), "org-1", &tt.req)
+			checkValidateError(t, err, tt.want)
+		})
+	}
+
+	insecure := "http://example.com/hook"
+	updateTests := []struct {
+		name string
+		req  UpdateWebhookRequest
+		want string
+	}{
+		{"http URL", UpdateWebhookRequest{TargetURL: &insecure}, "targetUrl must be an absolute https URL"},
+		{"unknown event", UpdateWebhookRequest{Events: []WebhookEventType{"asset.deleted"}}, `events[0]: unknown webhook event type "asset.deleted"`},
+	}
+	for _, tt := range updateTests {
+		t.Run("update "+tt.name, func(t *testing.T) {
+			_, err := client.Webhooks.Update(context.Background(), "wh-1", &tt.req)
+			checkValidateError(t, err, tt.want)
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		req := CreateWebhookRequest{TargetURL: "https://example.com/hook", Events: []WebhookEventType{WebhookEventTypeAll}}
+		if err := req.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := (&UpdateWebhookRequest{}).Validate(); err != nil {
+			t.Errorf("empty update: unexpected error: %v", err)
+		}
+	})
+}
+
+func TestWebhookEventsRoundTripUnknown(t *testing.T) {
+	sent := []WebhookEventType{WebhookEventTypeFindingChanged, "target.deleted", WebhookEventTypeAll}
+
+	events, err := json.Marshal(convertWebhookEventsToCreateBody(sent))
+	if err != nil {
+		t.Fatalf("marshal create events: %v", err)
+	}
+	if string(events) != `["finding.changed","target.deleted","*"]` {
+		t.Errorf("create body events = %s", events)
+	}
+	patched, err := json.Marshal(convertWebhookEventsToPatchBody(sent))
+	if err != nil || string(patched) != string(events) {
+		t.Errorf("update body events = %s, %v; want %s", patched, err, events)
+	}
+
+	var resp api.GetAPIV1OrganizationsOrganizationIDWebhooksResponse
+	list := `{"items":[{"id":"wh-1","apiVersion":"2026-02-01","targetUrl":"https://example.com/hook","events":` + string(events) + `,"createdAt":"2026-01-02T03:04:05Z","updatedAt":"2026-01-02T03:04:05Z"}]}`
+	if err := json.Unmarshal([]byte(list), &resp); err != nil {
+		t.Fatalf("unmarshal list: %v", err)
+	}
+	got := webhooksPageFromResponse(&resp).Items[0].Events
+	if len(got) != len(sent) {
+		t.Fatalf("listed events = %v, want %v", got, sent)
+	}
+	for i := range sent {
+		if got[i] != sent[i] {
+			t.Errorf("events[%d] = %q, want %q", i, got[i], sent[i])
+		}
+		if want := i != 1; got[i].IsKnown() != want {
+			t.Errorf("%q.IsKnown() = %v, want %v", got[i], got[i].IsKnown(), want)
+		}
+	}
+}